@@ -0,0 +1,477 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+	"github.com/trytobebee/snake_go/pkg/game"
+)
+
+// Elo settings
+const (
+	eloStart = 1500.0
+	eloK     = 32.0
+)
+
+// Pairing is one scheduled game: controller A plays controller B from the given side
+type Pairing struct {
+	Index int
+	A, B  string
+	Swap  bool // B takes the first player slot
+	Seed  int64
+}
+
+// Result is the outcome of one headless game
+type Result struct {
+	Pairing
+	ScoreA, ScoreB int
+	Winner         string // "a", "b" or "draw"
+	Ticks          int
+	Scenario       game.Scenario // Curriculum start, if any
+	Survived       bool          // The first-slot snake did not crash
+	HitsTaken      int           // Fireball hits on the first-slot snake
+}
+
+// Standing aggregates one controller's results over the whole tournament
+type Standing struct {
+	Controller string  `json:"controller"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Draws      int     `json:"draws"`
+	WinRate    float64 `json:"win_rate"`
+	Elo        float64 `json:"elo"`
+	ScoreMean  float64 `json:"score_mean"`
+	ScoreStd   float64 `json:"score_std"`
+	ScoreP10   int     `json:"score_p10"`
+	ScoreP50   int     `json:"score_p50"`
+	ScoreP90   int     `json:"score_p90"`
+	ScoreMax   int     `json:"score_max"`
+	scores     []int
+}
+
+// ScenarioStanding is how one controller handled one curriculum scenario
+// from the first slot, the snake each scenario puts on the spot
+type ScenarioStanding struct {
+	Scenario       string  `json:"scenario"`
+	Controller     string  `json:"controller"`
+	Games          int     `json:"games"`
+	Survived       int     `json:"survived"`
+	SurvivalRate   float64 `json:"survival_rate"`
+	Wins           int     `json:"wins"`
+	HitsTaken      int     `json:"hits_taken"`
+	DifficultyMean float64 `json:"difficulty_mean"`
+}
+
+// Report is the JSON output of a tournament
+type Report struct {
+	Mode       string              `json:"mode"`
+	Width      int                 `json:"width"`
+	Height     int                 `json:"height"`
+	Seed       int64               `json:"seed"`
+	Games      int                 `json:"games"`
+	Curriculum string              `json:"curriculum,omitempty"`
+	Horizon    int                 `json:"horizon,omitempty"`
+	Standings  []*Standing         `json:"standings"`
+	Scenarios  []*ScenarioStanding `json:"scenarios,omitempty"`
+}
+
+func main() {
+	controllers := flag.String("controllers", "heuristic,berserker", "Comma-separated controllers to pit against each other (available: "+strings.Join(game.ControllerNames(), ", ")+", and wasm:<name> for bots loaded with -wasm)")
+	games := flag.Int("games", 100, "Games per pairing")
+	width := flag.Int("width", config.StandardWidth, "Board width")
+	height := flag.Int("height", config.StandardHeight, "Board height")
+	mode := flag.String("mode", "pvp", "Game mode: pvp (either crash ends the game) or battle (second snake respawns)")
+	speed := flag.String("speed", "mid", "Speed tier for both snakes: low, mid or high")
+	seed := flag.Int64("seed", 1, "Base seed; game i uses seed+i, which replays it unless a WASM bot plays (their move time limit depends on machine load)")
+	workers := flag.Int("workers", runtime.NumCPU(), "Games run in parallel")
+	out := flag.String("out", "json", "Output format: json or csv")
+	verbose := flag.Bool("v", false, "Keep engine logs")
+	wasmDir := flag.String("wasm", "", "Directory of WASM bots (<name>.wasm) to register as wasm:<name>")
+	curriculumSpec := flag.String("curriculum", "", "Start every game from a generated scenario: all or a comma list of "+strings.Join(game.ScenarioNames(), ", ")+", optionally with a difficulty range (e.g. head_on,corridor@0.5-1)")
+	horizon := flag.Int("horizon", 0, "End each game after this many moves of the first snake (0 plays to the end)")
+	flag.Parse()
+
+	curriculum, err := game.ParseCurriculum(*curriculumSpec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if *wasmDir != "" {
+		bots, err := game.LoadWasmBots(*wasmDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "🧩 WASM bots: %s\n", strings.Join(bots, ", "))
+	}
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	names := strings.Split(*controllers, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+		if _, err := game.NewController(names[i]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		// A neural controller without its model quietly plays the heuristic
+		if names[i] == "neural" || strings.HasPrefix(names[i], "neural:") {
+			if err := game.CheckNeural(strings.TrimPrefix(strings.TrimPrefix(names[i], "neural"), ":"), *width, *height); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s: %v\n", names[i], err)
+				os.Exit(1)
+			}
+		}
+	}
+	if len(names) < 2 {
+		fmt.Fprintln(os.Stderr, "❌ Need at least two controllers (the same name twice is fine for a mirror match)")
+		os.Exit(1)
+	}
+	if *width < config.MinBoardSize || *width > config.MaxBoardSize || *height < config.MinBoardSize || *height > config.MaxBoardSize {
+		fmt.Fprintf(os.Stderr, "❌ Board size %dx%d out of range (%d to %d)\n", *width, *height, config.MinBoardSize, config.MaxBoardSize)
+		os.Exit(1)
+	}
+	if *mode != "pvp" && *mode != "battle" {
+		fmt.Fprintf(os.Stderr, "❌ Unknown mode %q\n", *mode)
+		os.Exit(1)
+	}
+	if *workers < 1 {
+		*workers = 1
+	}
+
+	pairings := schedule(names, *games, *seed)
+	fmt.Fprintf(os.Stderr, "🏟️  Arena: %d games between %s on %dx%d (%s) with %d workers\n",
+		len(pairings), strings.Join(names, ", "), *width, *height, *mode, *workers)
+	if curriculum != nil {
+		fmt.Fprintf(os.Stderr, "🎓 Curriculum: %s\n", curriculum)
+	}
+
+	results := make([]Result, len(pairings))
+	errs := make([]error, len(pairings))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = play(pairings[i], *width, *height, *mode, *speed, curriculum, *horizon)
+			}
+		}()
+	}
+	for i := range pairings {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Game %d: %v\n", i, err)
+			os.Exit(1)
+		}
+	}
+
+	report := &Report{
+		Mode:       *mode,
+		Width:      *width,
+		Height:     *height,
+		Seed:       *seed,
+		Games:      len(results),
+		Curriculum: curriculum.String(),
+		Horizon:    *horizon,
+		Standings:  tally(names, results),
+	}
+	if curriculum != nil {
+		report.Scenarios = tallyScenarios(results)
+	}
+
+	switch *out {
+	case "csv":
+		err = writeCSV(os.Stdout, report)
+	default:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to write report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// schedule builds a round robin over every pair of controllers, alternating
+// sides so neither gets the better spawn more often
+func schedule(names []string, games int, seed int64) []Pairing {
+	var pairings []Pairing
+	for i := 0; i < len(names); i++ {
+		for j := i + 1; j < len(names); j++ {
+			for k := 0; k < games; k++ {
+				pairings = append(pairings, Pairing{
+					Index: len(pairings),
+					A:     names[i],
+					B:     names[j],
+					Swap:  k%2 == 1,
+					Seed:  seed + int64(len(pairings)),
+				})
+			}
+		}
+	}
+	return pairings
+}
+
+// play runs one game to completion (or the horizon) on a virtual clock. It
+// fails if the curriculum scenario does not fit the board.
+func play(p Pairing, width, height int, mode, speed string, curriculum *game.Curriculum, horizon int) (Result, error) {
+	g := game.NewSeededGame(width, height, p.Seed)
+	g.Headless = true
+	defer g.ReleaseControllers()
+	first, second := p.A, p.B
+	if p.Swap {
+		first, second = second, first
+	}
+	if mode == "pvp" {
+		g.SetupPVP(first, second)
+	} else {
+		g.Players[0].Name, g.Players[1].Name = first, second
+	}
+	for i, name := range []string{first, second} {
+		brain, _ := game.NewController(name)
+		if m, ok := brain.(*game.MCTSController); ok {
+			m.Budget = 0 // Rollouts only, so the seed replays the game on any machine
+		}
+		g.Players[i].Brain = brain
+		g.Players[i].Controller = name
+	}
+	res := Result{Pairing: p}
+	if curriculum != nil {
+		// Both sides of a swapped pair face the same scenario
+		seed := p.Seed
+		if p.Swap {
+			seed--
+		}
+		scenario, err := curriculum.Apply(g, seed)
+		if err != nil {
+			return res, err
+		}
+		res.Scenario = scenario
+	}
+
+	sim := game.NewSimulator(g, speed)
+	for !g.GameOver && (horizon <= 0 || sim.Moves(0) < horizon) {
+		sim.Tick()
+		res.Ticks++
+	}
+
+	s0, s1 := g.Players[0].Score, g.Players[1].Score
+	res.Survived = g.Players[0].Deaths == 0
+	res.HitsTaken = g.Players[0].HitsTaken
+	winner := "draw"
+	switch {
+	case !g.GameOver:
+		// Stopped at the horizon: the score decides
+		if s0 > s1 {
+			winner = "first"
+		} else if s1 > s0 {
+			winner = "second"
+		}
+	case g.Winner == "player":
+		winner = "first"
+	case g.Winner == "ai", g.Winner == "":
+		// Outside PVP the game only ends early when the first snake crashes
+		winner = "second"
+	}
+
+	res.ScoreA, res.ScoreB = s0, s1
+	if p.Swap {
+		res.ScoreA, res.ScoreB = s1, s0
+	}
+	switch {
+	case winner == "draw":
+		res.Winner = "draw"
+	case (winner == "first") != p.Swap:
+		res.Winner = "a"
+	default:
+		res.Winner = "b"
+	}
+	return res, nil
+}
+
+// tally aggregates the results per controller. Elo is updated game by game
+// in schedule order so the ratings do not depend on which worker finished first.
+func tally(names []string, results []Result) []*Standing {
+	byName := make(map[string]*Standing)
+	var standings []*Standing
+	for _, n := range names {
+		if _, ok := byName[n]; ok {
+			continue
+		}
+		s := &Standing{Controller: n, Elo: eloStart}
+		byName[n] = s
+		standings = append(standings, s)
+	}
+
+	for _, r := range results {
+		a, b := byName[r.A], byName[r.B]
+		if a == b {
+			// Mirror match: scores count, ratings cannot move
+			a.Games += 2
+			a.scores = append(a.scores, r.ScoreA, r.ScoreB)
+			if r.Winner == "draw" {
+				a.Draws += 2
+			} else {
+				a.Wins++
+				a.Losses++
+			}
+			continue
+		}
+
+		a.Games++
+		b.Games++
+		a.scores = append(a.scores, r.ScoreA)
+		b.scores = append(b.scores, r.ScoreB)
+
+		resultA := 0.5
+		switch r.Winner {
+		case "a":
+			a.Wins++
+			b.Losses++
+			resultA = 1
+		case "b":
+			b.Wins++
+			a.Losses++
+			resultA = 0
+		default:
+			a.Draws++
+			b.Draws++
+		}
+		expectedA := 1 / (1 + math.Pow(10, (b.Elo-a.Elo)/400))
+		delta := eloK * (resultA - expectedA)
+		a.Elo += delta
+		b.Elo -= delta
+	}
+
+	for _, s := range standings {
+		s.Elo = math.Round(s.Elo*10) / 10
+		if s.Games > 0 {
+			s.WinRate = float64(s.Wins) / float64(s.Games)
+		}
+		summarize(s)
+	}
+	sort.SliceStable(standings, func(i, j int) bool { return standings[i].Elo > standings[j].Elo })
+	return standings
+}
+
+// tallyScenarios aggregates curriculum results per scenario and controller,
+// crediting only the controller that played the first slot
+func tallyScenarios(results []Result) []*ScenarioStanding {
+	byKey := make(map[[2]string]*ScenarioStanding)
+	var standings []*ScenarioStanding
+	for _, r := range results {
+		if r.Scenario.Name == "" {
+			continue
+		}
+		name := r.A
+		won := r.Winner == "a"
+		if r.Swap {
+			name = r.B
+			won = r.Winner == "b"
+		}
+		key := [2]string{r.Scenario.Name, name}
+		s, ok := byKey[key]
+		if !ok {
+			s = &ScenarioStanding{Scenario: r.Scenario.Name, Controller: name}
+			byKey[key] = s
+			standings = append(standings, s)
+		}
+		s.Games++
+		s.HitsTaken += r.HitsTaken
+		s.DifficultyMean += r.Scenario.Difficulty
+		if r.Survived {
+			s.Survived++
+		}
+		if won {
+			s.Wins++
+		}
+	}
+
+	for _, s := range standings {
+		s.SurvivalRate = float64(s.Survived) / float64(s.Games)
+		s.DifficultyMean = math.Round(s.DifficultyMean/float64(s.Games)*100) / 100
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Scenario != standings[j].Scenario {
+			return standings[i].Scenario < standings[j].Scenario
+		}
+		return standings[i].Controller < standings[j].Controller
+	})
+	return standings
+}
+
+// summarize fills in the score distribution
+func summarize(s *Standing) {
+	if len(s.scores) == 0 {
+		return
+	}
+	sorted := append([]int(nil), s.scores...)
+	sort.Ints(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(sorted))
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (float64(v) - mean) * (float64(v) - mean)
+	}
+
+	pct := func(p float64) int {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	s.ScoreMean = math.Round(mean*100) / 100
+	s.ScoreStd = math.Round(math.Sqrt(variance/float64(len(sorted)))*100) / 100
+	s.ScoreP10 = pct(0.1)
+	s.ScoreP50 = pct(0.5)
+	s.ScoreP90 = pct(0.9)
+	s.ScoreMax = sorted[len(sorted)-1]
+}
+
+func writeCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"controller", "games", "wins", "losses", "draws", "win_rate", "elo",
+		"score_mean", "score_std", "score_p10", "score_p50", "score_p90", "score_max"})
+	for _, s := range r.Standings {
+		cw.Write([]string{
+			s.Controller,
+			strconv.Itoa(s.Games),
+			strconv.Itoa(s.Wins),
+			strconv.Itoa(s.Losses),
+			strconv.Itoa(s.Draws),
+			strconv.FormatFloat(s.WinRate, 'f', 4, 64),
+			strconv.FormatFloat(s.Elo, 'f', 1, 64),
+			strconv.FormatFloat(s.ScoreMean, 'f', 2, 64),
+			strconv.FormatFloat(s.ScoreStd, 'f', 2, 64),
+			strconv.Itoa(s.ScoreP10),
+			strconv.Itoa(s.ScoreP50),
+			strconv.Itoa(s.ScoreP90),
+			strconv.Itoa(s.ScoreMax),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -1,14 +1,56 @@
+//go:build cgo
+
+// check_onnx checks that ONNX Runtime works and, given a model, validates it
+// before it is copied into ml/checkpoints:
+//
+//	go run ./cmd/check_onnx                                  # runtime only
+//	go run ./cmd/check_onnx -records data/records my_model.onnx
+//
+// The model's inputs and outputs must match its observation spec, and its
+// moves on recorded states are compared with the heuristic AI. The exit code
+// is non-zero when the contract is broken or a threshold is missed.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"runtime"
 
+	"github.com/trytobebee/snake_go/pkg/config"
+	"github.com/trytobebee/snake_go/pkg/game"
 	ort "github.com/yalue/onnxruntime_go"
 )
 
 func main() {
+	records := flag.String("records", "data/records", "Directory of recorded games (*.jsonl)")
+	states := flag.Int("states", 2000, "Maximum number of recorded states to evaluate")
+	width := flag.Int("width", config.StandardWidth, "Board width of the recordings")
+	height := flag.Int("height", config.StandardHeight, "Board height of the recordings")
+	maxUnsafe := flag.Float64("max-unsafe", 0.05, "Fail when more than this fraction of moves is unsafe")
+	minAgreement := flag.Float64("min-agreement", 0, "Fail when less than this fraction of moves agrees with the heuristic AI")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		checkRuntime()
+		return
+	}
+
+	opts := validateOptions{
+		records:      *records,
+		states:       *states,
+		width:        *width,
+		height:       *height,
+		maxUnsafe:    *maxUnsafe,
+		minAgreement: *minAgreement,
+	}
+	if !validate(flag.Arg(0), opts) {
+		os.Exit(1)
+	}
+}
+
+// checkRuntime only checks that the shared library loads
+func checkRuntime() {
 	fmt.Println("Checking ONNX Runtime...")
 
 	// Copy of logic from ai_model.go
@@ -48,3 +90,16 @@ func main() {
 
 	fmt.Println("SUCCESS: ONNX Runtime initialized correctly.")
 }
+
+// loadForValidation loads the model through the engine, which also
+// initializes ONNX Runtime, and refuses the pure-Go fallback
+func loadForValidation(path string) (*game.ModelVersion, error) {
+	mv, err := game.LoadModel(path)
+	if err != nil {
+		return nil, err
+	}
+	if mv.Backend != "onnx" {
+		return nil, fmt.Errorf("ONNX Runtime is unavailable, only the pure-Go weights could be loaded")
+	}
+	return mv, nil
+}
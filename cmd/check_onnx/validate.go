@@ -0,0 +1,260 @@
+//go:build cgo
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/game"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+type validateOptions struct {
+	records      string
+	states       int
+	width        int
+	height       int
+	maxUnsafe    float64
+	minAgreement float64
+}
+
+const latencyBatch = 32
+
+var actionNames = [len(game.ActionDirections)]string{"up", "down", "left", "right"}
+
+// validate prints the report and returns whether the model passed
+func validate(path string, opts validateOptions) bool {
+	fmt.Printf("Validating %s\n", path)
+	mv, err := loadForValidation(path)
+	if err != nil {
+		fmt.Printf("FAIL: %v\n", err)
+		return false
+	}
+	fmt.Printf("Observation spec v%d: %d channels %v\n", mv.Spec.Version, len(mv.Spec.Channels), mv.Spec.Channels)
+
+	if !checkContract(path, mv.Spec) {
+		return false
+	}
+
+	states, err := loadStates(opts.records, opts.states)
+	if err != nil {
+		fmt.Printf("FAIL: reading records: %v\n", err)
+		return false
+	}
+	if len(states) == 0 {
+		fmt.Printf("WARN: no recorded states in %s, skipping the behaviour checks\n", opts.records)
+		fmt.Println("SUCCESS: model matches the observation contract.")
+		return true
+	}
+
+	// Every rebuilt state is a new game, which would log its setup
+	log.SetOutput(io.Discard)
+	r := evaluate(mv, states, opts)
+	r.print()
+
+	ok := true
+	if r.unsafeRate() > opts.maxUnsafe {
+		fmt.Printf("FAIL: %.1f%% unsafe moves (limit %.1f%%)\n", 100*r.unsafeRate(), 100*opts.maxUnsafe)
+		ok = false
+	}
+	if r.agreementRate() < opts.minAgreement {
+		fmt.Printf("FAIL: %.1f%% agreement with the heuristic AI (minimum %.1f%%)\n", 100*r.agreementRate(), 100*opts.minAgreement)
+		ok = false
+	}
+	if ok {
+		fmt.Println("SUCCESS: model is ready for ml/checkpoints.")
+	}
+	return ok
+}
+
+// checkContract compares the ONNX graph's inputs and outputs with what the
+// engine feeds it: one float "input" of (batch, C, 25, 25) and one "output"
+// of (batch, 4)
+func checkContract(path string, spec game.ObservationSpec) bool {
+	inputs, outputs, err := ort.GetInputOutputInfo(path)
+	if err != nil {
+		fmt.Printf("FAIL: reading model inputs/outputs: %v\n", err)
+		return false
+	}
+
+	ok := true
+	fail := func(format string, args ...any) {
+		fmt.Printf("FAIL: "+format+"\n", args...)
+		ok = false
+	}
+	want := []int64{-1, int64(len(spec.Channels)), game.ObservationWindow, game.ObservationWindow}
+
+	if len(inputs) != 1 || inputs[0].Name != "input" {
+		fail("expected one input named \"input\", got %v", ioNames(inputs))
+	} else if in := inputs[0]; in.DataType != ort.TensorElementDataTypeFloat || !shapeMatches(in.Dimensions, want) {
+		fail("input is %v %v, want float %v", in.DataType, in.Dimensions, want)
+	} else {
+		fmt.Printf("Input  %q %v\n", in.Name, in.Dimensions)
+		if in.Dimensions[0] > 0 {
+			fmt.Printf("WARN: fixed batch size %d, batched inference will fall back to one sample at a time\n", in.Dimensions[0])
+		}
+	}
+
+	want = []int64{-1, int64(len(game.ActionDirections))}
+	if len(outputs) != 1 || outputs[0].Name != "output" {
+		fail("expected one output named \"output\", got %v", ioNames(outputs))
+	} else if out := outputs[0]; out.DataType != ort.TensorElementDataTypeFloat || !shapeMatches(out.Dimensions, want) {
+		fail("output is %v %v, want float %v", out.DataType, out.Dimensions, want)
+	} else {
+		fmt.Printf("Output %q %v\n", out.Name, out.Dimensions)
+	}
+	return ok
+}
+
+// shapeMatches treats -1 in want, and any dynamic axis in got, as a batch axis
+func shapeMatches(got ort.Shape, want []int64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range want {
+		if want[i] >= 0 && got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func ioNames(infos []ort.InputOutputInfo) []string {
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name
+	}
+	return names
+}
+
+// loadStates reads up to limit live states from the recordings, oldest file first
+func loadStates(dir string, limit int) ([]game.GameState, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var states []game.GameState
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+		for scanner.Scan() && len(states) < limit {
+			var rec game.StepRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				continue
+			}
+			if rec.State.GameOver || len(rec.State.Snake) < 2 {
+				continue
+			}
+			states = append(states, rec.State)
+		}
+		f.Close()
+		if len(states) >= limit {
+			break
+		}
+	}
+	return states, nil
+}
+
+type report struct {
+	states    int
+	actions   [len(game.ActionDirections)]int
+	agree     int
+	unsafe    int
+	caught    int // Unsafe moves where the fallback has a safe alternative
+	single    []time.Duration
+	batchTime time.Duration
+}
+
+func (r *report) unsafeRate() float64    { return float64(r.unsafe) / float64(r.states) }
+func (r *report) agreementRate() float64 { return float64(r.agree) / float64(r.states) }
+
+// evaluate runs the model on every state, one sample at a time for latency
+// and in batches for throughput, and compares its moves with the heuristic AI
+func evaluate(mv *game.ModelVersion, states []game.GameState, opts validateOptions) *report {
+	r := &report{states: len(states)}
+	games := make([]*game.Game, len(states))
+	inputs := make([][]float64, len(states))
+	for i, s := range states {
+		games[i] = game.GameFromState(s, opts.width, opts.height)
+		inputs[i] = games[i].BuildObservation(mv.Spec, 0)
+	}
+
+	logits := make([][]float32, len(inputs))
+	for i, in := range inputs {
+		start := time.Now()
+		logits[i] = mv.Run([][]float64{in})[0]
+		r.single = append(r.single, time.Since(start))
+	}
+	start := time.Now()
+	for i := 0; i < len(inputs); i += latencyBatch {
+		mv.Run(inputs[i:min(i+latencyBatch, len(inputs))])
+	}
+	r.batchTime = time.Since(start)
+
+	for i, g := range games {
+		action := argmax(logits[i])
+		r.actions[action]++
+		dir := game.ActionDirections[action]
+
+		p := g.Players[0]
+		if best, _, _ := g.CalculateBestMove(0, p.Snake, p.LastMoveDir); best == dir {
+			r.agree++
+		}
+		if !g.IsSafeMove(0, dir) {
+			r.unsafe++
+			for _, alt := range game.ActionDirections {
+				if g.IsSafeMove(0, alt) {
+					r.caught++
+					break
+				}
+			}
+		}
+	}
+	return r
+}
+
+func argmax(logits []float32) int {
+	best := 0
+	for i, v := range logits {
+		if i < len(game.ActionDirections) && v > logits[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+func (r *report) print() {
+	fmt.Printf("\nEvaluated %d recorded states\n", r.states)
+	fmt.Println("Action distribution:")
+	for i, n := range r.actions {
+		fmt.Printf("  %-5s %5d  %5.1f%%\n", actionNames[i], n, 100*float64(n)/float64(r.states))
+	}
+	fmt.Printf("Agreement with heuristic AI: %d (%.1f%%)\n", r.agree, 100*r.agreementRate())
+	fmt.Printf("Unsafe moves: %d (%.1f%%), %d would be replaced by NeuralController's isSafe fallback\n",
+		r.unsafe, 100*r.unsafeRate(), r.caught)
+
+	sorted := append([]time.Duration(nil), r.single...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	pct := func(p float64) time.Duration { return sorted[int(p*float64(len(sorted)-1))] }
+	fmt.Printf("Latency (single sample): mean %v, p50 %v, p99 %v, max %v\n",
+		total/time.Duration(len(sorted)), pct(0.5), pct(0.99), sorted[len(sorted)-1])
+	fmt.Printf("Latency (batches of %d): %v per sample\n\n", latencyBatch, r.batchTime/time.Duration(r.states))
+}
@@ -0,0 +1,387 @@
+// dataset turns recorded games into training shards. Each step's observation
+// is rebuilt with the engine's own feature builder, so Python never has to
+// re-implement it:
+//
+//	go run ./cmd/dataset -records data/records -out data/dataset -outcome win
+//
+// Recordings carry every player's moves, so -players all also exports the
+// opponent's (or the second human's) side of each game. Rewards are
+// recomputed from the recorded events with -reward, so games can be
+// reshaped without replaying them.
+//
+// With -curriculum the games are played on the spot instead, each starting
+// from a generated scenario, so training data can target situations that
+// recordings rarely contain:
+//
+//	go run ./cmd/dataset -curriculum all@0.3-1 -episodes 2000 -teacher mcts -out data/curriculum
+//
+// The output directory holds train/ and val/ shards (.npz with obs, action,
+// reward, next_obs and done), the observation spec and a manifest. Games are
+// assigned to a split as a whole so no game leaks across them.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+	"github.com/trytobebee/snake_go/pkg/game"
+)
+
+// Manifest describes an exported dataset
+type Manifest struct {
+	Spec    game.ObservationSpec `json:"spec"`
+	Reward  string               `json:"reward"`
+	Weights game.RewardWeights   `json:"reward_weights"`
+	Filters Filters              `json:"filters"`
+	Games   map[string]int       `json:"games"`       // Per split
+	Samples map[string]int       `json:"transitions"` // Per split
+	Shards  map[string][]string  `json:"shards"`      // Per split, relative to the manifest
+	Skipped int                  `json:"skipped_games"`
+
+	Curriculum string `json:"curriculum,omitempty"` // Set when the games were generated
+	Teacher    string `json:"teacher,omitempty"`
+	Opponent   string `json:"opponent,omitempty"`
+}
+
+// Filters select which recorded games are exported
+type Filters struct {
+	Outcomes     []string `json:"outcomes,omitempty"`     // win, loss, draw, unfinished
+	Modes        []string `json:"modes,omitempty"`        // battle, pvp, zen
+	Difficulties []string `json:"difficulties,omitempty"` // Recorded difficulty of the player
+	Controllers  []string `json:"controllers,omitempty"`  // manual, heuristic, neural, ...
+	Players      string   `json:"players"`                // p1 or all
+	MinScore     int      `json:"min_score"`              // The player's final score, a proxy for skill
+	MinSteps     int      `json:"min_steps"`
+}
+
+func (f Filters) accept(s game.EpisodeSummary) bool {
+	in := func(list []string, v string) bool {
+		if len(list) == 0 {
+			return true
+		}
+		for _, item := range list {
+			if item == v {
+				return true
+			}
+		}
+		return false
+	}
+	return in(f.Outcomes, s.Outcome) && in(f.Modes, s.Mode) && in(f.Difficulties, s.Difficulty) &&
+		(len(f.Controllers) == 0 || in(f.Controllers, s.Controller)) && s.Score >= f.MinScore && s.Steps >= f.MinSteps
+}
+
+func main() {
+	records := flag.String("records", "data/records", "Directory of recorded games (*.jsonl)")
+	out := flag.String("out", "data/dataset", "Output directory")
+	observation := flag.Int("observation", 1, "Observation spec version")
+	specPath := flag.String("spec", "", "Use the spec of this model file instead (reads <model>.spec.json)")
+	reward := flag.String("reward", "default", "Reward preset applied to the recorded events")
+	rewards := flag.String("rewards", "", "JSON file of extra reward presets ({\"name\": {weights}})")
+	valFrac := flag.Float64("val", 0.1, "Fraction of games in the validation split")
+	seed := flag.Int64("seed", 1, "Seed for the train/validation assignment")
+	shardSize := flag.Int("shard", 4096, "Transitions per shard")
+	outcome := flag.String("outcome", "", "Comma-separated outcomes to keep: win, loss, draw, unfinished (default all)")
+	mode := flag.String("mode", "", "Comma-separated modes to keep: battle, pvp, zen (default all)")
+	difficulty := flag.String("difficulty", "", "Comma-separated difficulties to keep (default all)")
+	controller := flag.String("controller", "", "Comma-separated controllers to keep: manual, heuristic, neural (default all)")
+	players := flag.String("players", "p1", "Perspectives to export: p1, or all players of each game")
+	minScore := flag.Int("min-score", 0, "Only perspectives that finished with at least this score")
+	minSteps := flag.Int("min-steps", 2, "Only games with at least this many recorded steps")
+	width := flag.Int("width", config.StandardWidth, "Board width of the recordings")
+	height := flag.Int("height", config.StandardHeight, "Board height of the recordings")
+	verbose := flag.Bool("v", false, "Keep engine logs")
+	curriculumSpec := flag.String("curriculum", "", "Generate games from scenarios instead of reading -records: all or a comma list of "+strings.Join(game.ScenarioNames(), ", ")+", optionally with a difficulty range (e.g. corridor@0.5-1)")
+	episodes := flag.Int("episodes", 1000, "Generated games (with -curriculum)")
+	teacher := flag.String("teacher", "heuristic", "Controller playing the scenario's snake in generated games")
+	opponent := flag.String("opponent", "heuristic", "Controller playing the other snake in generated games")
+	speed := flag.String("speed", "mid", "Speed tier in generated games: low, mid or high")
+	horizon := flag.Int("horizon", 60, "Moves of the scenario's snake per generated game (0 plays to the end)")
+	flag.Parse()
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	spec, err := game.ObservationSpecVersion(*observation)
+	if *specPath != "" {
+		spec, err = game.LoadObservationSpec(*specPath)
+	}
+	if err != nil {
+		fail(err)
+	}
+	if *rewards != "" {
+		if err := game.LoadRewardPresets(*rewards); err != nil {
+			fail(err)
+		}
+	}
+	weights, err := game.RewardPreset(*reward)
+	if err != nil {
+		fail(err)
+	}
+
+	if *players != "p1" && *players != "all" {
+		fail(fmt.Errorf("-players must be p1 or all, got %q", *players))
+	}
+	filters := Filters{
+		Outcomes:     splitList(*outcome),
+		Modes:        splitList(*mode),
+		Difficulties: splitList(*difficulty),
+		Controllers:  splitList(*controller),
+		Players:      *players,
+		MinScore:     *minScore,
+		MinSteps:     *minSteps,
+	}
+
+	curriculum, err := game.ParseCurriculum(*curriculumSpec)
+	if err != nil {
+		fail(err)
+	}
+	// Each game is either a recording or, with a curriculum, played now
+	var files []string
+	var load func(i int) ([]game.StepRecord, error)
+	if curriculum != nil {
+		for _, name := range []string{*teacher, *opponent} {
+			if _, err := game.NewController(name); err != nil {
+				fail(err)
+			}
+		}
+		files = make([]string, *episodes)
+		for i := range files {
+			files[i] = fmt.Sprintf("episode %d", i)
+		}
+		load = func(i int) ([]game.StepRecord, error) {
+			return generate(curriculum, *seed+int64(i), *width, *height, *teacher, *opponent, *speed, *horizon)
+		}
+	} else {
+		files, err = filepath.Glob(filepath.Join(*records, "*.jsonl"))
+		if err != nil {
+			fail(err)
+		}
+		sort.Strings(files)
+		if len(files) == 0 {
+			fail(fmt.Errorf("no recordings in %s", *records))
+		}
+		load = func(i int) ([]game.StepRecord, error) { return readRecords(files[i]) }
+	}
+
+	manifest := Manifest{
+		Spec:    spec,
+		Reward:  *reward,
+		Weights: weights,
+		Filters: filters,
+		Games:   map[string]int{},
+		Samples: map[string]int{},
+		Shards:  map[string][]string{},
+	}
+	if curriculum != nil {
+		manifest.Curriculum, manifest.Teacher, manifest.Opponent = curriculum.String(), *teacher, *opponent
+	}
+	writers := map[string]*shardWriter{}
+	for _, split := range []string{"train", "val"} {
+		dir := filepath.Join(*out, split)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fail(err)
+		}
+		// Shards of an earlier export would otherwise mix with this one
+		stale, _ := filepath.Glob(filepath.Join(dir, "shard_*.npz"))
+		for _, f := range stale {
+			os.Remove(f)
+		}
+		writers[split] = &shardWriter{dir: dir, split: split, size: *shardSize, spec: spec}
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+	for i, path := range files {
+		// Draw for every file so the split of a game does not depend on the filters
+		split := "train"
+		if rng.Float64() < *valFrac {
+			split = "val"
+		}
+
+		recs, err := load(i)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ %s: %v\n", path, err)
+			manifest.Skipped++
+			continue
+		}
+		perspectives := 1
+		if *players == "all" {
+			perspectives = game.EpisodePlayers(recs)
+		}
+		exported := 0
+		for idx := 0; idx < perspectives; idx++ {
+			if !filters.accept(game.SummarizeEpisode(recs, idx, *width, *height)) {
+				continue
+			}
+			transitions := game.BuildTransitions(recs, spec, weights, idx, *width, *height)
+			exported += len(transitions)
+			if err := writers[split].add(transitions); err != nil {
+				fail(err)
+			}
+		}
+		if exported == 0 {
+			manifest.Skipped++
+			continue
+		}
+		manifest.Games[split]++
+		manifest.Samples[split] += exported
+	}
+
+	for split, w := range writers {
+		if err := w.flush(); err != nil {
+			fail(err)
+		}
+		manifest.Shards[split] = append([]string{}, w.shards...)
+	}
+	if err := writeJSON(filepath.Join(*out, "spec.json"), spec); err != nil {
+		fail(err)
+	}
+	if err := writeJSON(filepath.Join(*out, "manifest.json"), manifest); err != nil {
+		fail(err)
+	}
+
+	fmt.Printf("✅ %d train / %d val transitions from %d train / %d val games (%d skipped), spec v%d with %d channels → %s\n",
+		manifest.Samples["train"], manifest.Samples["val"], manifest.Games["train"], manifest.Games["val"],
+		manifest.Skipped, spec.Version, len(spec.Channels), *out)
+}
+
+// shardWriter converts transitions to float32 as they arrive and writes
+// them shard by shard
+type shardWriter struct {
+	dir, split string
+	size       int
+	spec       game.ObservationSpec
+	shards     []string
+
+	obs, next []float32
+	actions   []int64
+	rewards   []float32
+	dones     []bool
+}
+
+func (w *shardWriter) add(ts []game.Transition) error {
+	for _, t := range ts {
+		for _, v := range t.Obs {
+			w.obs = append(w.obs, float32(v))
+		}
+		for _, v := range t.NextObs {
+			w.next = append(w.next, float32(v))
+		}
+		w.actions = append(w.actions, int64(t.Action))
+		w.rewards = append(w.rewards, float32(t.Reward))
+		w.dones = append(w.dones, t.Done)
+		if len(w.actions) == w.size {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *shardWriter) flush() error {
+	n := len(w.actions)
+	if n == 0 {
+		return nil
+	}
+	gridShape := []int{n, len(w.spec.Channels), game.ObservationWindow, game.ObservationWindow}
+	name := fmt.Sprintf("shard_%05d.npz", len(w.shards))
+	err := writeNPZ(filepath.Join(w.dir, name), []npyArray{
+		{name: "obs", descr: "<f4", shape: gridShape, data: w.obs},
+		{name: "action", descr: "<i8", shape: []int{n}, data: w.actions},
+		{name: "reward", descr: "<f4", shape: []int{n}, data: w.rewards},
+		{name: "next_obs", descr: "<f4", shape: gridShape, data: w.next},
+		{name: "done", descr: "|b1", shape: []int{n}, data: w.dones},
+	})
+	if err != nil {
+		return err
+	}
+	w.shards = append(w.shards, filepath.Join(w.split, name))
+	w.obs, w.next = w.obs[:0], w.next[:0]
+	w.actions, w.rewards, w.dones = w.actions[:0], w.rewards[:0], w.dones[:0]
+	return nil
+}
+
+// generate plays one headless PVP game from a curriculum scenario and
+// records it like the web server would: a step after every move of the
+// scenario's snake, and the final one
+func generate(c *game.Curriculum, seed int64, width, height int, teacher, opponent, speed string, horizon int) ([]game.StepRecord, error) {
+	g := game.NewSeededGame(width, height, seed)
+	g.Headless = true
+	defer g.ReleaseControllers()
+	g.SetupPVP(teacher, opponent)
+	for i, name := range []string{teacher, opponent} {
+		brain, _ := game.NewController(name)
+		g.Players[i].Brain = brain
+		g.Players[i].Controller = name
+	}
+	if _, err := c.Apply(g, seed); err != nil {
+		return nil, err
+	}
+
+	rec := game.NewMemoryRecorder()
+	sim := game.NewSimulator(g, speed)
+	record := func() { rec.Record(g, g.GetGameStateSnapshot(true, g.Players[0].Boosting, "")) }
+	record()
+	for !g.GameOver && (horizon <= 0 || sim.Moves(0) < horizon) {
+		moves := sim.Moves(0)
+		sim.Tick()
+		if sim.Moves(0) != moves || g.GameOver {
+			record()
+		}
+	}
+	return rec.Steps(), nil
+}
+
+func readRecords(path string) ([]game.StepRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var recs []game.StepRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec game.StepRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // A crash can leave a truncated last line
+		}
+		recs = append(recs, rec)
+	}
+	return recs, scanner.Err()
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+	os.Exit(1)
+}
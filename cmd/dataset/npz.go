@@ -0,0 +1,74 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// npyArray is one array of an .npz archive
+type npyArray struct {
+	name  string
+	descr string // NumPy dtype, e.g. "<f4"
+	shape []int
+	data  any // Slice written with encoding/binary (little-endian)
+}
+
+// writeNPZ writes the arrays as a deflated .npz that numpy.load reads directly
+func writeNPZ(path string, arrays []npyArray) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(f)
+	for _, a := range arrays {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: a.name + ".npy", Method: zip.Deflate})
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := w.Write(npyHeader(a.descr, a.shape)); err != nil {
+			f.Close()
+			return err
+		}
+		if err := binary.Write(w, binary.LittleEndian, a.data); err != nil {
+			f.Close()
+			return fmt.Errorf("%s: %w", a.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// npyHeader builds a version 1.0 .npy header, padded so the data starts on a
+// 64-byte boundary
+func npyHeader(descr string, shape []int) []byte {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = fmt.Sprint(d)
+	}
+	tuple := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		tuple += ","
+	}
+	dict := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, tuple)
+
+	const prefix = 10 // Magic, version and header length
+	pad := 64 - (prefix+len(dict)+1)%64
+	if pad == 64 {
+		pad = 0
+	}
+	dict += strings.Repeat(" ", pad) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&buf, binary.LittleEndian, uint16(len(dict)))
+	buf.WriteString(dict)
+	return buf.Bytes()
+}
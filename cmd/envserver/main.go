@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/websocket"
+	"github.com/trytobebee/snake_go/pkg/game"
+	pb "github.com/trytobebee/snake_go/pkg/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Largest number of environments a single connection may run
+const maxEnvsPerConn = 1024
+
+func main() {
+	addr := flag.String("addr", "localhost:8090", "Listen address")
+	verbose := flag.Bool("v", false, "Keep engine logs")
+	rewards := flag.String("rewards", "", "JSON file of extra reward presets ({\"name\": {weights}})")
+	flag.Parse()
+
+	if *rewards != "" {
+		if err := game.LoadRewardPresets(*rewards); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	http.HandleFunc("/ws/env", handleEnv)
+
+	fmt.Printf("🧪 Snake RL environment server on ws://%s/ws/env\n", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handleEnv serves one agent. Every connection owns its own pool of
+// environments; each binary frame is an EnvRequest answered by an EnvResponse.
+func handleEnv(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	pool := &game.EnvPool{}
+	defer pool.Close()
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req pb.EnvRequest
+		resp := &pb.EnvResponse{Type: "error"}
+		if err := proto.Unmarshal(data, &req); err != nil {
+			resp.Error = fmt.Sprintf("bad request: %v", err)
+		} else {
+			resp = handleRequest(pool, &req)
+		}
+
+		out, err := proto.Marshal(resp)
+		if err != nil {
+			return
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, out); err != nil {
+			return
+		}
+	}
+}
+
+func handleRequest(pool *game.EnvPool, req *pb.EnvRequest) *pb.EnvResponse {
+	resp := &pb.EnvResponse{Type: req.Type}
+	var steps []game.EnvStep
+	var err error
+
+	switch req.Type {
+	case "reset":
+		seeds := req.Seeds
+		if len(seeds) == 0 {
+			seeds = []int64{0}
+		}
+		if len(seeds) > maxEnvsPerConn {
+			resp.Error = fmt.Sprintf("at most %d environments per connection", maxEnvsPerConn)
+			return resp
+		}
+		cfg := pb.FromProtoEnvConfig(req.Config)
+		steps, err = pool.Reset(seeds, cfg)
+		if err == nil {
+			pool.AutoReset = req.Config.GetAutoReset()
+		}
+		resp.ScalarNames = game.ObservationScalars
+		if spec, serr := game.ObservationSpecVersion(cfg.Observation); serr == nil {
+			resp.ChannelNames = spec.Channels
+		}
+	case "step":
+		steps, err = pool.Step(pb.FromProtoEnvActions(req.Actions))
+	default:
+		err = fmt.Errorf("unknown request type %q", req.Type)
+	}
+
+	if err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+	resp.Steps = pb.ToProtoEnvSteps(steps)
+	return resp
+}
@@ -80,7 +80,9 @@ func main() {
 
 			if input.IsRestart(inputEvent) {
 				if g.GameOver {
+					showAI := g.ShowAIDebug
 					g = game.NewGame(config.LargeWidth, config.LargeHeight)
+					g.ShowAIDebug = showAI
 					boosting = false
 					tickCount = 0
 					consecutiveKeyCount = 0
@@ -94,6 +96,11 @@ func main() {
 				}
 			}
 
+			if input.IsAIDebug(inputEvent) {
+				g.ShowAIDebug = !g.ShowAIDebug
+				render.Render(g, boosting)
+			}
+
 			if inputDir, isValid := input.ParseDirection(inputEvent); isValid {
 				dirChanged := g.SetDirection(inputDir)
 
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+	"github.com/trytobebee/snake_go/pkg/game"
+	pb "github.com/trytobebee/snake_go/pkg/proto"
+)
+
+// Bot API: programs play as a bot account over /ws/bot with JSON messages.
+// The protocol is documented in docs/BOT_API.md.
+
+// errBotBehind drops an observe request while the bot's connection is still
+// busy with earlier ones; that move uses the fallback
+var errBotBehind = errors.New("bot is not keeping up with its observe requests")
+
+// botCommand is a message from a bot
+type botCommand struct {
+	Type       string          `json:"type"` // auth, play, action or leave
+	Username   string          `json:"username,omitempty"`
+	Token      string          `json:"token,omitempty"`
+	Mode       string          `json:"mode,omitempty"`       // battle (default) or pvp
+	Difficulty string          `json:"difficulty,omitempty"` // AI difficulty in battle mode
+	Opponents  int             `json:"opponents,omitempty"`  // AI snakes in battle mode
+	Opponent   string          `json:"opponent,omitempty"`   // WASM bot playing the AI snakes in battle mode
+	Tick       int             `json:"tick,omitempty"`
+	Action     game.ActionData `json:"action"`
+}
+
+// botEvent is a message to a bot
+type botEvent struct {
+	Type       string          `json:"type"` // auth_ok, error, searching, start, observe or game_over
+	Error      string          `json:"error,omitempty"`
+	User       *game.User      `json:"user,omitempty"`
+	Mode       string          `json:"mode,omitempty"`
+	Width      int             `json:"width,omitempty"`
+	Height     int             `json:"height,omitempty"`
+	You        int             `json:"you"`
+	Tick       int             `json:"tick,omitempty"`
+	DeadlineMs int             `json:"deadline_ms,omitempty"`
+	State      *game.GameState `json:"state,omitempty"`
+	Result     string          `json:"result,omitempty"` // win, loss, draw or aborted
+	Score      int             `json:"score,omitempty"`
+	Answered   int             `json:"answered,omitempty"` // Moves the bot replied to in time
+	Missed     int             `json:"missed,omitempty"`   // Moves that used the fallback
+}
+
+// botSession is the bot side of a GameServer
+type botSession struct {
+	ctrl   *game.RemoteController
+	send   func(botEvent) error
+	idx    int  // Player the bot controls in the current game
+	inGame bool // A game is running and its end has not been reported yet
+}
+
+// attachBot hands the server's player to its bot and announces the game.
+// Games are created with manual controllers, so this runs for every new game.
+func (gs *GameServer) attachBot() {
+	if gs.bot == nil {
+		return
+	}
+	idx := gs.seat
+	if idx >= len(gs.game.Players) {
+		return
+	}
+	p := gs.game.Players[idx]
+	gs.bot.ctrl.NewGame()
+	p.Brain = gs.bot.ctrl
+	p.Controller = "bot"
+	gs.bot.idx = idx
+	gs.bot.inGame = true
+	gs.bot.send(botEvent{Type: "start", Mode: gs.game.Mode, Width: gs.game.Width, Height: gs.game.Height, You: idx})
+}
+
+// reportGameOver tells the bot how its game ended, once
+func (gs *GameServer) reportGameOver() {
+	b := gs.bot
+	// A match can also end early when the other side disconnects
+	aborted := gs.game.IsPVP && gs.match == nil && !gs.game.GameOver
+	if b == nil || !b.inGame || gs.match != nil || (!gs.game.GameOver && !aborted) {
+		return
+	}
+	b.inGame = false
+
+	result := "aborted"
+	if !aborted {
+		result = map[string]string{"won": "win", "lost": "loss", "draw": "draw"}[gs.game.Result(b.idx)]
+	}
+	ev := botEvent{Type: "game_over", You: b.idx, Result: result}
+	if b.idx < len(gs.game.Players) {
+		ev.Score = gs.game.Players[b.idx].Score
+	}
+	ev.Answered, ev.Missed = b.ctrl.Stats()
+	b.send(ev)
+}
+
+// play starts a solo battle or joins PVP matchmaking for the bot
+func (gs *GameServer) play(cmd botCommand) error {
+	if gs.match != nil || gs.searching || (gs.started && !gs.game.GameOver) {
+		return fmt.Errorf("already playing")
+	}
+
+	if cmd.Mode == "pvp" {
+		gs.bot.send(botEvent{Type: "searching"})
+		pvpManager.FindMatch(gs) // attachBot runs once the match is made
+		return nil
+	}
+	if cmd.Mode != "" && cmd.Mode != "battle" {
+		return fmt.Errorf("unknown mode %q (battle or pvp)", cmd.Mode)
+	}
+
+	if gs.game.GameOver || gs.game.IsPVP {
+		gs.resetGame()
+	}
+	gs.handleAction("mode_battle", "")
+	switch cmd.Difficulty {
+	case "":
+	case "low", "mid", "high", "expert", "adaptive":
+		gs.handleAction("diff_"+cmd.Difficulty, "")
+	default:
+		return fmt.Errorf("unknown difficulty %q", cmd.Difficulty)
+	}
+	if cmd.Opponents > 0 {
+		gs.handleAction("opponents", strconv.Itoa(cmd.Opponents))
+	}
+	if cmd.Opponent != "" && !slices.Contains(game.WasmBotNames(), cmd.Opponent) {
+		return fmt.Errorf("unknown opponent %q", cmd.Opponent)
+	}
+	gs.handleAction("opponent_brain", cmd.Opponent)
+	gs.attachBot()
+	gs.startGame()
+	return nil
+}
+
+func handleBotWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("Upgrade error:", err)
+		return
+	}
+	defer conn.Close()
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	connID := fmt.Sprintf("bot-%x-%d", b, time.Now().UnixNano())
+
+	// Bots always get the standard board, the size the neural AI and the
+	// observation specs are built for
+	gs := NewGameServer(connID, config.StandardWidth, config.StandardHeight)
+	send := func(ev botEvent) error {
+		gs.writeMu.Lock()
+		defer gs.writeMu.Unlock()
+		// A stalled bot must not hold up the game loop that is sending to it
+		conn.SetWriteDeadline(time.Now().Add(config.BotMoveTimeout))
+		return conn.WriteJSON(ev)
+	}
+	// The web client messages carry nothing a bot needs besides errors; bots
+	// see the board through their observe requests
+	gs.sendMsg = func(m *pb.ServerMessage) error {
+		if m.Type == "error" {
+			return send(botEvent{Type: "error", Error: m.Error})
+		}
+		return nil
+	}
+	gs.close = func() {
+		conn.Close()
+	}
+
+	// Authenticate before anything else
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var auth botCommand
+	if err := conn.ReadJSON(&auth); err != nil || auth.Type != "auth" {
+		send(botEvent{Type: "error", Error: "expected an auth message"})
+		return
+	}
+	user, err := userManager.LoginBot(auth.Username, auth.Token)
+	if err != nil {
+		log.Printf("🤖 Bot login failed for %s: %v\n", auth.Username, err)
+		send(botEvent{Type: "error", Error: err.Error()})
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	clientsMu.RLock()
+	full := len(clients) >= MaxPlayers
+	clientsMu.RUnlock()
+	if full {
+		send(botEvent{Type: "error", Error: "server is full"})
+		return
+	}
+
+	kickOtherSessions(user.Username, connID)
+
+	// Observe requests come from the game loop, which must never wait for the
+	// bot: they are queued and written by their own goroutine
+	observe := make(chan botEvent, 4)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case ev := <-observe:
+				send(ev)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	gs.user = user
+	gs.bot = &botSession{
+		ctrl: game.NewRemoteController(func(req game.BotRequest) error {
+			select {
+			case observe <- botEvent{Type: "observe", Tick: req.Tick, You: req.You, DeadlineMs: req.DeadlineMs, State: &req.State}:
+				return nil
+			default:
+				return errBotBehind
+			}
+		}),
+		send: send,
+	}
+	log.Printf("🤖 Bot connected: %s\n", user.Username)
+	send(botEvent{Type: "auth_ok", User: user})
+
+	clientsMu.Lock()
+	clients[connID] = gs
+	clientsMu.Unlock()
+	broadcastSessionCount()
+
+	defer func() {
+		clientsMu.Lock()
+		delete(clients, connID)
+		clientsMu.Unlock()
+		broadcastSessionCount()
+		gs.disconnect()
+		log.Printf("🤖 Bot disconnected: %s\n", user.Username)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var cmd botCommand
+			if err := conn.ReadJSON(&cmd); err != nil {
+				return
+			}
+			switch cmd.Type {
+			case "action":
+				gs.bot.ctrl.Reply(game.BotReply{Tick: cmd.Tick, Action: cmd.Action})
+			case "play":
+				if err := gs.play(cmd); err != nil {
+					send(botEvent{Type: "error", Error: err.Error()})
+				}
+			case "leave":
+				pvpManager.CancelSearch(gs)
+			default:
+				send(botEvent{Type: "error", Error: fmt.Sprintf("unknown message type %q", cmd.Type)})
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-gs.ticker.C:
+			// In a match the match loop moves the bot's snake
+			if gs.match == nil {
+				gs.update()
+			}
+			gs.reportGameOver()
+		}
+	}
+}
@@ -6,10 +6,16 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -21,7 +27,18 @@ import (
 )
 
 var (
-	detailedLogs = flag.Bool("detailed-logs", false, "Enable detailed session logging to database")
+	detailedLogs  = flag.Bool("detailed-logs", false, "Enable detailed session logging to database")
+	rewardConfig  = flag.String("rewards", "", "JSON file of extra reward presets recorded with every step")
+	wasmBotDir    = flag.String("wasm-bots", "data/wasm_bots", "Directory of WASM bots (<name>.wasm) offered as battle opponents")
+	spectateDelay = flag.Duration("spectate-delay", 0, "How far behind the players spectators see matches, against ghosting")
+	botFillAfter  = flag.Duration("bot-fill", 30*time.Second, "Match players waiting this long for PVP against the AI (0 = never)")
+	botFillRated  = flag.Bool("bot-fill-rated", false, "Let games against a fill bot change the player's rating")
+
+	reconnectGrace = flag.Duration("reconnect-grace", 30*time.Second, "How long the game of a dropped connection waits for the player to reconnect (0 = not at all)")
+
+	inferenceWorkers = flag.Int("inference-workers", config.InferenceWorkers, "Goroutines running neural AI inference")
+	inferenceBatch   = flag.Int("inference-batch", config.InferenceBatchSize, "Maximum neural AI requests per forward pass")
+	inferenceWait    = flag.Duration("inference-wait", config.InferenceBatchWait, "How long an inference worker waits to fill a batch")
 )
 
 var upgrader = websocket.Upgrader{
@@ -46,32 +63,39 @@ var lbManager = game.NewLeaderboardManager()
 // Global user manager
 var userManager = game.NewUserManager()
 
-// PVP Matchmaking
+// Match is a game shared by several connections: a matchmaking pair or a room
 type Match struct {
+	ID      string // Spectators pick the match by it; the room code for room matches
 	Game    *game.Game
-	P1      *GameServer
-	P2      *GameServer
+	Players []*GameServer // Indexed like Game.Players; nil seats are played by the AI
+	Room    *Room         // Room the match was started from, nil for matchmaking
+	Bot     bool          // Matchmaking gave up on a human opponent and seat 1 is the AI
 	Mu      sync.Mutex
 	Closing bool
-}
 
-type MatchMaker struct {
-	mu      sync.Mutex
-	waiting *GameServer
+	spectators        spectatorHub
+	aiTickCounts      []int // Per-seat move timers of the AI seats
+	fireballTickCount int
 }
 
-var pvpManager = &MatchMaker{}
-
 type GameServer struct {
-	game       *game.Game
-	match      *Match // Shared match if in PVP
-	role       string // "p1" or "p2" for PVP, "solo" for others
-	user       *game.User
-	started    bool
-	searching  bool
-	boosting   bool
-	difficulty string
-	ticker     *time.Ticker
+	game        *game.Game
+	match       *Match // Shared match if in PVP
+	seat        int    // Player this connection controls: its seat in a match, 0 in solo games
+	room        *Room  // Room this connection is a member of, if any
+	watching    *Match // Match this connection spectates, if any
+	width       int    // Solo board size for this device
+	height      int
+	user        *game.User
+	started     bool
+	searching   bool
+	boosting    bool
+	difficulty  string
+	personality string // Solo AI personality preset
+	brain       string // Controller of the solo AI snakes, e.g. a WASM bot; empty for the built-in AI
+	aiDebug     bool   // Stream AI decisions for the debug overlay
+	opponents   int    // Number of AI snakes in battle mode (more than 1 = free-for-all)
+	ticker      *time.Ticker
 
 	// Boost tracking
 	tickCount           int
@@ -80,19 +104,29 @@ type GameServer struct {
 	lastDirKeyDir       game.Point
 	consecutiveKeyCount int
 	fireballTickCount   int
-	aiTickCount         int
+	aiTickCounts        []int // Per-AI move timers, indexed like game.Players
 	currentMode         string
 	userUpdated         bool
 	lbUpdated           bool
 
 	// Recording info
-	stepID        int
-	firedThisStep bool
-	connID        string
-	sessionStart  time.Time
+	connID       string
+	sessionStart time.Time
+
+	bot *botSession // Set when a program plays through the bot API
+
+	// Rematch pairing, guarded by rematchMu
+	rival        *GameServer // Last matchmaking opponent, who may be offered a rematch
+	rematchOffer int         // Series length offered to rival, 0 for none
+
+	// Reconnect state, changed under resumables.mu. away may be read without
+	// it, so that checking a player is free takes no lock.
+	resumeToken string
+	away        atomic.Pointer[awayState] // Set while the session waits for its player to reconnect
 
 	// Connection management
 	writeMu sync.Mutex
+	conn    *websocket.Conn // Current web connection, replaced on a resume (guarded by writeMu)
 	sendMsg func(v *pb.ServerMessage) error
 	close   func() // Function to close the connection
 }
@@ -107,13 +141,64 @@ func NewGameServer(connID string, width, height int) *GameServer {
 		game:        game.NewGame(width, height),
 		ticker:      time.NewTicker(config.BaseTick),
 		difficulty:  "mid",
+		personality: "balanced",
+		opponents:   1,
 		currentMode: "battle",
 		connID:      connID,
+		width:       width,
+		height:      height,
 	}
+	gs.configureAI()
 	gs.game.TimerStarted = false
 	return gs
 }
 
+// configureAI applies the solo opponent settings (count, difficulty, personality) to the current game
+func (gs *GameServer) configureAI() {
+	if gs.game.Mode == "battle" {
+		gs.game.SetupFFA(gs.opponents)
+	}
+	gs.applyDifficulty()
+	gs.game.SetAIPersonality(gs.personality)
+	gs.applyBrain()
+	gs.aiTickCounts = nil
+}
+
+// applyBrain hands the solo AI snakes to the chosen controller. Difficulty
+// and personality only tune the built-in AI, so it runs after them.
+func (gs *GameServer) applyBrain() {
+	if gs.brain == "" || gs.game.IsPVP {
+		return
+	}
+	for i, p := range gs.game.Players {
+		if i == 0 || p.Controller == "manual" {
+			continue
+		}
+		brain, err := game.NewController(gs.brain)
+		if err != nil {
+			log.Printf("⚠️ Opponent %s is gone, keeping the built-in AI: %v\n", gs.brain, err)
+			gs.brain = ""
+			return
+		}
+		game.ReleaseController(p.Brain)
+		p.Brain = brain
+		p.Controller = gs.brain
+	}
+}
+
+// applyDifficulty sets the AI skill for the chosen tier; "adaptive" starts
+// from the user's persisted skill estimate
+func (gs *GameServer) applyDifficulty() {
+	if gs.difficulty == "adaptive" {
+		skill := game.DefaultSkillLevel
+		if gs.user != nil {
+			skill = gs.user.SkillEstimate
+		}
+		gs.game.EnableAdaptiveDifficulty(skill)
+	}
+	gs.game.SetAIDifficulty(gs.difficulty)
+}
+
 func (gs *GameServer) getGameState() game.GameState {
 	state := gs.game.GetGameStateSnapshot(gs.started, gs.boosting, gs.difficulty)
 
@@ -130,12 +215,15 @@ func (gs *GameServer) startRecording() {
 	if gs.game.Recorder != nil {
 		return // Already recording
 	}
-	sessionID := fmt.Sprintf("game_%d_conn_%s", time.Now().UnixNano(), gs.connID)
+	kind := "game"
+	if gs.match != nil {
+		kind = "pvp" // One recording per match, shared by both players
+	}
+	sessionID := fmt.Sprintf("%s_%d_conn_%s", kind, time.Now().UnixNano(), gs.connID)
 	// Sanitize filename safe chars
 	recorder, err := game.NewRecorder(sessionID)
 	if err == nil {
 		gs.game.Recorder = recorder
-		gs.stepID = 0
 		log.Printf("🔴 Recording started: %s\n", sessionID)
 	} else {
 		log.Println("❌ Failed to start recording:", err)
@@ -162,11 +250,8 @@ func (gs *GameServer) checkBoostKey(inputDir game.Point) {
 	gs.lastDirKeyDir = inputDir
 	gs.lastDirKeyTime = now
 
-	if len(gs.game.Players) > 0 {
-		p := gs.game.Players[0]
-		if gs.role == "p2" && len(gs.game.Players) > 1 {
-			p = gs.game.Players[1]
-		}
+	if gs.seat < len(gs.game.Players) {
+		p := gs.game.Players[gs.seat]
 
 		if gs.consecutiveKeyCount >= config.BoostThreshold && inputDir == p.Direction {
 			gs.boosting = true
@@ -192,99 +277,104 @@ func (gs *GameServer) startGame() {
 	gs.startRecording()
 }
 
-func (mm *MatchMaker) FindMatch(gs *GameServer) {
-	mm.mu.Lock()
-	if mm.waiting == nil {
-		gs.searching = true
-		mm.waiting = gs
-		mm.mu.Unlock()
-		log.Printf("[PVP] ⏳ Player %s entered matchmaking queue (waiting for opponent)\n", gs.user.Username)
-		return
-	}
+// resetGame replaces the current game with a fresh solo one in the current
+// mode. A match may have used another board size, so the config is resent.
+func (gs *GameServer) resetGame() {
+	gs.dropGame()
+	gs.game = game.NewGame(gs.width, gs.height)
+	gs.game.Mode = gs.currentMode
+	gs.game.ShowAIDebug = gs.aiDebug
+	gs.configureAI()
+	gs.game.TimerStarted = false
+	gs.started = false
+	gs.boosting = false
+	gs.tickCount = 0
+	gs.consecutiveKeyCount = 0
 
-	// Double-check: Prevent matching with oneself (same user, different connection)
-	if mm.waiting.user.Username == gs.user.Username {
-		log.Printf("[PVP] ⚠️ Player %s tried to match with themselves (another session). Keeping original session in queue.\n", gs.user.Username)
-		mm.mu.Unlock()
-		// Optionally: Send a message to the client saying "Still waiting for others..."
-		return
-	}
+	gameConfig := gs.game.GetGameConfig()
+	gs.sendMsg(pb.ToProtoServerMessage("config", &gameConfig, nil, nil, nil, nil, "", "", 0))
+}
 
-	// Found a pair!
-	p1 := mm.waiting
-	p2 := gs
-	mm.waiting = nil
-	p1.searching = false
-	p2.searching = false
-	mm.mu.Unlock()
-
-	log.Printf("[PVP] ⚔️ Match found: %s (P1) vs %s (P2). Initializing shared game state...\n", p1.user.Username, p2.user.Username)
-
-	// Create shared game - Use Standard size for PVP to ensure mobile compatibility
-	sharedGame := game.NewGame(config.StandardWidth, config.StandardHeight)
-	sharedGame.Mode = "pvp"
-	sharedGame.IsPVP = true
-	sharedGame.Paused = true // Start paused for countdown
-
-	// Reset players for PVP symmetry - Start them at different Y positions to avoid head-on crash
-	sharedGame.Players = []*game.Player{
-		{
-			Snake:       []game.Point{{X: sharedGame.Width / 4, Y: sharedGame.Height / 3}},
-			Direction:   game.Point{X: 1, Y: 0},
-			LastMoveDir: game.Point{X: 1, Y: 0},
-			Name:        p1.user.Username,
-			Brain:       &game.ManualController{},
-			Controller:  "manual",
-		},
-		{
-			Snake:       []game.Point{{X: (sharedGame.Width * 3) / 4, Y: (sharedGame.Height * 2) / 3}},
-			Direction:   game.Point{X: -1, Y: 0},
-			LastMoveDir: game.Point{X: -1, Y: 0},
-			Name:        p2.user.Username,
-			Brain:       &game.ManualController{},
-			Controller:  "manual",
-		},
+// dropGame releases the controllers of the solo game gs is leaving. Match
+// games are shared, so their match releases them when it ends.
+func (gs *GameServer) dropGame() {
+	if !gs.game.IsPVP {
+		gs.game.ReleaseControllers()
 	}
+}
 
-	match := &Match{
-		Game: sharedGame,
-		P1:   p1,
-		P2:   p2,
-	}
+// seatColors names the snake colour of each seat as the web client draws it
+var seatColors = []struct{ emoji, name string }{
+	{"🟢", "GREEN"}, {"🟣", "PURPLE"}, {"🟠", "ORANGE"}, {"🔵", "BLUE"},
+	{"🩷", "PINK"}, {"🩵", "TEAL"}, {"🟡", "YELLOW"}, {"🔴", "RED"},
+}
 
-	p1.match = match
-	p1.role = "p1"
-	p1.game = sharedGame
+// newMatch seats the connections in g, one per player (nil for AI seats).
+// Matches always run at the mid speed so every seat moves alike.
+func newMatch(g *game.Game, players []*GameServer, room *Room) *Match {
+	g.Paused = true // Start paused for countdown
+	m := &Match{Game: g, Players: players, Room: room}
+	for seat, gs := range players {
+		if gs == nil {
+			continue
+		}
+		gs.unwatch()
+		gs.dropRival("gone")
+		gs.stopRecording()
+		gs.match = m
+		gs.seat = seat
+		gs.dropGame()
+		gs.game = g
+		gs.started = false
+		gs.boosting = false
+		gs.tickCount = 0
+		gs.attachBot()
+		gs.handOverSeat()
+	}
+	return m
+}
 
-	p2.match = match
-	p2.role = "p2"
-	p2.game = sharedGame
+// humans returns the connections still playing in the match
+func (m *Match) humans() []*GameServer {
+	var out []*GameServer
+	for _, gs := range m.Players {
+		if gs != nil {
+			out = append(out, gs)
+		}
+	}
+	return out
+}
 
-	log.Printf("[PVP] 🔗 Both players attached to Match. P1: %s, P2: %s. Sending initial MATCH FOUND msg.\n", p1.user.Username, p2.user.Username)
+// names lists the human players for logs
+func (m *Match) names() string {
+	var names []string
+	for _, gs := range m.humans() {
+		names = append(names, gs.user.Username)
+	}
+	return strings.Join(names, ", ")
+}
 
-	// Initial broadcast with "MATCH FOUND"
-	st := sharedGame.GetGameStateSnapshot(true, false, "mid")
+// start announces the match with its board size, lists it for spectators
+// and runs the countdown
+func (m *Match) start() {
+	liveMatches.add(m)
+	gameConfig := m.Game.GetGameConfig()
+	st := m.Game.GetGameStateSnapshot(true, false, "mid")
 	st.Message = "⚔️ MATCH FOUND!"
+	if st.Series != nil {
+		st.Message = fmt.Sprintf("⚔️ GAME %d · BEST OF %d", st.Series.Game, st.Series.BestOf)
+	}
 	st.MessageType = "important"
-
-	p1.sendMsg(pb.ToProtoServerMessage("state", nil, &st, nil, nil, nil, "", "", 0))
-	p2.sendMsg(pb.ToProtoServerMessage("state", nil, &st, nil, nil, nil, "", "", 0))
-
-	log.Printf("[PVP] ⏱️ Starting 3-second countdown for %s vs %s\n", p1.user.Username, p2.user.Username)
-	go mm.runPVPCountdown(match)
-}
-
-func (mm *MatchMaker) CancelSearch(gs *GameServer) {
-	mm.mu.Lock()
-	defer mm.mu.Unlock()
-	if mm.waiting == gs {
-		mm.waiting = nil
-		gs.searching = false
-		log.Printf("👋 Player %s removed from matchmaking queue (disconnected/left)\n", gs.user.Username)
+	for _, gs := range m.humans() {
+		gs.sendMsg(pb.ToProtoServerMessage("config", &gameConfig, nil, nil, nil, nil, "", "", 0))
+		gs.sendMsg(pb.ToProtoServerMessage("state", nil, &st, nil, nil, nil, "", "", 0))
 	}
+
+	log.Printf("[PVP] ⏱️ Starting 3-second countdown for %s\n", m.names())
+	go m.runPVPCountdown()
 }
 
-func (mm *MatchMaker) runPVPCountdown(m *Match) {
+func (m *Match) runPVPCountdown() {
 	for i := 3; i > 0; i-- {
 		m.Mu.Lock()
 		if m.Closing {
@@ -293,20 +383,23 @@ func (mm *MatchMaker) runPVPCountdown(m *Match) {
 		}
 		m.Game.Message = fmt.Sprintf("🔥 STARTING IN %d...", i)
 		m.Game.MessageType = "important"
-		state := m.Game.GetGameStateSnapshot(true, false, m.P1.difficulty)
+		state := m.Game.GetGameStateSnapshot(true, false, "mid")
+		players := slices.Clone(m.Players)
+		m.spectators.publish(pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0))
 		m.Mu.Unlock()
 
-		log.Printf("[PVP] 🔔 Countdown: %d... (Players: %s, %s)\n", i, m.P1.user.Username, m.P2.user.Username)
-
-		// Send personalized state to P1
-		stateP1 := state
-		stateP1.Message = fmt.Sprintf("🟢 YOU ARE PLAYER 1 (GREEN)\nSTARTING IN %d...", i)
-		m.P1.sendMsg(pb.ToProtoServerMessage("state", nil, &stateP1, nil, nil, nil, "", "", 0))
+		log.Printf("[PVP] 🔔 Countdown: %d... (Players: %s)\n", i, m.names())
 
-		// Send personalized state to P2
-		stateP2 := state
-		stateP2.Message = fmt.Sprintf("🟣 YOU ARE PLAYER 2 (PURPLE)\nSTARTING IN %d...", i)
-		m.P2.sendMsg(pb.ToProtoServerMessage("state", nil, &stateP2, nil, nil, nil, "", "", 0))
+		// Send each player a state naming their seat and colour
+		for seat, gs := range players {
+			if gs == nil {
+				continue
+			}
+			color := seatColors[seat%len(seatColors)]
+			st := state
+			st.Message = fmt.Sprintf("%s YOU ARE PLAYER %d (%s)\nSTARTING IN %d...", color.emoji, seat+1, color.name, i)
+			gs.sendMsg(pb.ToProtoServerMessage("state", nil, &st, nil, nil, nil, "", "", 0))
+		}
 
 		time.Sleep(1 * time.Second)
 	}
@@ -321,16 +414,22 @@ func (mm *MatchMaker) runPVPCountdown(m *Match) {
 	m.Game.Paused = false
 	m.Game.TimerStarted = true
 	m.Game.StartTime = time.Now()
-	// Set both participants to started state so their update logic runs
-	m.P1.started = true
-	m.P2.started = true
+	// Set all participants to started state so their movement runs
+	humans := m.humans()
+	for _, gs := range humans {
+		gs.started = true
+		gs.sessionStart = time.Now()
+	}
+	if len(humans) > 0 {
+		humans[0].startRecording()
+	}
 	m.Mu.Unlock()
 
-	log.Printf("[PVP] 🚀 Rocket Start! Game is now UNPAUSED for %s vs %s\n", m.P1.user.Username, m.P2.user.Username)
-	go mm.runPVPGame(m)
+	log.Printf("[PVP] 🚀 Rocket Start! Game is now UNPAUSED for %s\n", m.names())
+	go m.runPVPGame()
 }
 
-func (mm *MatchMaker) runPVPGame(m *Match) {
+func (m *Match) runPVPGame() {
 	ticker := time.NewTicker(config.BaseTick)
 	defer ticker.Stop()
 
@@ -341,110 +440,202 @@ func (mm *MatchMaker) runPVPGame(m *Match) {
 			return
 		}
 
-		// Update game logic for both players
-		c1 := m.P1.update()
-		c2 := m.P2.update()
-		changed := c1 || c2
+		g := m.Game
+		g.HitPoints = nil
+		g.ScoreEvents = nil
+
+		// Every seat moves on its own timer; the world advances once per tick
+		changed := false
+		for _, gs := range m.Players {
+			if gs != nil && gs.stepPlayer() {
+				changed = true
+			}
+		}
+		var moved bool
+		m.aiTickCounts, moved = moveAI(g, m.aiTickCounts, func(i int) bool { return m.Players[i] != nil })
+		if stepWorld(g, &m.fireballTickCount) || moved {
+			changed = true
+		}
+		if g.Message != "" || len(g.HitPoints) > 0 || len(g.ScoreEvents) > 0 || g.GameOver {
+			changed = true
+		}
+
+		if g.GameOver && g.Series != nil {
+			g.Series.Record(g) // Before the final state, which shows the new score
+		}
 
 		if changed {
-			state := m.Game.GetGameStateSnapshot(true, false, m.P1.difficulty)
+			state := g.GetGameStateSnapshot(true, false, "mid")
 
-			// Broadcast to both
-			m.P1.sendMsg(pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0))
-			m.P2.sendMsg(pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0))
+			// Broadcast to every player, then queue it for the spectators
+			msg := pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0)
+			msg.State.Spectators = int32(m.spectators.count())
+			for _, gs := range m.humans() {
+				gs.sendMsg(msg)
+			}
+			m.spectators.publish(msg)
 
 			// Reset one-shot effects ONLY after broadcast
-			m.Game.ScoreEvents = nil
-			m.Game.HitPoints = nil
-			m.Game.Message = ""
-			m.Game.MessageType = ""
+			g.ScoreEvents = nil
+			g.HitPoints = nil
+			g.Message = ""
+			g.MessageType = ""
 		}
 
-		if m.Game.GameOver {
-			log.Printf("[PVP] 🏁 Match Over detected in loop (%s vs %s). Winner: %s\n", m.P1.user.Username, m.P2.user.Username, m.Game.Winner)
+		if g.GameOver {
+			log.Printf("[PVP] 🏁 Match Over detected in loop (%s). Winner: %s\n", m.names(), g.Winner)
 			m.Closing = true
+			m.stopRecording()
 
-			// Handle stats for both players
+			// Handle stats for every player
 			m.handleMatchOver()
+			m.end()
 
 			m.Mu.Unlock()
+			if m.Room != nil {
+				roomManager.matchOver(m.Room, m)
+			}
 			return
 		}
 		m.Mu.Unlock()
 	}
+}
 
-	// Double check: if match ended without disconnect, ensure roles are reset
-	m.Mu.Lock()
-	if !m.Closing {
-		m.handleMatchOver()
+// stopRecording records the final state of the shared game and closes its recording
+func (m *Match) stopRecording() {
+	if m.Game.Recorder == nil {
+		return
 	}
-	m.Mu.Unlock()
+	snapshot := m.Game.GetGameStateSnapshot(true, false, "mid")
+	m.Game.Recorder.Record(m.Game, snapshot)
+	m.Game.Recorder.Close()
+	m.Game.Recorder = nil
+	log.Println("⏹️ Recording stopped")
 }
 
+// handleMatchOver counts the match for every player and detaches them. It is
+// the only place match results reach the user stats.
 func (m *Match) handleMatchOver() {
 	gameObj := m.Game
+	kind := "pvp"
+	if m.Room != nil {
+		kind = "room"
+	}
 
-	// Update Stats for P1
-	if m.P1.user != nil && len(gameObj.Players) > 0 {
-		won := gameObj.Winner == "player"
-		updated, _ := userManager.UpdateStats(m.P1.user.Username, gameObj.Players[0].Score, won)
-		if updated != nil {
-			m.P1.user = updated
-			m.P1.sendMsg(pb.ToProtoServerMessage("auth_success", nil, nil, nil, nil, updated, "", "", 0))
-		}
+	log.Printf("[PVP] 🔓 Detaching players from match and resetting to solo state (%s)\n", m.names())
+	// Crucial: Detach players from the match so they can resume solo, re-queue
+	// or ask for a rematch. Done before sending the results, which clients may
+	// answer right away.
+	for _, gs := range m.humans() {
+		gs.match = nil
+		gs.seat = 0
+		gs.started = false
 	}
+	m.afterGame()
 
-	// Update Stats for P2
-	if m.P2.user != nil && len(gameObj.Players) > 1 {
-		won := gameObj.Winner == "ai"
-		updated, _ := userManager.UpdateStats(m.P2.user.Username, gameObj.Players[1].Score, won)
+	// Matchmaking games are rated, before the stats below fetch the users
+	// again; room matches are among friends and are not, and neither are
+	// games against a fill bot unless the server says so
+	if m.Room == nil && (!m.Bot || *botFillRated) {
+		m.updateRatings()
+	}
+
+	for seat, gs := range m.Players {
+		if gs == nil || gs.user == nil || seat >= len(gameObj.Players) {
+			continue
+		}
+		result := gameObj.Result(seat)
+		score := gameObj.Players[seat].Score
+		updated, _ := userManager.UpdateStats(gs.user.Username, score, result == "won")
 		if updated != nil {
-			m.P2.user = updated
-			m.P2.sendMsg(pb.ToProtoServerMessage("auth_success", nil, nil, nil, nil, updated, "", "", 0))
+			gs.user = updated
+			gs.sendMsg(pb.ToProtoServerMessage("auth_success", nil, nil, nil, nil, updated, "", "", 0))
+		}
+
+		if *detailedLogs {
+			game.RecordGameSession(gs.user.Username, gs.sessionStart, time.Now(), score, result, kind, gs.difficulty, 0, m.Bot)
 		}
 	}
+}
 
-	// Sessions
-	if *detailedLogs {
-		log.Printf("[PVP] 📝 Recording detailed game sessions for both players...\n")
-		if m.P1.user != nil && len(gameObj.Players) > 0 {
-			p1Res := "lost"
-			if gameObj.Winner == "player" {
-				p1Res = "won"
-			} else if gameObj.Winner == "draw" {
-				p1Res = "draw"
-			}
-			game.RecordGameSession(m.P1.user.Username, m.P1.sessionStart, time.Now(), gameObj.Players[0].Score, p1Res, "pvp", m.P1.difficulty)
-		}
-		if m.P2.user != nil && len(gameObj.Players) > 1 {
-			var p2Res string
-			switch gameObj.Winner {
-			case "ai":
-				p2Res = "won"
-			case "draw":
-				p2Res = "draw"
-			default:
-				p2Res = "lost"
-			}
-			game.RecordGameSession(m.P2.user.Username, m.P2.sessionStart, time.Now(), gameObj.Players[1].Score, p2Res, "pvp", m.P2.difficulty)
+// forfeit ends a one on one match lost by gs, who left it, and shows the
+// result to whoever is still there. m.Mu must be held.
+func (m *Match) forfeit(gs *GameServer) {
+	if m.Game.GameOver || len(m.Game.Players) != 2 {
+		return
+	}
+	m.Game.Forfeit(gs.seat)
+	if s := m.Game.Series; s != nil {
+		s.Record(m.Game)
+		s.Forfeit(gs.seat)
+	}
+	m.Game.SetMessage(fmt.Sprintf("🏳️ %s 掉线，判负", gs.user.Username))
+	state := m.Game.GetGameStateSnapshot(true, false, "mid")
+	msg := pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0)
+	for _, other := range m.humans() {
+		if other != gs {
+			other.sendMsg(msg)
 		}
 	}
+	m.spectators.publish(msg)
+}
 
-	log.Printf("[PVP] 🔓 Detaching players from match and resetting to solo state (%s, %s)\n", m.P1.user.Username, m.P2.user.Username)
-	// Crucial: Detach players from the match so they can resume solo or re-queue
-	m.P1.match = nil
-	m.P1.role = "solo"
-	m.P1.started = false
+// dropPlayer hands a leaving player's seat to the AI. It reports whether the
+// match ended because no one is left to play it.
+func (m *Match) dropPlayer(gs *GameServer) bool {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.Closing || gs.seat >= len(m.Players) || m.Players[gs.seat] != gs {
+		return false
+	}
 
-	m.P2.match = nil
-	m.P2.role = "solo"
-	m.P2.started = false
+	seat := gs.seat
+	p := m.Game.Players[seat]
+	if gs.user != nil && m.Game.TimerStarted {
+		// Leaving a running match counts as a loss
+		if updated, _ := userManager.UpdateStats(gs.user.Username, p.Score, false); updated != nil {
+			gs.user = updated
+		}
+	}
+	m.Players[seat] = nil
+	gs.match = nil
+	gs.seat = 0
+	gs.started = false
+
+	p.Brain, p.Controller = &game.HeuristicController{}, "heuristic"
+	p.Profile = game.ProfileNormal
+	m.Game.SetMessage(fmt.Sprintf("🤖 %s 离开了，AI 接管", p.Name))
+	log.Printf("[PVP] 🤖 %s left the match, the AI takes seat %d\n", gs.user.Username, seat+1)
+
+	if len(m.humans()) > 0 {
+		return false
+	}
+	log.Printf("[PVP] 📡 Match abandoned: no players left\n")
+	m.Closing = true
+	m.stopRecording()
+	m.end()
+	return true
 }
 
 func (gs *GameServer) handleAction(action string, mode string) {
 	var inputDir game.Point
 	var isDirection bool
 
+	// Spectators only watch; their own game waits until they stop
+	if gs.watching != nil && action != "find_match" && action != "cancel_match" {
+		return
+	}
+
+	// Solo settings must not touch a shared match game
+	if gs.match != nil {
+		switch {
+		case action == "pause", action == "start", action == "restart", strings.HasPrefix(action, "mode_"),
+			action == "opponents", strings.HasPrefix(action, "diff_"), action == "personality",
+			action == "opponent_brain", action == "toggleBerserker":
+			return
+		}
+	}
+
 	switch action {
 	case "up":
 		inputDir = game.Point{X: 0, Y: -1}
@@ -473,73 +664,71 @@ func (gs *GameServer) handleAction(action string, mode string) {
 		gs.stopRecording()
 
 		if gs.game.GameOver {
-			gs.game = game.NewGame(gs.game.Width, gs.game.Height)
-			gs.game.Mode = gs.currentMode
-			gs.game.TimerStarted = false
-			gs.started = false
-			gs.boosting = false
-			gs.tickCount = 0
-			gs.consecutiveKeyCount = 0
+			gs.resetGame()
 		}
 	case "mode_zen":
 		gs.currentMode = "zen"
 		gs.game.Mode = "zen"
 		if len(gs.game.Players) > 1 {
+			for _, p := range gs.game.Players[1:] {
+				game.ReleaseController(p.Brain)
+			}
 			gs.game.Players = gs.game.Players[:1] // Remove AI
 		}
 	case "mode_battle":
 		gs.currentMode = "battle"
 		gs.game.Mode = "battle"
 		if len(gs.game.Players) < 2 {
-			// Decide which AI brain to use based on dimensions
-			var brain game.Controller = &game.HeuristicController{}
-			controller := "heuristic"
-			if gs.game.NeuralNet != nil && gs.game.Width == config.StandardWidth && gs.game.Height == config.StandardHeight {
-				brain = &game.NeuralController{}
-				controller = "neural"
-			}
-
-			gs.game.Players = append(gs.game.Players, &game.Player{
-				Snake:       []game.Point{{X: gs.game.Width - 2, Y: gs.game.Height - 2}},
-				Direction:   game.Point{X: -1, Y: 0},
-				LastMoveDir: game.Point{X: -1, Y: 0},
-				Name:        "AI",
-				Brain:       brain,
-				Controller:  controller,
-			})
+			gs.configureAI()
 		}
-	case "diff_low":
-		if !gs.started || gs.game.GameOver {
-			gs.difficulty = "low"
+	case "opponents":
+		// The opponent count travels in the mode field, like the autoplay brain
+		if n, err := strconv.Atoi(mode); err == nil && n >= 1 && n <= game.MaxFFAOpponents &&
+			gs.currentMode == "battle" && (!gs.started || gs.game.GameOver) {
+			gs.opponents = n
+			gs.configureAI()
 		}
-	case "diff_mid":
+	case "diff_low", "diff_mid", "diff_high", "diff_expert", "diff_adaptive":
 		if !gs.started || gs.game.GameOver {
-			gs.difficulty = "mid"
+			gs.difficulty = strings.TrimPrefix(action, "diff_")
+			gs.applyDifficulty()
+			gs.applyBrain()
 		}
-	case "diff_high":
-		if !gs.started || gs.game.GameOver {
-			gs.difficulty = "high"
+	case "personality":
+		// The preset name travels in the mode field, like the autoplay brain
+		if _, ok := game.AIPersonalities[mode]; ok && (!gs.started || gs.game.GameOver) {
+			gs.personality = mode
+			gs.game.SetAIPersonality(mode)
+		}
+	case "opponent_brain":
+		// A WASM bot name ("wasm:<name>") in the mode field, empty for the built-in AI
+		if (mode == "" || slices.Contains(game.WasmBotNames(), mode)) && (!gs.started || gs.game.GameOver) {
+			gs.brain = mode
+			gs.configureAI()
 		}
 	case "auto":
 		if !gs.game.GameOver {
-			pIdx := 0
-			if gs.role == "p2" {
-				pIdx = 1
-			}
-			gs.game.TogglePlayerAutoPlay(pIdx, mode)
+			gs.game.TogglePlayerAutoPlay(gs.seat, mode)
 		}
 	case "find_match":
-		if gs.user != nil {
+		// Room members play through their room
+		if gs.user != nil && gs.room == nil {
 			pvpManager.FindMatch(gs)
 		}
 	case "fire":
 		if !gs.game.GameOver && !gs.game.Paused {
-			if gs.role == "p2" {
-				gs.game.FireByTypeIdx(1)
+			gs.game.FireByTypeIdx(gs.seat)
+		}
+	case "ai_debug":
+		// Solo only: in a match it would reveal the opponent's autoplay plans
+		if gs.match == nil {
+			gs.aiDebug = !gs.aiDebug
+			gs.game.ShowAIDebug = gs.aiDebug
+			if gs.aiDebug {
+				gs.game.SetMessage("🔍 AI 决策可视化：开启")
 			} else {
-				gs.game.FireByTypeIdx(0)
+				gs.game.SetMessage("🔍 AI 决策可视化：关闭")
 			}
-			gs.firedThisStep = true
 		}
 	case "toggleBerserker":
 		if !gs.game.GameOver {
@@ -556,15 +745,12 @@ func (gs *GameServer) handleAction(action string, mode string) {
 	}
 
 	if isDirection {
-		if !gs.started && gs.role != "p1" && gs.role != "p2" {
+		if !gs.started && gs.match == nil {
 			gs.startGame()
 		}
 
 		var dirChanged bool
-		pIdx := 0
-		if gs.role == "p2" {
-			pIdx = 1
-		}
+		pIdx := gs.seat
 
 		if pIdx < len(gs.game.Players) {
 			p := gs.game.Players[pIdx]
@@ -595,12 +781,8 @@ func (gs *GameServer) updateBoostingOnly() {
 	if gs.boosting && time.Since(gs.lastBoostKeyTime) > config.BoostTimeout {
 		gs.boosting = false
 	}
-	pIdx := 0
-	if gs.role == "p2" {
-		pIdx = 1
-	}
-	if pIdx < len(gs.game.Players) {
-		p := gs.game.Players[pIdx]
+	if gs.seat < len(gs.game.Players) {
+		p := gs.game.Players[gs.seat]
 		if mc, ok := p.Brain.(*game.ManualController); ok {
 			mc.SetBoosting(gs.boosting)
 		}
@@ -609,12 +791,8 @@ func (gs *GameServer) updateBoostingOnly() {
 
 // Check if any other player has the TIMEWARP effect active
 func (gs *GameServer) isOthersTimeWarpActive() bool {
-	myIdx := 0
-	if gs.role == "p2" {
-		myIdx = 1
-	}
 	for i, p := range gs.game.Players {
-		if i == myIdx {
+		if i == gs.seat {
 			continue
 		}
 		for _, e := range p.Effects {
@@ -626,159 +804,143 @@ func (gs *GameServer) isOthersTimeWarpActive() bool {
 	return false
 }
 
-func (gs *GameServer) update() bool {
-	changed := false
-
+// stepPlayer moves this connection's snake when its timer is due and records
+// the step. In a match every seat runs at the mid speed.
+func (gs *GameServer) stepPlayer() bool {
 	// Sync manual boosting state to game if not in AutoPlay
 	gs.updateBoostingOnly()
 
-	// 1. Clear per-frame events at the start of the update cycle
-	gs.game.HitPoints = nil
-	gs.game.ScoreEvents = nil
-
 	gs.tickCount++
+	if !gs.started {
+		return false
+	}
 
-	// 2. Movement logic (Only if started)
-	if gs.started {
-		// Determine Tick threshold based on difficulty and boosting
-		ticksNeeded := config.MidTicks
-		boostTicks := config.MidBoostTicks
-
-		switch gs.difficulty {
-		case "low":
-			ticksNeeded = config.LowTicks
-			boostTicks = config.LowBoostTicks
-		case "mid":
-			ticksNeeded = config.MidTicks
-			boostTicks = config.MidBoostTicks
-		case "high":
-			ticksNeeded = config.HighTicks
-			boostTicks = config.HighBoostTicks
-		}
-
-		isBoosted := false
-		if len(gs.game.Players) > 0 {
-			isBoosted = gs.game.Players[0].Boosting
-			if gs.role == "p2" && len(gs.game.Players) > 1 {
-				isBoosted = gs.game.Players[1].Boosting
-			}
-		}
-
-		if isBoosted {
-			ticksNeeded = boostTicks
-		}
-
-		// --- PROP EFFECTS: SPEED & TIMEWARP ---
-		// If OTHERS have TimeWarp, I am slowed down
-		if gs.isOthersTimeWarpActive() {
-			ticksNeeded = ticksNeeded * 2
-		}
-		// --------------------------------------
+	// Determine Tick threshold based on difficulty and boosting
+	speed := gs.difficulty
+	if gs.match != nil {
+		speed = "mid"
+	}
+	ticksNeeded := config.MidTicks
+	boostTicks := config.MidBoostTicks
+
+	switch speed {
+	case "low":
+		ticksNeeded = config.LowTicks
+		boostTicks = config.LowBoostTicks
+	case "mid":
+		ticksNeeded = config.MidTicks
+		boostTicks = config.MidBoostTicks
+	case "high", "expert":
+		ticksNeeded = config.HighTicks
+		boostTicks = config.HighBoostTicks
+	}
 
-		if gs.tickCount >= ticksNeeded {
-			gs.tickCount = 0
-			if !gs.game.GameOver && !gs.game.Paused {
-				playerIdx := 0
-				if gs.role == "p2" {
-					playerIdx = 1
-				}
-				if playerIdx < len(gs.game.Players) {
-					gs.game.UpdatePlayer(playerIdx)
-					changed = true
-				}
+	if gs.seat < len(gs.game.Players) && gs.game.Players[gs.seat].Boosting {
+		ticksNeeded = boostTicks
+	}
 
-				// --- Recording Logic ---
-				if gs.game.Recorder != nil && len(gs.game.Players) > 0 {
-					p1 := gs.game.Players[0]
-					snapshot := gs.game.GetGameStateSnapshot(gs.started, gs.boosting, gs.difficulty)
-
-					// Reward Calculation
-					reward := float64(p1.Score - gs.game.LastScore)
-					if gs.game.GameOver && gs.game.Winner != "player" {
-						reward -= 100.0 // Death penalty
-					} else if !gs.game.GameOver {
-						reward += 0.1 // Survival bonus
-					}
-					gs.game.LastScore = p1.Score
+	// --- PROP EFFECTS: SPEED & TIMEWARP ---
+	// If OTHERS have TimeWarp, I am slowed down
+	if gs.isOthersTimeWarpActive() {
+		ticksNeeded = ticksNeeded * 2
+	}
+	// --------------------------------------
 
-					// Capture Action
-					actionData := game.ActionData{
-						Direction: p1.LastMoveDir,
-						Boost:     p1.Boosting,
-						Fire:      gs.firedThisStep,
-					}
-					gs.firedThisStep = false // Reset for next step
-
-					rec := game.StepRecord{
-						StepID:    gs.stepID,
-						Timestamp: time.Now().UnixMilli(),
-						State:     snapshot,
-						Action:    actionData,
-						AIContext: gs.game.CurrentAIContext,
-						Reward:    reward,
-						Done:      gs.game.GameOver,
-					}
-					gs.game.Recorder.RecordStep(rec)
-					gs.stepID++
-				}
-				// -----------------------
-			}
-		}
+	if gs.tickCount < ticksNeeded {
+		return false
+	}
+	gs.tickCount = 0
+	if gs.game.GameOver || gs.game.Paused || gs.seat >= len(gs.game.Players) {
+		return false
+	}
+	gs.game.UpdatePlayer(gs.seat)
 
+	// --- Recording Logic ---
+	// Every player's action since the last record is captured; in
+	// PVP every server records into the match's shared recorder
+	if gs.game.Recorder != nil {
+		snapshot := gs.game.GetGameStateSnapshot(gs.started, gs.boosting, gs.difficulty)
+		gs.game.Recorder.Record(gs.game, snapshot)
 	}
+	// -----------------------
+	return true
+}
 
-	// Move AI snake independently (if any)
-	if gs.started && !gs.game.IsPVP && len(gs.game.Players) > 1 {
-		gs.aiTickCount++
-		aiTicksNeeded := config.MidTicks
-		if gs.game.Players[1].Boosting {
-			aiTicksNeeded = config.MidBoostTicks
-		}
-
-		// If P1 has TimeWarp, AI is slowed
-		p1HasWarp := false
-		if len(gs.game.Players) > 0 {
-			for _, e := range gs.game.Players[0].Effects {
-				if e.Type == game.EffectTimeWarp {
-					p1HasWarp = true
-					break
-				}
-			}
+// moveAI moves every AI-driven snake on its own timer. counts is indexed like
+// g.Players and human reports the seats a connection drives.
+func moveAI(g *game.Game, counts []int, human func(int) bool) ([]int, bool) {
+	changed := false
+	for len(counts) < len(g.Players) {
+		counts = append(counts, 0)
+	}
+	for i := range g.Players {
+		if human(i) {
+			continue
 		}
-		if p1HasWarp {
+		counts[i]++
+		aiTicksNeeded := int(g.GetAIMoveIntervalFor(i) / config.BaseTick)
+
+		// A TimeWarp held by anyone else slows this AI down
+		if g.IsTimeWarpedFor(i) {
 			aiTicksNeeded = aiTicksNeeded * 2
 		}
 
-		if gs.aiTickCount >= aiTicksNeeded {
-			gs.aiTickCount = 0
-			if !gs.game.GameOver && !gs.game.Paused {
-				gs.game.UpdatePlayer(1)
+		if counts[i] >= aiTicksNeeded {
+			counts[i] = 0
+			if !g.GameOver && !g.Paused {
+				g.UpdatePlayer(i)
 				changed = true
 			}
 		}
 	}
+	return counts, changed
+}
 
-	// Periodic World Update (Food, Obstacles, Time Limit) - always check if game is active
-	if !gs.game.GameOver && !gs.game.Paused && (gs.started || gs.game.Mode == "pvp") {
-		gs.game.TrySpawnFood()
-		gs.game.TrySpawnProp()
-		gs.game.TrySpawnObstacle()
-		gs.game.CheckTimeLimit()
-		// Note: we don't necessarily set changed=true here to avoid flooding,
-		// but if food or time changed significantly it will be sent in the next snake move anyway.
+// stepWorld runs the periodic world update (food, props, obstacles, time
+// limit) and moves the fireballs at FireballSpeed. It reports whether the
+// fireballs moved.
+func stepWorld(g *game.Game, fireballTicks *int) bool {
+	if g.GameOver || g.Paused {
+		return false
+	}
+	g.TrySpawnFood()
+	g.TrySpawnProp()
+	g.TrySpawnObstacle()
+	g.CheckTimeLimit()
+	g.UpdateAdaptiveDifficulty()
+	// Note: we don't necessarily set changed=true here to avoid flooding,
+	// but if food or time changed significantly it will be sent in the next snake move anyway.
+
+	*fireballTicks++
+	if *fireballTicks < int(config.FireballSpeed/config.BaseTick) {
+		return false
 	}
+	*fireballTicks = 0
+	if g.GameOver {
+		return false
+	}
+	g.UpdateFireballs()
+	return true
+}
 
-	// Update fireballs independently at FireballSpeed
-	if gs.started {
-		gs.fireballTickCount++
-		fbTicks := int(config.FireballSpeed / config.BaseTick)
-		if gs.fireballTickCount >= fbTicks {
-			gs.fireballTickCount = 0
-			if !gs.game.GameOver && !gs.game.Paused {
-				gs.game.UpdateFireballs()
-				changed = true
-			}
-		}
+func (gs *GameServer) update() bool {
+	// 1. Clear per-frame events at the start of the update cycle
+	gs.game.HitPoints = nil
+	gs.game.ScoreEvents = nil
+
+	// 2. Movement logic (Only if started)
+	changed := gs.stepPlayer()
+
+	// Move AI snakes independently (if any), each on its own timer
+	if gs.started && !gs.game.IsPVP && len(gs.game.Players) > 1 {
+		var moved bool
+		gs.aiTickCounts, moved = moveAI(gs.game, gs.aiTickCounts, func(i int) bool { return i == 0 })
+		changed = changed || moved
+	}
+
+	// Periodic World Update and fireballs - always check if game is active
+	if gs.started && stepWorld(gs.game, &gs.fireballTickCount) {
+		changed = true
 	}
 
 	// IMPORTANT: Any message or special event also counts as a change that MUST be sent
@@ -786,55 +948,24 @@ func (gs *GameServer) update() bool {
 		changed = true
 	}
 
-	// Handle Game Over logic (Stats, Leaderboard, Recording)
+	// Handle Game Over logic (Stats, Leaderboard, Recording). Matches are
+	// counted by handleMatchOver instead.
 	if gs.game.GameOver {
-		// 1. Stop recording if it's still running
-		if gs.game.Recorder != nil && len(gs.game.Players) > 0 {
-			p1 := gs.game.Players[0]
-			// Capture final state
+		// 1. Stop recording if it's still running (the final step is only
+		// recorded here when the game did not end on this player's move)
+		if gs.game.Recorder != nil {
 			snapshot := gs.game.GetGameStateSnapshot(gs.started, gs.boosting, gs.difficulty)
-			reward := float64(p1.Score - gs.game.LastScore)
-
-			rec := game.StepRecord{
-				StepID:    gs.stepID,
-				Timestamp: time.Now().UnixMilli(),
-				State:     snapshot,
-				Action: game.ActionData{
-					Direction: p1.LastMoveDir,
-					Boost:     p1.Boosting,
-					Fire:      false,
-				},
-				AIContext: gs.game.CurrentAIContext,
-				Reward:    reward,
-				Done:      true,
-			}
-			gs.game.Recorder.RecordStep(rec)
+			gs.game.Recorder.Record(gs.game, snapshot)
 			gs.stopRecording()
 		}
 
 		// 2. Automatic score/stats submission (only once per game session)
 		// We use gs.started as a flag since it's set to false on restart
 		if gs.started && gs.user != nil && len(gs.game.Players) > 0 {
-			log.Printf("🏁 Game Over detected for user %s. Processing stats (Winner: %s, IsPVP: %v)...\n", gs.user.Username, gs.game.Winner, gs.game.IsPVP)
+			log.Printf("🏁 Game Over detected for user %s. Processing stats (Winner: %s)...\n", gs.user.Username, gs.game.Winner)
 			isBattle := gs.game.Mode == "battle"
-
-			// In PVP, 'player' means P1 wins, 'ai' means P2 wins
-			won := false
-			if gs.game.IsPVP {
-				switch gs.role {
-				case "p1":
-					won = gs.game.Winner == "player"
-				case "p2":
-					won = gs.game.Winner == "ai"
-				}
-			} else {
-				won = gs.game.Winner == "player"
-			}
-
+			won := gs.game.Winner == "player"
 			p1Score := gs.game.Players[0].Score
-			if gs.role == "p2" && len(gs.game.Players) > 1 {
-				p1Score = gs.game.Players[1].Score
-			}
 
 			// Always update stats
 			updatedUser, err := userManager.UpdateStats(gs.user.Username, p1Score, won)
@@ -847,11 +978,20 @@ func (gs *GameServer) update() bool {
 			// Only Battle Mode goes to the Global Leaderboard
 			if isBattle && p1Score > 0 {
 				log.Printf("🏆 Submitting Battle Mode score (%d) to leaderboard...\n", p1Score)
-				if lbManager.AddEntry(gs.user.Username, p1Score, gs.difficulty, gs.game.Mode) {
+				if lbManager.AddEntry(gs.user.Username, p1Score, gs.difficulty, gs.game.Mode, gs.game.Opponents()) {
 					gs.lbUpdated = true
 				}
 			}
 
+			// Carry the dynamic difficulty level over to the next session
+			if gs.game.Adaptive != nil && !gs.game.IsPVP {
+				skill := gs.game.Adaptive.SessionEstimate(won)
+				if err := userManager.UpdateSkillEstimate(gs.user.Username, skill); err == nil {
+					gs.user.SkillEstimate = skill
+					log.Printf("🎚️ Adaptive level for %s: %d (skill estimate %.2f)\n", gs.user.Username, gs.game.AdaptiveLevel(), skill)
+				}
+			}
+
 			// Detailed session logging if enabled
 			if *detailedLogs {
 				game.RecordGameSession(
@@ -862,6 +1002,8 @@ func (gs *GameServer) update() bool {
 					gs.game.Winner,
 					gs.game.Mode,
 					gs.difficulty,
+					gs.game.AdaptiveLevel(),
+					false,
 				)
 			}
 
@@ -928,11 +1070,79 @@ func broadcastSessionCount() {
 	clientsMu.RUnlock()
 
 	msg := pb.ToProtoServerMessage("update_counts", nil, nil, nil, nil, nil, "", "", count)
+	msg.QueueSize = int32(pvpManager.Size())
 	for _, gs := range targets {
 		go gs.sendMsg(msg)
 	}
 }
 
+// kickOtherSessions closes any other connection logged in as username
+func kickOtherSessions(username, connID string) {
+	clientsMu.Lock()
+	var killee *GameServer
+	for id, c := range clients {
+		if c.user != nil && c.user.Username == username && id != connID {
+			log.Printf("⚠️ Found old session for user: %s (connID: %s)\n", username, id)
+			killee = c
+			break
+		}
+	}
+	clientsMu.Unlock()
+
+	if killee != nil {
+		log.Printf("⚠️ Kicking old session for user: %s\n", username)
+		go func(c *GameServer) {
+			resumables.forget(c) // Replaced, not dropped
+			msg := pb.ToProtoServerMessage("error", nil, nil, nil, nil, nil, "Logged in from another location.", "", 0)
+			c.sendMsg(msg)
+			if c.close != nil {
+				c.close()
+			}
+		}(killee)
+	}
+}
+
+// disconnect releases what a closed connection held: its matchmaking slot,
+// its room, its PVP match and its recording
+func (gs *GameServer) disconnect() {
+	// Fix: Remove from matchmaking queue if waiting
+	pvpManager.CancelSearch(gs)
+
+	// A room match goes on with the AI in the seat
+	roomManager.Leave(gs)
+	gs.unwatch()
+
+	// Fix: Handle PVP match termination if in game
+	// (handleMatchOver detaches gs, so keep hold of the match)
+	if m := gs.match; m != nil {
+		m.Mu.Lock()
+		if !m.Closing {
+			m.Closing = true
+			log.Printf("[PVP] 📡 Match terminated due to %s disconnecting\n", gs.user.Username)
+			m.forfeit(gs)
+			m.handleMatchOver() // Ensure P2 gets reset to solo mode
+			m.end()
+		}
+		m.Mu.Unlock()
+	}
+	gs.dropRival("gone")
+
+	gs.ticker.Stop()
+	gs.stopRecording()
+	gs.dropGame()
+}
+
+// endSession ends a web session for good: it leaves the client list and
+// releases what it held
+func (gs *GameServer) endSession() {
+	clientsMu.Lock()
+	delete(clients, gs.connID)
+	clientsMu.Unlock()
+	broadcastSessionCount()
+
+	gs.disconnect()
+}
+
 func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -968,76 +1178,77 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		log.Printf("[Server] Mobile detected. Using standard game space: %dx%d\n", width, height)
 	}
 
-	gs := NewGameServer(connID, width, height)
-
-	// Mutex to protect concurrent writes to the WebSocket connection
-	gs.sendMsg = func(v *pb.ServerMessage) error {
-		gs.writeMu.Lock()
-		defer gs.writeMu.Unlock()
-		data, err := proto.Marshal(v)
-		if err != nil {
-			return err
+	// A dropped connection's session is taken over as it is
+	gs := resumables.resume(r.URL.Query().Get("resume"), conn)
+	resumed := gs != nil
+	if resumed {
+		log.Printf("📶 %s reconnected and resumes the session\n", gs.name())
+	} else {
+		gs = NewGameServer(connID, width, height)
+		gs.conn = conn
+
+		// Mutex to protect concurrent writes to the WebSocket connection
+		gs.sendMsg = func(v *pb.ServerMessage) error {
+			gs.writeMu.Lock()
+			defer gs.writeMu.Unlock()
+			data, err := proto.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return gs.conn.WriteMessage(websocket.BinaryMessage, data)
 		}
-		return conn.WriteMessage(websocket.BinaryMessage, data)
-	}
 
-	gs.close = func() {
-		conn.Close()
-	}
-
-	// Check for player limit
-	clientsMu.RLock()
-	currentCount := len(clients)
-	clientsMu.RUnlock()
-
-	if currentCount >= MaxPlayers {
-		log.Printf("🚫 Connection rejected: server full (%d/%d)\n", currentCount, MaxPlayers)
-		msg := pb.ToProtoServerMessage("error", nil, nil, nil, nil, nil, "Server is full (500/500). Please wait for a player to leave and try refreshing.", "", 0)
-		data, _ := proto.Marshal(msg)
-		conn.WriteMessage(websocket.BinaryMessage, data)
-		return
-	}
+		gs.close = func() {
+			gs.writeMu.Lock()
+			c := gs.conn
+			gs.writeMu.Unlock()
+			c.Close()
+		}
 
-	// Add to global client tracking for broadcasts
-	clientsMu.Lock()
-	clients[connID] = gs
-	clientsMu.Unlock()
+		// Check for player limit
+		clientsMu.RLock()
+		currentCount := len(clients)
+		clientsMu.RUnlock()
 
-	broadcastSessionCount()
+		if currentCount >= MaxPlayers {
+			log.Printf("🚫 Connection rejected: server full (%d/%d)\n", currentCount, MaxPlayers)
+			msg := pb.ToProtoServerMessage("error", nil, nil, nil, nil, nil, "Server is full (500/500). Please wait for a player to leave and try refreshing.", "", 0)
+			data, _ := proto.Marshal(msg)
+			conn.WriteMessage(websocket.BinaryMessage, data)
+			return
+		}
 
-	defer func() {
+		// Add to global client tracking for broadcasts
 		clientsMu.Lock()
-		delete(clients, connID)
+		clients[connID] = gs
 		clientsMu.Unlock()
-		broadcastSessionCount()
 
-		// Fix: Remove from matchmaking queue if waiting
-		pvpManager.CancelSearch(gs)
+		broadcastSessionCount()
+	}
 
-		// Fix: Handle PVP match termination if in game
-		if gs.match != nil {
-			gs.match.Mu.Lock()
-			if !gs.match.Closing {
-				gs.match.Closing = true
-				log.Printf("[PVP] 📡 Match terminated due to %s disconnecting\n", gs.user.Username)
-				gs.match.handleMatchOver() // Ensure P2 gets reset to solo mode
-			}
-			gs.match.Mu.Unlock()
+	// A dropped connection may still come back (see resume.go)
+	defer func() {
+		if !gs.suspend(conn) {
+			gs.endSession()
 		}
-
-		gs.ticker.Stop()
-		gs.stopRecording()
 	}()
 
-	// Send initial config
-	gameConfig := gs.game.GetGameConfig()
-	gs.sendMsg(pb.ToProtoServerMessage("config", &gameConfig, nil, nil, nil, nil, "", "", 0))
+	if resumed {
+		gs.showResumed()
+	} else {
+		// Send initial config
+		gameConfig := gs.game.GetGameConfig()
+		gs.sendMsg(pb.ToProtoServerMessage("config", &gameConfig, nil, nil, nil, nil, "", "", 0))
+	}
 
 	// Send leaderboards
 	gs.sendMsg(pb.ToProtoServerMessage("leaderboard", nil, nil, lbManager.GetEntries(), lbManager.GetWinRateEntries(), nil, "", "", 0))
 
-	initialState := gs.getGameState()
-	gs.sendMsg(pb.ToProtoServerMessage("state", nil, &initialState, nil, nil, nil, "", "", 0))
+	if !resumed {
+		initialState := gs.getGameState()
+		gs.sendMsg(pb.ToProtoServerMessage("state", nil, &initialState, nil, nil, nil, "", "", 0))
+	}
+	resumables.issue(gs, resumed)
 
 	// Signal for loop termination
 	done := make(chan struct{})
@@ -1068,30 +1279,12 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 				} else {
 					log.Printf("✅ Login success: %s\n", msg.Username)
 
-					// Kick old connection for this user if it exists
-					clientsMu.Lock()
-					var killee *GameServer
-					for id, c := range clients {
-						if c.user != nil && c.user.Username == msg.Username && id != connID {
-							log.Printf("⚠️ Found old session for user: %s (connID: %s)\n", msg.Username, id)
-							killee = c
-							break
-						}
-					}
-					clientsMu.Unlock()
-
-					if killee != nil {
-						log.Printf("⚠️ Kicking old session for user: %s\n", msg.Username)
-						go func(c *GameServer) {
-							msg := pb.ToProtoServerMessage("error", nil, nil, nil, nil, nil, "Logged in from another location.", "", 0)
-							c.sendMsg(msg)
-							if c.close != nil {
-								c.close()
-							}
-						}(killee)
-					}
+					kickOtherSessions(msg.Username, gs.connID)
 
 					gs.user = user
+					if gs.difficulty == "adaptive" && !gs.started {
+						gs.applyDifficulty()
+					}
 					gs.sendMsg(pb.ToProtoServerMessage("auth_success", nil, nil, nil, nil, user, "", "", 0))
 				}
 				continue
@@ -1129,6 +1322,7 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 			if msg.Action == "logout" {
 				log.Printf("👋 Logout received for user: %v\n", gs.user)
+				resumables.forget(gs) // Leaving on purpose
 				if gs.close != nil {
 					gs.close()
 				}
@@ -1137,13 +1331,19 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 			if msg.Action == "submit_score" {
 				// No-op: automatic on game over
+			} else if strings.HasPrefix(msg.Action, "room_") {
+				gs.handleRoomAction(&msg)
+			} else if strings.HasPrefix(msg.Action, "spectate") {
+				gs.handleSpectateAction(&msg)
+			} else if strings.HasPrefix(msg.Action, "rematch") {
+				gs.handleRematchAction(&msg)
 			} else {
 				// Only allow game actions if not in a state where we should be logged in?
 				// For now, let's just let it run, but typically you'd want auth for leaderboard.
 				gs.handleAction(msg.Action, msg.Mode)
 			}
 			// Trigger immediate state update for UI responsiveness
-			if gs.match == nil && !gs.searching {
+			if gs.match == nil && !gs.searching && gs.watching == nil {
 				state := gs.getGameState()
 				gs.sendMsg(pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0))
 			}
@@ -1156,6 +1356,10 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		case <-done:
 			return
 		case <-gs.ticker.C:
+			// A resumed connection took over this session
+			if !gs.owns(conn) {
+				return
+			}
 			// If in match, P1's runPVPGame goroutine handles the updates and broadcasts.
 			if gs.match != nil {
 				// CRITICAL: Even in match mode, we must sync the LOCAL boosting state of this connection
@@ -1163,6 +1367,10 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 				gs.updateBoostingOnly()
 				continue
 			}
+			// A spectator's own game waits while the watched match streams in
+			if gs.watching != nil {
+				continue
+			}
 
 			changed := gs.update()
 
@@ -1197,6 +1405,20 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// adminAuthorized checks the ?key= secret of the admin endpoints
+func adminAuthorized(w http.ResponseWriter, r *http.Request) bool {
+	// Simple basic security: checking for a secret query param
+	secret := os.Getenv("ADMIN_SECRET")
+	if secret == "" {
+		secret = "admin123" // Fallback if not configured
+	}
+	if r.URL.Query().Get("key") != secret {
+		http.Error(w, "Unauthorized. Please provide valid ?key=...", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
 func main() {
 	flag.Parse()
 
@@ -1205,7 +1427,26 @@ func main() {
 		log.Println("⚠️  No .env file found, relying on system environment variables")
 	}
 
+	if *rewardConfig != "" {
+		if err := game.LoadRewardPresets(*rewardConfig); err != nil {
+			log.Fatalf("❌ Failed to load reward presets: %v", err)
+		}
+		log.Printf("🎯 Reward presets: %v", game.RewardPresetNames())
+	}
+
 	game.InitDB()
+	if names, err := game.LoadWasmBots(*wasmBotDir); err != nil {
+		log.Printf("⚠️ Some WASM bots failed to load: %v", err)
+	} else if len(names) > 0 {
+		log.Printf("🧩 WASM bots: %v", names)
+	}
+	game.ConfigureInference(game.InferenceOptions{
+		Workers:   *inferenceWorkers,
+		BatchSize: *inferenceBatch,
+		BatchWait: *inferenceWait,
+	})
+	pvpManager.queue.FillAfter = *botFillAfter
+	go pvpManager.Run()
 
 	// Serve static files
 	fs := http.FileServer(http.Dir("web/static"))
@@ -1219,16 +1460,19 @@ func main() {
 	// WebSocket endpoint
 	http.HandleFunc("/ws", handleWebSocket)
 
+	// Bot API (docs/BOT_API.md)
+	http.HandleFunc("/ws/bot", handleBotWebSocket)
+
+	// Neural AI inference metrics (queue depth, batch size, latency)
+	http.HandleFunc("/metrics/inference", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(game.InferenceMetrics())
+	})
+
 	port := ":8080"
 	log.Printf("🚀 Snake Game Web Server starting on http://localhost%s\n", port)
 	http.HandleFunc("/admin/feedback", func(w http.ResponseWriter, r *http.Request) {
-		// Simple basic security: checking for a secret query param
-		secret := os.Getenv("ADMIN_SECRET")
-		if secret == "" {
-			secret = "admin123" // Fallback if not configured
-		}
-		if r.URL.Query().Get("key") != secret {
-			http.Error(w, "Unauthorized. Please provide valid ?key=...", http.StatusUnauthorized)
+		if !adminAuthorized(w, r) {
 			return
 		}
 
@@ -1250,6 +1494,99 @@ func main() {
 		fmt.Fprintf(w, "</table></body></html>")
 	})
 
+	// Model registry: list, hot reload (?reload=1) and A/B split (?ab=name1,name2, empty to stop)
+	http.HandleFunc("/admin/models", func(w http.ResponseWriter, r *http.Request) {
+		if !adminAuthorized(w, r) {
+			return
+		}
+		if game.Models == nil {
+			http.Error(w, "Neural inference is not running", http.StatusServiceUnavailable)
+			return
+		}
+
+		resp := map[string]interface{}{}
+		if r.URL.Query().Get("reload") != "" {
+			changed, err := game.Models.Reload()
+			resp["changed"] = changed
+			if err != nil {
+				resp["reload_error"] = err.Error()
+			}
+		}
+		if r.URL.Query().Has("ab") {
+			var names []string
+			if ab := r.URL.Query().Get("ab"); ab != "" {
+				names = strings.Split(ab, ",")
+			}
+			if err := game.Models.SetABTest(names); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		resp["default"] = game.Models.Default()
+		resp["ab_test"] = game.Models.ABTest()
+		resp["models"] = game.Models.List()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	// Bot accounts: ?name=... creates one and returns its API token (shown once)
+	http.HandleFunc("/admin/bots", func(w http.ResponseWriter, r *http.Request) {
+		if !adminAuthorized(w, r) {
+			return
+		}
+		name := strings.TrimSpace(r.URL.Query().Get("name"))
+		if name == "" {
+			http.Error(w, "Missing ?name=...", http.StatusBadRequest)
+			return
+		}
+		token, err := userManager.RegisterBot(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Printf("🤖 Bot account created: %s\n", name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"username": name, "token": token})
+	})
+
+	// WASM bots offered as battle opponents
+	http.HandleFunc("/api/wasm-bots", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(game.WasmBotNames())
+	})
+
+	// WASM bot upload: POST the module to ?name=... to add or replace a bot
+	http.HandleFunc("/admin/wasm", func(w http.ResponseWriter, r *http.Request) {
+		if !adminAuthorized(w, r) {
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST the .wasm module", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimSpace(r.URL.Query().Get("name"))
+		wasm, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.WasmMaxModuleSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := game.RegisterWasmBot(name, wasm); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Keep the module so the bot survives restarts
+		if err := os.MkdirAll(*wasmBotDir, 0755); err == nil {
+			err = os.WriteFile(filepath.Join(*wasmBotDir, name+".wasm"), wasm, 0644)
+		}
+		if err != nil {
+			log.Printf("⚠️ WASM bot %s registered but not saved: %v\n", name, err)
+		}
+		log.Printf("🧩 WASM bot registered: %s\n", name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"controller": game.WasmBotPrefix + name})
+	})
+
 	log.Println("Server starting on :8080")
 	log.Fatal(http.ListenAndServe(":8080", nil))
 }
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+	"github.com/trytobebee/snake_go/pkg/game"
+	pb "github.com/trytobebee/snake_go/pkg/proto"
+)
+
+// PVP Matchmaking: players wait in a rating queue (game.MatchQueue). The
+// queue is matched whenever someone joins and once a second, when everyone
+// waiting also hears how the search is going. Whoever waited -bot-fill
+// without a match plays the AI instead.
+
+const (
+	matchmakingInterval = time.Second
+	fillBotName         = "🤖 Bot"
+)
+
+type MatchMaker struct {
+	mu      sync.Mutex
+	queue   game.MatchQueue
+	waiting map[string]*GameServer // Searching connection of each queued user
+}
+
+var pvpManager = &MatchMaker{waiting: make(map[string]*GameServer)}
+
+// FindMatch queues gs. If the same user is already searching from another
+// session, this session takes its place in the queue.
+func (mm *MatchMaker) FindMatch(gs *GameServer) {
+	name := gs.user.Username
+	gs.dropRival("gone")
+	mm.mu.Lock()
+	if old := mm.waiting[name]; old != nil && old != gs {
+		old.searching = false
+		log.Printf("[PVP] ⚠️ Player %s searches from another session, which takes over the queue spot\n", name)
+	}
+	mm.queue.Add(game.QueueEntry{Name: name, Rating: gs.user.Rating, Joined: time.Now()})
+	mm.waiting[name] = gs
+	gs.searching = true
+	size := mm.queue.Len()
+	mm.mu.Unlock()
+
+	log.Printf("[PVP] ⏳ Player %s (rating %.0f) entered matchmaking queue (%d waiting)\n", name, gs.user.Rating, size)
+	broadcastSessionCount()
+	mm.pair()
+	mm.sendStatus()
+}
+
+func (mm *MatchMaker) CancelSearch(gs *GameServer) {
+	mm.mu.Lock()
+	if gs.user == nil || mm.waiting[gs.user.Username] != gs {
+		mm.mu.Unlock()
+		return
+	}
+	mm.queue.Remove(gs.user.Username)
+	delete(mm.waiting, gs.user.Username)
+	gs.searching = false
+	mm.mu.Unlock()
+
+	log.Printf("👋 Player %s removed from matchmaking queue (disconnected/left)\n", gs.user.Username)
+	broadcastSessionCount()
+}
+
+// Size is the number of players searching
+func (mm *MatchMaker) Size() int {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	return mm.queue.Len()
+}
+
+// Run keeps matching the queue and reporting to the players waiting in it
+func (mm *MatchMaker) Run() {
+	for range time.Tick(matchmakingInterval) {
+		mm.pair()
+		mm.sendStatus()
+	}
+}
+
+// pair starts a match for every two players close enough in rating by now,
+// and a bot match for everyone who waited too long for one
+func (mm *MatchMaker) pair() {
+	now := time.Now()
+	mm.mu.Lock()
+	var pairs [][2]*GameServer
+	for _, p := range mm.queue.Match(now) {
+		p1, p2 := mm.waiting[p[0].Name], mm.waiting[p[1].Name]
+		delete(mm.waiting, p[0].Name)
+		delete(mm.waiting, p[1].Name)
+		p1.searching = false
+		p2.searching = false
+		pairs = append(pairs, [2]*GameServer{p1, p2})
+	}
+	var lonely []*GameServer
+	for _, e := range mm.queue.Overdue(now) {
+		gs := mm.waiting[e.Name]
+		delete(mm.waiting, e.Name)
+		gs.searching = false
+		lonely = append(lonely, gs)
+	}
+	mm.mu.Unlock()
+
+	for _, p := range pairs {
+		startMatch(p[0], p[1])
+	}
+	for _, gs := range lonely {
+		startBotMatch(gs)
+	}
+	if len(pairs)+len(lonely) > 0 {
+		broadcastSessionCount()
+	}
+}
+
+// sendStatus tells every waiting player how long they waited and may still wait
+func (mm *MatchMaker) sendStatus() {
+	now := time.Now()
+	mm.mu.Lock()
+	msgs := make(map[*GameServer]*pb.ServerMessage, len(mm.waiting))
+	for name, gs := range mm.waiting {
+		e, ok := mm.queue.Entry(name)
+		if !ok {
+			continue
+		}
+		waited := now.Sub(e.Joined)
+		msg := pb.ToProtoServerMessage("queue", nil, nil, nil, nil, nil, "", "", 0)
+		msg.Queue = &pb.QueueStatus{
+			Size:     int32(mm.queue.Len()),
+			Waited:   int32(waited.Seconds()),
+			Estimate: int32(math.Ceil(mm.queue.EstimatedWait(name, now).Seconds())),
+			Rating:   int32(math.Round(e.Rating)),
+			Gap:      int32(game.MatchGap(waited)),
+		}
+		msgs[gs] = msg
+	}
+	mm.mu.Unlock()
+
+	for gs, msg := range msgs {
+		gs.sendMsg(msg)
+	}
+}
+
+// startMatch plays p1 against p2, p1 being the one who waited longer
+func startMatch(p1, p2 *GameServer) {
+	log.Printf("[PVP] ⚔️ Match found: %s (P1, %.0f) vs %s (P2, %.0f). Initializing shared game state...\n",
+		p1.user.Username, p1.user.Rating, p2.user.Username, p2.user.Rating)
+
+	log.Printf("[PVP] 🔗 Attaching P1: %s, P2: %s. Sending initial MATCH FOUND msg.\n", p1.user.Username, p2.user.Username)
+	startPair(p1, p2, nil)
+}
+
+// startBotMatch plays gs against an AI about as strong as their rating
+func startBotMatch(gs *GameServer) {
+	log.Printf("[PVP] 🤖 No opponent for %s (%.0f) in time, filling in a bot\n", gs.user.Username, gs.user.Rating)
+
+	sharedGame := game.NewGame(config.StandardWidth, config.StandardHeight)
+	sharedGame.SetupPVP(gs.user.Username, fillBotName)
+	sharedGame.SetupBotOpponent(1, gs.user.Rating)
+
+	match := newMatch(sharedGame, []*GameServer{gs, nil}, nil)
+	match.Bot = true
+	match.start()
+}
+
+// updateRatings moves both players' ratings after a matchmaking game. A fill
+// bot plays at its opponent's rating and has no rating of its own to move.
+func (m *Match) updateRatings() {
+	if len(m.Players) != 2 || m.Players[0] == nil || (m.Players[1] == nil && !m.Bot) {
+		return
+	}
+	a := m.Players[0].user
+	if a == nil {
+		return
+	}
+	score := game.ResultScore(m.Game.Result(0))
+	if m.Bot {
+		ra := a.Rating + game.RatingChange(a.Rating, a.Rating, score, a.RatedGames)
+		if err := userManager.UpdateRating(a.Username, ra); err != nil {
+			log.Printf("❌ Failed to update rating of %s: %v\n", a.Username, err)
+		}
+		log.Printf("[PVP] 📈 Rating: %s %.0f → %.0f (against a bot)\n", a.Username, a.Rating, ra)
+		return
+	}
+	b := m.Players[1].user
+	if b == nil {
+		return
+	}
+	ra := a.Rating + game.RatingChange(a.Rating, b.Rating, score, a.RatedGames)
+	rb := b.Rating + game.RatingChange(b.Rating, a.Rating, 1-score, b.RatedGames)
+	if err := userManager.UpdateRating(a.Username, ra); err != nil {
+		log.Printf("❌ Failed to update rating of %s: %v\n", a.Username, err)
+	}
+	if err := userManager.UpdateRating(b.Username, rb); err != nil {
+		log.Printf("❌ Failed to update rating of %s: %v\n", b.Username, err)
+	}
+	log.Printf("[PVP] 📈 Ratings: %s %.0f → %.0f, %s %.0f → %.0f\n", a.Username, a.Rating, ra, b.Username, b.Rating, rb)
+}
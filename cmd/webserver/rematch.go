@@ -0,0 +1,159 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+	"github.com/trytobebee/snake_go/pkg/game"
+	pb "github.com/trytobebee/snake_go/pkg/proto"
+)
+
+// Rematches: once a matchmaking game between two people ends, either may
+// offer the other a rematch, as a single game or a best-of-3/5 series. The
+// same offer from the other side accepts it. Series games follow each other
+// after a short break, and a decided series is stored as one record.
+
+const seriesBreak = 5 * time.Second
+
+// rematchMu guards the rematch pairing (rival, rematchOffer) of every GameServer
+var rematchMu sync.Mutex
+
+func rematchMessage(status, from string, bestOf int) *pb.ServerMessage {
+	msg := pb.ToProtoServerMessage("rematch", nil, nil, nil, nil, nil, "", "", 0)
+	msg.Rematch = &pb.Rematch{Status: status, From: from, BestOf: int32(bestOf)}
+	return msg
+}
+
+// setRivals lets a and b offer each other a rematch
+func setRivals(a, b *GameServer) {
+	rematchMu.Lock()
+	a.rival, b.rival = b, a
+	a.rematchOffer, b.rematchOffer = 0, 0
+	rematchMu.Unlock()
+}
+
+// dropRival ends the rematch pairing of gs and tells the other player why
+// ("declined" or "gone")
+func (gs *GameServer) dropRival(status string) {
+	rematchMu.Lock()
+	r := gs.rival
+	paired := r != nil && r.rival == gs
+	if paired {
+		r.rival, r.rematchOffer = nil, 0
+	}
+	gs.rival, gs.rematchOffer = nil, 0
+	rematchMu.Unlock()
+
+	if paired {
+		r.sendMsg(rematchMessage(status, gs.name(), 0))
+	}
+}
+
+// available reports whether gs is still connected and free to start a match
+func (gs *GameServer) available() bool {
+	clientsMu.RLock()
+	connected := clients[gs.connID] == gs
+	clientsMu.RUnlock()
+	return connected && gs.user != nil && gs.match == nil && !gs.searching && gs.room == nil && !gs.isAway()
+}
+
+// handleRematchAction runs a rematch* client action
+func (gs *GameServer) handleRematchAction(msg *pb.ClientMessage) {
+	switch msg.Action {
+	case "rematch":
+		// The series length travels in the mode field, like the opponent count
+		bestOf := 1
+		if msg.Mode != "" {
+			bestOf, _ = strconv.Atoi(msg.Mode)
+		}
+		if !slices.Contains(game.SeriesLengths, bestOf) {
+			gs.sendMsg(rematchMessage("unavailable", "", bestOf))
+			return
+		}
+		gs.offerRematch(bestOf)
+	case "rematch_decline":
+		gs.dropRival("declined")
+	}
+}
+
+// offerRematch offers the last opponent a rematch of bestOf games, or
+// accepts theirs if they offered the same
+func (gs *GameServer) offerRematch(bestOf int) {
+	rematchMu.Lock()
+	r := gs.rival
+	if r == nil || r.rival != gs || !gs.available() || !r.available() {
+		rematchMu.Unlock()
+		gs.sendMsg(rematchMessage("unavailable", "", bestOf))
+		return
+	}
+	if r.rematchOffer != bestOf {
+		gs.rematchOffer = bestOf
+		rematchMu.Unlock()
+		log.Printf("[PVP] 🔁 %s offers %s a rematch (best of %d)\n", gs.user.Username, r.user.Username, bestOf)
+		r.sendMsg(rematchMessage("offer", gs.user.Username, bestOf))
+		gs.sendMsg(rematchMessage("sent", r.user.Username, bestOf))
+		return
+	}
+	gs.rival, r.rival = nil, nil
+	gs.rematchOffer, r.rematchOffer = 0, 0
+	rematchMu.Unlock()
+
+	log.Printf("[PVP] 🔁 Rematch accepted: %s vs %s (best of %d)\n", r.user.Username, gs.user.Username, bestOf)
+	var series *game.Series
+	if bestOf > 1 {
+		series = game.NewSeries(bestOf, r.user.Username, gs.user.Username, time.Now())
+	}
+	startPair(r, gs, series) // Whoever offered first is P1
+}
+
+// startPair plays p1 against p2, as the next game of series unless it is nil
+func startPair(p1, p2 *GameServer, series *game.Series) {
+	// Use Standard size for PVP to ensure mobile compatibility
+	sharedGame := game.NewGame(config.StandardWidth, config.StandardHeight)
+	sharedGame.SetupPVP(p1.user.Username, p2.user.Username)
+	if series != nil {
+		series.Setup(sharedGame)
+	}
+	newMatch(sharedGame, []*GameServer{p1, p2}, nil).start()
+}
+
+// afterGame lets the two players of a finished matchmaking game carry on:
+// with the next game of their series, or else with a rematch offer.
+// m.Mu must be held.
+func (m *Match) afterGame() {
+	if m.Room != nil || m.Bot || len(m.Players) != 2 || m.Players[0] == nil || m.Players[1] == nil {
+		return
+	}
+	p1, p2 := m.Players[0], m.Players[1]
+	s := m.Game.Series
+	if s != nil && !s.Over() {
+		log.Printf("[PVP] 🔁 Series %s %d-%d %s, next game in %v\n", p1.user.Username, s.Wins[0], s.Wins[1], p2.user.Username, seriesBreak)
+		time.AfterFunc(seriesBreak, func() { nextSeriesGame(p1, p2, s) })
+		return
+	}
+	if s != nil {
+		game.RecordSeries(s, time.Now())
+	}
+	setRivals(p1, p2)
+}
+
+// nextSeriesGame starts the next game of a series. A player who left or
+// went on to something else during the break forfeits the series.
+func nextSeriesGame(p1, p2 *GameServer, s *game.Series) {
+	players := []*GameServer{p1, p2}
+	for seat, gs := range players {
+		if gs.available() {
+			continue
+		}
+		log.Printf("[PVP] 🏳️ %s left the series against %s\n", gs.name(), players[1-seat].name())
+		s.Forfeit(seat)
+		game.RecordSeries(s, time.Now())
+		players[1-seat].sendMsg(rematchMessage("gone", gs.name(), s.BestOf))
+		return
+	}
+	startPair(p1, p2, s)
+}
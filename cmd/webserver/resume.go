@@ -0,0 +1,260 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/trytobebee/snake_go/pkg/game"
+	pb "github.com/trytobebee/snake_go/pkg/proto"
+)
+
+// Reconnects: every web connection gets a resume token, replaced on each
+// resume. When the connection drops, its GameServer waits -reconnect-grace
+// for a new connection to /ws?resume=<token>. Meanwhile a solo game is
+// paused and a match seat is played by the AI. The new connection takes
+// over the GameServer and gets the full current state. Once the grace
+// period is over the session ends as if the connection had just closed.
+
+// awayState is kept while a session waits for its player to reconnect
+type awayState struct {
+	timer *time.Timer // Guarded by resumables.mu
+
+	// Seat hand-over, guarded by mu, which is taken before any match lock.
+	// back is set once the player has returned, so a late stepAway or
+	// handOverSeat leaves the seat alone.
+	mu   sync.Mutex
+	back bool
+
+	// Match seat handed to the AI, nil if none, and how it was played before
+	player     *game.Player
+	brain      game.Controller
+	controller string
+	profile    *game.AIProfile
+}
+
+// takeSeat hands p to the AI until the player is back
+func (a *awayState) takeSeat(p *game.Player) {
+	a.player, a.brain, a.controller, a.profile = p, p.Brain, p.Controller, p.Profile
+	p.Brain, p.Controller, p.Profile = &game.HeuristicController{}, "heuristic", game.ProfileNormal
+}
+
+// ResumeRegistry finds sessions by resume token. Its lock also guards the
+// resumeToken and away fields of every GameServer. Only a connection's write
+// lock is taken while holding it; seats change hands once it is released.
+type ResumeRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]*GameServer
+}
+
+var resumables = &ResumeRegistry{sessions: make(map[string]*GameServer)}
+
+// issue gives gs a new resume token, replacing its old one, and sends it.
+// The token is empty if sessions are not kept for a reconnect.
+func (rr *ResumeRegistry) issue(gs *GameServer, resumed bool) {
+	var token string
+	if *reconnectGrace > 0 {
+		b := make([]byte, 16)
+		rand.Read(b)
+		token = hex.EncodeToString(b)
+
+		rr.mu.Lock()
+		delete(rr.sessions, gs.resumeToken)
+		gs.resumeToken = token
+		rr.sessions[token] = gs
+		rr.mu.Unlock()
+	}
+
+	var user *game.User
+	if resumed {
+		user = gs.user // The client may have lost track of who it was
+	}
+	msg := pb.ToProtoServerMessage("session", nil, nil, nil, nil, user, "", "", 0)
+	msg.Session = &pb.Session{Token: token, Grace: int32(reconnectGrace.Seconds()), Resumed: resumed}
+	gs.sendMsg(msg)
+}
+
+// forget makes gs impossible to resume, for a logout or a login elsewhere.
+// A session already waiting for its player ends right away.
+func (rr *ResumeRegistry) forget(gs *GameServer) {
+	rr.mu.Lock()
+	delete(rr.sessions, gs.resumeToken)
+	gs.resumeToken = ""
+	a := gs.away.Load()
+	ended := a != nil && a.timer.Stop()
+	if ended {
+		gs.away.Store(nil)
+	}
+	rr.mu.Unlock()
+
+	if ended {
+		log.Printf("🔌 Session of %s ended while away\n", gs.name())
+		gs.endSession()
+	}
+}
+
+// resume hands the session with this token over to conn and returns it, or
+// nil if there is no such session (anymore). A connection the session still
+// had is closed.
+func (rr *ResumeRegistry) resume(token string, conn *websocket.Conn) *GameServer {
+	if token == "" {
+		return nil
+	}
+	rr.mu.Lock()
+	gs := rr.sessions[token]
+	if gs == nil {
+		rr.mu.Unlock()
+		return nil
+	}
+	a := gs.away.Load()
+	if a != nil {
+		if !a.timer.Stop() {
+			rr.mu.Unlock()
+			return nil // Ran out of time just now
+		}
+		gs.away.Store(nil)
+	}
+
+	gs.writeMu.Lock()
+	old := gs.conn
+	gs.conn = conn
+	gs.writeMu.Unlock()
+	rr.mu.Unlock()
+
+	if old != nil {
+		old.Close() // Its handler sees it lost the session and leaves quietly
+	}
+	if a != nil {
+		gs.stepBack(a)
+	}
+	return gs
+}
+
+// suspend keeps gs for a resume once conn dropped. It reports false if the
+// session has to end now instead.
+func (gs *GameServer) suspend(conn *websocket.Conn) bool {
+	if !gs.owns(conn) {
+		return true // Another connection took over
+	}
+	rr := resumables
+	rr.mu.Lock()
+	if *reconnectGrace <= 0 || gs.resumeToken == "" {
+		rr.mu.Unlock()
+		return false
+	}
+
+	a := &awayState{}
+	a.timer = time.AfterFunc(*reconnectGrace, func() {
+		rr.mu.Lock()
+		if gs.away.Load() != a {
+			rr.mu.Unlock()
+			return
+		}
+		gs.away.Store(nil)
+		delete(rr.sessions, gs.resumeToken)
+		gs.resumeToken = ""
+		rr.mu.Unlock()
+
+		log.Printf("🔌 %s did not come back within %v, ending the session\n", gs.name(), *reconnectGrace)
+		gs.endSession()
+	})
+	gs.away.Store(a)
+	rr.mu.Unlock()
+
+	pvpManager.CancelSearch(gs)
+	gs.unwatch()
+	gs.stepAway(a)
+	log.Printf("📶 %s dropped, keeping the session for %v\n", gs.name(), *reconnectGrace)
+	return true
+}
+
+// stepAway keeps the game of gs going without its player: a match seat is
+// played by the AI and a solo game is paused
+func (gs *GameServer) stepAway(a *awayState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.back {
+		return // Resumed already
+	}
+	if m := gs.match; m != nil {
+		m.Mu.Lock()
+		if gs.match == m && gs.seat < len(m.Game.Players) && !m.Game.GameOver {
+			p := m.Game.Players[gs.seat]
+			a.takeSeat(p)
+			m.Game.SetMessage(fmt.Sprintf("📶 %s 掉线，AI 暂时接管", p.Name))
+		}
+		m.Mu.Unlock()
+		return
+	}
+	if gs.started && !gs.game.GameOver && !gs.game.Paused {
+		gs.game.TogglePause()
+	}
+}
+
+// stepBack gives the returning player their match seat back; a paused solo
+// game waits for them to resume it
+func (gs *GameServer) stepBack(a *awayState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.back = true
+	if a.player == nil {
+		return
+	}
+	if m := gs.match; m != nil {
+		m.Mu.Lock()
+		defer m.Mu.Unlock()
+		m.Game.SetMessage(fmt.Sprintf("📶 %s 回来了", a.player.Name))
+	}
+	a.player.Brain, a.player.Controller, a.player.Profile = a.brain, a.controller, a.profile
+}
+
+// handOverSeat lets the AI play a new match seat of a player who is away,
+// e.g. when their room starts the next game. A seat of an earlier match
+// has nothing left to give back.
+func (gs *GameServer) handOverSeat() {
+	a := gs.away.Load()
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.back && gs.seat < len(gs.game.Players) {
+		a.takeSeat(gs.game.Players[gs.seat])
+	}
+}
+
+// isAway reports whether gs waits for its player to reconnect. It takes no
+// lock, so it is safe under the match and rematch locks.
+func (gs *GameServer) isAway() bool {
+	return gs.away.Load() != nil
+}
+
+// owns reports whether conn is still the connection of gs
+func (gs *GameServer) owns(conn *websocket.Conn) bool {
+	gs.writeMu.Lock()
+	defer gs.writeMu.Unlock()
+	return gs.conn == conn
+}
+
+// showResumed sends a connection that took over a session everything it
+// needs to pick up where the old one left off
+func (gs *GameServer) showResumed() {
+	gameConfig := gs.game.GetGameConfig()
+	gs.sendMsg(pb.ToProtoServerMessage("config", &gameConfig, nil, nil, nil, nil, "", "", 0))
+	if m := gs.match; m != nil {
+		m.Mu.Lock()
+		state := m.Game.GetGameStateSnapshot(true, false, "mid")
+		m.Mu.Unlock()
+		gs.sendMsg(pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0))
+	} else {
+		state := gs.getGameState()
+		gs.sendMsg(pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0))
+	}
+	if room := roomManager.State(gs); room != nil {
+		gs.sendMsg(roomMessage(room, ""))
+	}
+}
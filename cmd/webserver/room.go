@@ -0,0 +1,410 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/trytobebee/snake_go/pkg/game"
+	pb "github.com/trytobebee/snake_go/pkg/proto"
+)
+
+// Rooms: a logged-in player creates a room with a short code and the match
+// settings, friends join by code and the host starts once everyone is ready.
+// Seats nobody took can be played by the AI.
+
+const (
+	roomCodeLength   = 5
+	roomCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No 0/O or 1/I lookalikes
+)
+
+// Room is a lobby of players sharing match settings
+type Room struct {
+	Code     string
+	Host     *GameServer
+	Settings game.RoomSettings
+	Members  []*roomMember // In join order, which is also the seat order
+	match    *Match        // Running match, nil in the lobby
+}
+
+type roomMember struct {
+	gs    *GameServer
+	ready bool
+}
+
+// RoomManager owns every open room. Its lock is never held while taking a
+// match lock, or the other way round.
+type RoomManager struct {
+	mu    sync.Mutex
+	rooms map[string]*Room
+}
+
+var roomManager = &RoomManager{rooms: make(map[string]*Room)}
+
+// roomMessage wraps a room state (nil once the player is out of any room)
+func roomMessage(state *pb.RoomState, errStr string) *pb.ServerMessage {
+	msg := pb.ToProtoServerMessage("room", nil, nil, nil, nil, nil, errStr, "", 0)
+	if state == nil {
+		state = &pb.RoomState{}
+	}
+	msg.Room = state
+	return msg
+}
+
+func (r *Room) state() *pb.RoomState {
+	st := &pb.RoomState{
+		Code:     r.Code,
+		Settings: pb.ToProtoRoomSettings(r.Settings),
+		Playing:  r.match != nil,
+	}
+	for _, m := range r.Members {
+		st.Members = append(st.Members, &pb.RoomMember{
+			Name:  m.gs.user.Username,
+			Ready: m.ready || m.gs == r.Host, // The host is ready by starting
+			Host:  m.gs == r.Host,
+		})
+	}
+	return st
+}
+
+// broadcast returns a function sending the room's state to every member.
+// It is built under the manager's lock and called after releasing it.
+func (r *Room) broadcast() func() {
+	msg := roomMessage(r.state(), "")
+	members := make([]*GameServer, len(r.Members))
+	for i, m := range r.Members {
+		members[i] = m.gs
+	}
+	return func() {
+		for _, gs := range members {
+			gs.sendMsg(msg)
+		}
+	}
+}
+
+func (r *Room) member(name string) *roomMember {
+	for _, m := range r.Members {
+		if m.gs.user.Username == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// remove takes gs out of the room, passing the host role on if needed
+func (r *Room) remove(gs *GameServer) {
+	r.Members = slices.DeleteFunc(r.Members, func(m *roomMember) bool { return m.gs == gs })
+	gs.room = nil
+	if r.Host == gs && len(r.Members) > 0 {
+		r.Host = r.Members[0].gs
+	}
+}
+
+// randomCode returns a short code that is easy to read out, for rooms and
+// live matches
+func randomCode() string {
+	b := make([]byte, roomCodeLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = roomCodeAlphabet[int(b[i])%len(roomCodeAlphabet)]
+	}
+	return string(b)
+}
+
+// newCode picks an unused room code
+func (rm *RoomManager) newCode() string {
+	for {
+		if code := randomCode(); rm.rooms[code] == nil {
+			return code
+		}
+	}
+}
+
+// State returns the state of the room gs is in, nil if none
+func (rm *RoomManager) State(gs *GameServer) *pb.RoomState {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if gs.room == nil {
+		return nil
+	}
+	return gs.room.state()
+}
+
+// Create opens a room hosted by gs
+func (rm *RoomManager) Create(gs *GameServer, s game.RoomSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if gs.match != nil {
+		return fmt.Errorf("finish your match first")
+	}
+	rm.Leave(gs)
+	pvpManager.CancelSearch(gs)
+
+	rm.mu.Lock()
+	r := &Room{Code: rm.newCode(), Host: gs, Settings: s}
+	r.Members = []*roomMember{{gs: gs}}
+	rm.rooms[r.Code] = r
+	gs.room = r
+	send := r.broadcast()
+	rm.mu.Unlock()
+
+	log.Printf("[Room] 🏠 %s created room %s (%s, %d seats)\n", gs.user.Username, r.Code, s.Mode, s.Seats)
+	send()
+	return nil
+}
+
+// Join adds gs to the room with the given code. Joining while the room is
+// playing is fine; the newcomer plays from the next match.
+func (rm *RoomManager) Join(gs *GameServer, code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if gs.match != nil {
+		return fmt.Errorf("finish your match first")
+	}
+	if gs.room != nil && gs.room.Code == code {
+		return fmt.Errorf("already in room %s", code)
+	}
+
+	rm.mu.Lock()
+	r := rm.rooms[code]
+	switch {
+	case r == nil:
+		rm.mu.Unlock()
+		return fmt.Errorf("no room %q", code)
+	case len(r.Members) >= r.Settings.Seats:
+		rm.mu.Unlock()
+		return fmt.Errorf("room %s is full", code)
+	}
+	rm.mu.Unlock()
+
+	rm.Leave(gs)
+	pvpManager.CancelSearch(gs)
+
+	rm.mu.Lock()
+	// The room may have filled up or closed in between
+	if rm.rooms[code] != r || len(r.Members) >= r.Settings.Seats {
+		rm.mu.Unlock()
+		return fmt.Errorf("room %s is no longer open", code)
+	}
+	r.Members = append(r.Members, &roomMember{gs: gs})
+	gs.room = r
+	send := r.broadcast()
+	rm.mu.Unlock()
+
+	log.Printf("[Room] 🚪 %s joined room %s\n", gs.user.Username, code)
+	send()
+	return nil
+}
+
+// Leave takes gs out of its room. In a running match the AI takes its seat.
+func (rm *RoomManager) Leave(gs *GameServer) {
+	rm.mu.Lock()
+	r := gs.room
+	if r == nil {
+		rm.mu.Unlock()
+		return
+	}
+	r.remove(gs)
+	m := r.match
+	if len(r.Members) == 0 {
+		delete(rm.rooms, r.Code)
+		log.Printf("[Room] 🧹 Room %s closed\n", r.Code)
+	}
+	send := r.broadcast()
+	rm.mu.Unlock()
+
+	log.Printf("[Room] 👋 %s left room %s\n", gs.user.Username, r.Code)
+	gs.sendMsg(roomMessage(nil, ""))
+	send()
+
+	if m != nil && gs.match == m && m.dropPlayer(gs) {
+		rm.matchOver(r, m)
+	}
+}
+
+// SetReady toggles whether gs is ready for the next match
+func (rm *RoomManager) SetReady(gs *GameServer) error {
+	rm.mu.Lock()
+	r := gs.room
+	if r == nil {
+		rm.mu.Unlock()
+		return fmt.Errorf("not in a room")
+	}
+	if m := r.member(gs.user.Username); m != nil {
+		m.ready = !m.ready
+	}
+	send := r.broadcast()
+	rm.mu.Unlock()
+	send()
+	return nil
+}
+
+// Kick lets the host remove a member from the lobby
+func (rm *RoomManager) Kick(gs *GameServer, target string) error {
+	rm.mu.Lock()
+	r := gs.room
+	var m *roomMember
+	var err error
+	switch {
+	case r == nil:
+		err = fmt.Errorf("not in a room")
+	case r.Host != gs:
+		err = fmt.Errorf("only the host can kick")
+	case r.match != nil:
+		err = fmt.Errorf("wait for the match to end")
+	default:
+		if m = r.member(target); m == nil || m.gs == gs {
+			err = fmt.Errorf("no member %q to kick", target)
+		}
+	}
+	if err != nil {
+		rm.mu.Unlock()
+		return err
+	}
+	r.remove(m.gs)
+	send := r.broadcast()
+	rm.mu.Unlock()
+
+	log.Printf("[Room] 🥾 %s kicked %s from room %s\n", gs.user.Username, target, r.Code)
+	m.gs.sendMsg(roomMessage(nil, "You were removed from the room"))
+	send()
+	return nil
+}
+
+// UpdateSettings lets the host change the rules between matches
+func (rm *RoomManager) UpdateSettings(gs *GameServer, s game.RoomSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	rm.mu.Lock()
+	r := gs.room
+	var err error
+	switch {
+	case r == nil:
+		err = fmt.Errorf("not in a room")
+	case r.Host != gs:
+		err = fmt.Errorf("only the host can change the settings")
+	case r.match != nil:
+		err = fmt.Errorf("wait for the match to end")
+	case s.Seats < len(r.Members):
+		err = fmt.Errorf("%d players are already in the room", len(r.Members))
+	}
+	if err != nil {
+		rm.mu.Unlock()
+		return err
+	}
+	r.Settings = s
+	send := r.broadcast()
+	rm.mu.Unlock()
+	send()
+	return nil
+}
+
+// Start begins a match with every member once they are all ready
+func (rm *RoomManager) Start(gs *GameServer) error {
+	rm.mu.Lock()
+	r := gs.room
+	var err error
+	switch {
+	case r == nil:
+		err = fmt.Errorf("not in a room")
+	case r.Host != gs:
+		err = fmt.Errorf("only the host can start")
+	case r.match != nil:
+		err = fmt.Errorf("the match is already running")
+	}
+	var names []string
+	for i := 0; err == nil && i < len(r.Members); i++ {
+		m := r.Members[i]
+		switch {
+		case m.gs != r.Host && !m.ready:
+			err = fmt.Errorf("%s is not ready", m.gs.user.Username)
+		case m.gs.match != nil:
+			err = fmt.Errorf("%s is still in a match", m.gs.user.Username)
+		}
+		names = append(names, m.gs.user.Username)
+	}
+
+	var g *game.Game
+	if err == nil {
+		w, h := r.Settings.Dimensions()
+		g = game.NewGame(w, h)
+		err = g.SetupRoom(r.Settings, names)
+	}
+	if err != nil {
+		rm.mu.Unlock()
+		return err
+	}
+
+	players := make([]*GameServer, len(g.Players))
+	for i, m := range r.Members {
+		pvpManager.CancelSearch(m.gs)
+		players[i] = m.gs
+		m.ready = false
+	}
+	r.match = newMatch(g, players, r)
+	m := r.match
+	send := r.broadcast()
+	rm.mu.Unlock()
+
+	log.Printf("[Room] ⚔️ Room %s starts a %s match: %s + %d AI\n", r.Code, r.Settings.Mode, strings.Join(names, ", "), len(g.Players)-len(names))
+	send()
+	m.start()
+	return nil
+}
+
+// matchOver sends the room back to its lobby once its match has ended
+func (rm *RoomManager) matchOver(r *Room, m *Match) {
+	rm.mu.Lock()
+	if r.match != m {
+		rm.mu.Unlock()
+		return
+	}
+	r.match = nil
+	for _, mem := range r.Members {
+		mem.ready = false
+	}
+	send := r.broadcast()
+	rm.mu.Unlock()
+	send()
+}
+
+// handleRoomAction runs a room_* client action and reports failures to the client
+func (gs *GameServer) handleRoomAction(msg *pb.ClientMessage) {
+	if gs.user == nil {
+		gs.sendMsg(roomMessage(nil, "Please login to play in rooms"))
+		return
+	}
+
+	var err error
+	switch msg.Action {
+	case "room_create":
+		gs.dropRival("gone")
+		err = roomManager.Create(gs, pb.FromProtoRoomSettings(msg.RoomSettings))
+	case "room_settings":
+		err = roomManager.UpdateSettings(gs, pb.FromProtoRoomSettings(msg.RoomSettings))
+	case "room_join":
+		gs.dropRival("gone")
+		err = roomManager.Join(gs, msg.Code)
+	case "room_leave":
+		roomManager.Leave(gs)
+		// Leaving mid-match or after it: back to a solo game of our own
+		if gs.game.IsPVP && gs.match == nil {
+			gs.resetGame()
+		}
+	case "room_ready":
+		err = roomManager.SetReady(gs)
+	case "room_kick":
+		err = roomManager.Kick(gs, msg.Target)
+	case "room_start":
+		err = roomManager.Start(gs)
+	default:
+		err = fmt.Errorf("unknown room action %q", msg.Action)
+	}
+	if err != nil {
+		gs.sendMsg(roomMessage(roomManager.State(gs), err.Error()))
+	}
+}
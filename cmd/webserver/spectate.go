@@ -0,0 +1,349 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+	pb "github.com/trytobebee/snake_go/pkg/proto"
+)
+
+// Spectating: any connection can list the running matches and watch one.
+// Spectators get the players' broadcast through a queue of their own, so a
+// slow spectator drops frames instead of stalling the match loop. With
+// -spectate-delay the frames are held back, so a spectator cannot relay
+// what they see to a player in time (ghosting).
+
+// spectatorBuffer is how many frames a spectator may fall behind, on top of
+// the ones held back by the delay, before frames are dropped
+const spectatorBuffer = 32
+
+// MatchRegistry lists the running matches for spectators. Its lock is never
+// held while taking a match lock.
+type MatchRegistry struct {
+	mu      sync.Mutex
+	matches map[string]*Match
+}
+
+var liveMatches = &MatchRegistry{matches: make(map[string]*Match)}
+
+// add lists m under its room code, or a fresh code for matchmaking
+func (mr *MatchRegistry) add(m *Match) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	id := ""
+	if m.Room != nil {
+		id = m.Room.Code
+	}
+	for id == "" || mr.matches[id] != nil {
+		id = randomCode()
+	}
+	m.ID = id
+	mr.matches[id] = m
+}
+
+func (mr *MatchRegistry) remove(m *Match) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	if mr.matches[m.ID] == m {
+		delete(mr.matches, m.ID)
+	}
+}
+
+func (mr *MatchRegistry) get(id string) *Match {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.matches[id]
+}
+
+// list describes every running match, the most watched first
+func (mr *MatchRegistry) list() []*pb.LiveMatch {
+	mr.mu.Lock()
+	matches := make([]*Match, 0, len(mr.matches))
+	for _, m := range mr.matches {
+		matches = append(matches, m)
+	}
+	mr.mu.Unlock()
+
+	out := []*pb.LiveMatch{}
+	for _, m := range matches {
+		m.Mu.Lock()
+		if !m.Closing {
+			out = append(out, m.info())
+		}
+		m.Mu.Unlock()
+	}
+	slices.SortFunc(out, func(a, b *pb.LiveMatch) int {
+		return cmp.Or(cmp.Compare(b.Spectators, a.Spectators), strings.Compare(a.Id, b.Id))
+	})
+	return out
+}
+
+// info describes the match for spectators. m.Mu must be held.
+func (m *Match) info() *pb.LiveMatch {
+	info := &pb.LiveMatch{
+		Id:         m.ID,
+		Mode:       m.Game.Mode,
+		Seats:      int32(len(m.Game.Players)),
+		Spectators: int32(m.spectators.count()),
+		TimeLeft:   int32(m.Game.GetTimeRemaining()),
+	}
+	if m.Room != nil {
+		info.Room = m.Room.Code
+	}
+	for _, gs := range m.humans() {
+		info.Players = append(info.Players, gs.user.Username)
+	}
+	return info
+}
+
+// end takes a finished match off the list, releases its controllers and lets
+// its spectators know once they have seen the last frame. m.Mu must be held.
+func (m *Match) end() {
+	m.Game.ReleaseControllers()
+	liveMatches.remove(m)
+	m.spectators.close()
+}
+
+// spectator streams a match to one connection
+type spectator struct {
+	gs     *GameServer
+	frames chan spectatorFrame
+	wake   chan struct{} // Closed on stop, to cut a delay short
+
+	mu      sync.Mutex // Held while sending, so nothing goes out after stop
+	stopped bool
+}
+
+type spectatorFrame struct {
+	at  time.Time
+	msg *pb.ServerMessage
+}
+
+// run sends the frames as they come due, then reports the end of the match
+func (s *spectator) run(delay time.Duration) {
+	for {
+		var f spectatorFrame
+		var ok bool
+		select {
+		case <-s.wake:
+			return
+		case f, ok = <-s.frames:
+		}
+		if !ok {
+			msg := spectateMessage(nil, "")
+			msg.Success = "Match over"
+			s.send(msg)
+			return
+		}
+		if wait := time.Until(f.at.Add(delay)); wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-s.wake:
+				t.Stop()
+				return
+			case <-t.C:
+			}
+		}
+		if !s.send(f.msg) {
+			return
+		}
+	}
+}
+
+func (s *spectator) send(msg *pb.ServerMessage) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.gs.sendMsg(msg)
+	return true
+}
+
+// stop ends the stream; once it returns nothing more reaches the connection
+func (s *spectator) stop() {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	close(s.wake)
+}
+
+// spectatorHub fans a match's broadcasts out to its spectators
+type spectatorHub struct {
+	mu       sync.Mutex
+	watchers map[*GameServer]*spectator
+	closed   bool
+}
+
+func (h *spectatorHub) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.watchers)
+}
+
+// add starts streaming the match to gs. It fails once the match is over.
+func (h *spectatorHub) add(gs *GameServer, delay time.Duration) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	if h.watchers == nil {
+		h.watchers = make(map[*GameServer]*spectator)
+	}
+	s := &spectator{
+		gs:     gs,
+		frames: make(chan spectatorFrame, int(delay/config.BaseTick)+spectatorBuffer),
+		wake:   make(chan struct{}),
+	}
+	h.watchers[gs] = s
+	go s.run(delay)
+	return true
+}
+
+func (h *spectatorHub) remove(gs *GameServer) {
+	h.mu.Lock()
+	s := h.watchers[gs]
+	delete(h.watchers, gs)
+	h.mu.Unlock()
+	if s != nil {
+		s.stop()
+	}
+}
+
+// publish queues msg for every spectator without ever blocking. The message
+// is shared, so it must not be changed afterwards.
+func (h *spectatorHub) publish(msg *pb.ServerMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	f := spectatorFrame{at: time.Now(), msg: msg}
+	for _, s := range h.watchers {
+		select {
+		case s.frames <- f:
+		default:
+			// Too slow: drop the frame rather than hold up the match
+		}
+	}
+}
+
+// close ends every stream after the frames already queued
+func (h *spectatorHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for _, s := range h.watchers {
+		close(s.frames)
+	}
+}
+
+// spectateMessage reports the watched match, nil once watching stopped or,
+// with Success set, once the match ended
+func spectateMessage(watching *pb.LiveMatch, errStr string) *pb.ServerMessage {
+	msg := pb.ToProtoServerMessage("spectate", nil, nil, nil, nil, nil, errStr, "", 0)
+	msg.Watching = watching
+	return msg
+}
+
+// watch starts spectating the match with the given id. A solo game in
+// progress is paused until the spectator comes back.
+func (gs *GameServer) watch(id string) error {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	switch {
+	case gs.match != nil:
+		return fmt.Errorf("finish your match first")
+	case gs.searching:
+		return fmt.Errorf("stop searching for a match first")
+	}
+	m := liveMatches.get(id)
+	if m == nil {
+		return fmt.Errorf("no live match %q", id)
+	}
+	if m == gs.watching {
+		return nil
+	}
+	gs.unwatch()
+	if gs.started && !gs.game.GameOver && !gs.game.Paused {
+		gs.game.TogglePause()
+	}
+
+	// Frames are published under the match lock, so none can reach the
+	// spectator before the match's board size
+	m.Mu.Lock()
+	if m.Closing || !m.spectators.add(gs, *spectateDelay) {
+		m.Mu.Unlock()
+		return fmt.Errorf("match %s is over", id)
+	}
+	gs.watching = m
+	gameConfig := m.Game.GetGameConfig()
+	gs.sendMsg(pb.ToProtoServerMessage("config", &gameConfig, nil, nil, nil, nil, "", "", 0))
+	gs.sendMsg(spectateMessage(m.info(), ""))
+	m.Mu.Unlock()
+
+	log.Printf("[Spectate] 👀 %s is watching match %s\n", gs.name(), id)
+	return nil
+}
+
+// unwatch stops spectating and tells the client, reporting whether gs was
+// watching
+func (gs *GameServer) unwatch() bool {
+	m := gs.watching
+	if m == nil {
+		return false
+	}
+	gs.watching = nil
+	m.spectators.remove(gs)
+	gs.sendMsg(spectateMessage(nil, ""))
+	log.Printf("[Spectate] 🙈 %s stopped watching match %s\n", gs.name(), m.ID)
+	return true
+}
+
+// name identifies the connection in logs, logged in or not
+func (gs *GameServer) name() string {
+	if gs.user != nil {
+		return gs.user.Username
+	}
+	return "guest " + gs.connID[:8]
+}
+
+// showOwnGame sends the connection's own game again after watching
+func (gs *GameServer) showOwnGame() {
+	gameConfig := gs.game.GetGameConfig()
+	gs.sendMsg(pb.ToProtoServerMessage("config", &gameConfig, nil, nil, nil, nil, "", "", 0))
+	state := gs.getGameState()
+	gs.sendMsg(pb.ToProtoServerMessage("state", nil, &state, nil, nil, nil, "", "", 0))
+}
+
+// handleSpectateAction runs a spectate* client action
+func (gs *GameServer) handleSpectateAction(msg *pb.ClientMessage) {
+	switch msg.Action {
+	case "spectate_list":
+		reply := pb.ToProtoServerMessage("matches", nil, nil, nil, nil, nil, "", "", 0)
+		reply.Matches = liveMatches.list()
+		gs.sendMsg(reply)
+	case "spectate":
+		wasWatching := gs.watching != nil
+		if err := gs.watch(msg.Code); err != nil {
+			gs.sendMsg(spectateMessage(nil, err.Error()))
+			if wasWatching && gs.watching == nil {
+				gs.showOwnGame() // Stopped watching the last match on the way
+			}
+		}
+	case "spectate_stop":
+		// Back to our own game, as we left it
+		if gs.unwatch() {
+			gs.showOwnGame()
+		}
+	}
+}
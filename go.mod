@@ -4,7 +4,10 @@ go 1.25.5
 
 require github.com/eiannone/keyboard v0.0.0-20220611211555-0d226195f203
 
-require github.com/yalue/onnxruntime_go v1.25.0
+require (
+	github.com/tetratelabs/wazero v1.11.0
+	github.com/yalue/onnxruntime_go v1.25.0
+)
 
 require (
 	github.com/dustin/go-humanize v1.0.1 // indirect
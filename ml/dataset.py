@@ -13,6 +13,12 @@ DIR_MAP = {
     (1, 0): 3
 }
 
+# Observation spec v1 (pkg/game/observation.go): the planes _state_to_tensor builds
+OBSERVATION_SPEC = {
+    "version": 1,
+    "channels": ["head", "body", "enemy_head", "enemy_body", "food", "hazard"],
+}
+
 class SnakeOfflineRLDataset(Dataset):
     def __init__(self, records_dir, grid_width=25, grid_height=25):
         self.files = glob.glob(os.path.join(records_dir, "*.jsonl"))
@@ -56,7 +62,7 @@ class SnakeOfflineRLDataset(Dataset):
                     })
 
     def _state_to_tensor(self, state):
-        grid = np.zeros((6, self.grid_height, self.grid_width), dtype=np.float32)
+        grid = np.zeros((len(OBSERVATION_SPEC["channels"]), self.grid_height, self.grid_width), dtype=np.float32)
         
         def set_p(c, p_list):
             for p in p_list:
@@ -96,3 +102,32 @@ class SnakeOfflineRLDataset(Dataset):
             's_next': s_next,
             'done': torch.tensor(1.0 if t['done'] else 0.0, dtype=torch.float32)
         }
+
+
+class SnakeShardDataset(Dataset):
+    """Transitions exported by `go run ./cmd/dataset`, built with the Go engine's
+    own observation builder. Yields the same dicts as SnakeOfflineRLDataset."""
+
+    def __init__(self, dataset_dir, split="train"):
+        with open(os.path.join(dataset_dir, "manifest.json")) as f:
+            manifest = json.load(f)
+        self.spec = manifest["spec"]
+        arrays = {"obs": [], "action": [], "reward": [], "next_obs": [], "done": []}
+        for shard in manifest["shards"].get(split) or []:
+            with np.load(os.path.join(dataset_dir, shard)) as data:
+                for key in arrays:
+                    arrays[key].append(data[key])
+        self.data = {key: np.concatenate(parts) for key, parts in arrays.items() if parts}
+        print(f"✅ Loaded {len(self)} {split} transitions from {dataset_dir} (observation v{self.spec['version']}).")
+
+    def __len__(self):
+        return len(self.data["action"]) if self.data else 0
+
+    def __getitem__(self, idx):
+        return {
+            's': torch.from_numpy(self.data["obs"][idx]),
+            'a': torch.tensor(self.data["action"][idx], dtype=torch.long),
+            'r': torch.tensor(self.data["reward"][idx], dtype=torch.float32),
+            's_next': torch.from_numpy(self.data["next_obs"][idx]),
+            'done': torch.tensor(1.0 if self.data["done"][idx] else 0.0, dtype=torch.float32)
+        }
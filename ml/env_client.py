@@ -0,0 +1,80 @@
+"""
+Client for the Go RL environment server (cmd/envserver).
+
+Generate the protobuf bindings once before use:
+    protoc -I ../pkg/proto --python_out=. ../pkg/proto/snake.proto
+
+Example:
+    env = SnakeVecEnv(num_envs=8, seed=0, opponent="heuristic")
+    obs, scalars = env.reset()
+    obs, scalars, rewards, dones, infos = env.step([3] * 8)
+"""
+import numpy as np
+from websocket import create_connection
+
+import snake_pb2
+
+GRID_SIZE = 25
+
+
+class SnakeVecEnv:
+    def __init__(self, num_envs=1, seed=0, url="ws://localhost:8090/ws/env",
+                 mode="battle", opponent="heuristic", opponents=1,
+                 width=25, height=25, speed="mid", auto_reset=True, observation=1,
+                 reward="default", curriculum=""):
+        self.num_envs = num_envs
+        self.seed = seed
+        self.config = snake_pb2.EnvConfig(
+            width=width, height=height, mode=mode, opponent=opponent,
+            opponents=opponents, speed=speed, autoReset=auto_reset,
+            observation=observation, reward=reward, curriculum=curriculum,
+        )
+        self.scalar_names = []
+        self.channel_names = []  # Grid planes of the observation spec, set by reset()
+        self.ws = create_connection(url)
+
+    def _call(self, req):
+        self.ws.send_binary(req.SerializeToString())
+        resp = snake_pb2.EnvResponse()
+        resp.ParseFromString(self.ws.recv())
+        if resp.error:
+            raise RuntimeError(f"env server: {resp.error}")
+        return resp
+
+    def _unpack(self, steps):
+        shape = (len(self.channel_names), GRID_SIZE, GRID_SIZE)
+        grids = np.array([s.grid for s in steps], dtype=np.float32).reshape(-1, *shape)
+        scalars = np.array([s.scalars for s in steps], dtype=np.float32)
+        return grids, scalars
+
+    def reset(self, seed=None):
+        if seed is not None:
+            self.seed = seed
+        seeds = [self.seed + i for i in range(self.num_envs)]
+        resp = self._call(snake_pb2.EnvRequest(type="reset", seeds=seeds, config=self.config))
+        self.scalar_names = list(resp.scalarNames)
+        self.channel_names = list(resp.channelNames)
+        return self._unpack(resp.steps)
+
+    def step(self, directions, boost=None, fire=None):
+        """directions: 0 up, 1 down, 2 left, 3 right, -1 keep heading"""
+        boost = boost if boost is not None else [False] * self.num_envs
+        fire = fire if fire is not None else [False] * self.num_envs
+        actions = [
+            snake_pb2.EnvAction(direction=int(d), boost=bool(b), fire=bool(f))
+            for d, b, f in zip(directions, boost, fire)
+        ]
+        resp = self._call(snake_pb2.EnvRequest(type="step", actions=actions))
+        grids, scalars = self._unpack(resp.steps)
+        rewards = np.array([s.reward for s in resp.steps], dtype=np.float32)
+        dones = np.array([s.done for s in resp.steps], dtype=bool)
+        infos = [
+            {"score": s.info.score, "opponent_score": s.info.opponentScore,
+             "winner": s.info.winner, "steps": s.info.steps, "ticks": s.info.ticks,
+             "scenario": s.info.scenario, "difficulty": s.info.difficulty}
+            for s in resp.steps
+        ]
+        return grids, scalars, rewards, dones, infos
+
+    def close(self):
+        self.ws.close()
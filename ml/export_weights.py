@@ -0,0 +1,60 @@
+"""
+Export SnakePolicyNet weights for the pure-Go inference backend (pkg/game/cnn.go).
+
+BatchNorm layers are folded into the preceding convolutions, so the Go side
+only needs conv + ReLU and two linear layers.
+
+Usage:
+    python export_weights.py checkpoints/snake_rl_v1.pth checkpoints/snake_policy.bin
+"""
+import struct
+import sys
+
+import torch
+
+from model import SnakePolicyNet
+
+MAGIC = b"SNKW"
+VERSION = 1
+
+
+def fold_bn(conv, bn):
+    scale = bn.weight / torch.sqrt(bn.running_var + bn.eps)
+    weight = conv.weight * scale.reshape(-1, 1, 1, 1)
+    bias = (conv.bias - bn.running_mean) * scale + bn.bias
+    return weight, bias
+
+
+def export_weights(model, path):
+    model.eval()
+    tensors = {}
+    with torch.no_grad():
+        for i, (conv, bn) in enumerate([(model.conv1, model.bn1), (model.conv2, model.bn2), (model.conv3, model.bn3)], 1):
+            w, b = fold_bn(conv, bn)
+            tensors[f"conv{i}.weight"] = w
+            tensors[f"conv{i}.bias"] = b
+        for name in ("fc1", "fc2"):
+            layer = getattr(model, name)
+            tensors[f"{name}.weight"] = layer.weight
+            tensors[f"{name}.bias"] = layer.bias
+
+        with open(path, "wb") as f:
+            f.write(MAGIC)
+            f.write(struct.pack("<II", VERSION, len(tensors)))
+            for name, t in tensors.items():
+                data = t.detach().cpu().float().contiguous()
+                encoded = name.encode()
+                f.write(struct.pack("<H", len(encoded)))
+                f.write(encoded)
+                f.write(struct.pack("<B", data.dim()))
+                f.write(struct.pack(f"<{data.dim()}I", *data.shape))
+                f.write(data.numpy().astype("<f4").tobytes())
+
+
+if __name__ == "__main__":
+    src = sys.argv[1] if len(sys.argv) > 1 else "checkpoints/snake_rl_v1.pth"
+    dst = sys.argv[2] if len(sys.argv) > 2 else "checkpoints/snake_policy.bin"
+    net = SnakePolicyNet()
+    net.load_state_dict(torch.load(src, map_location="cpu"))
+    export_weights(net, dst)
+    print(f"✨ Pure-Go weights written to {dst}")
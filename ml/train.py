@@ -2,8 +2,10 @@ import torch
 import torch.nn as nn
 import torch.optim as optim
 from torch.utils.data import DataLoader
-from dataset import SnakeOfflineRLDataset
+from dataset import SnakeOfflineRLDataset, SnakeShardDataset, OBSERVATION_SPEC
 from model import SnakePolicyNet
+from export_weights import export_weights
+import json
 import os
 import copy
 import logging
@@ -33,6 +35,7 @@ except ImportError:
 def train_rl():
     # 1. Config
     RECORDS_DIR = "../records"
+    DATASET_DIR = "../data/dataset"  # Written by `go run ./cmd/dataset`, preferred when present
     BATCH_SIZE = 64
     LR = 0.0005
     GAMMA = 0.95      # Discount factor for future rewards
@@ -43,7 +46,12 @@ def train_rl():
     print(f"🚀 Starting Offline RL Training on {DEVICE}...")
 
     # 2. Data
-    dataset = SnakeOfflineRLDataset(RECORDS_DIR)
+    if os.path.exists(os.path.join(DATASET_DIR, "manifest.json")):
+        dataset = SnakeShardDataset(DATASET_DIR, split="train")
+        spec = dataset.spec
+    else:
+        dataset = SnakeOfflineRLDataset(RECORDS_DIR)
+        spec = OBSERVATION_SPEC
     if len(dataset) == 0:
         print("❌ No data found.")
         return
@@ -51,7 +59,7 @@ def train_rl():
 
     # 3. Networks
     # Q-Network: the one we train
-    q_net = SnakePolicyNet().to(DEVICE)
+    q_net = SnakePolicyNet(input_channels=len(spec["channels"])).to(DEVICE)
     # Target-Network: stable reference for Bellman targets
     target_net = copy.deepcopy(q_net).to(DEVICE)
     target_net.eval()
@@ -108,11 +116,19 @@ def train_rl():
     
     # Export ONNX
     q_net.eval()
-    dummy_input = torch.randn(1, 6, 25, 25).to(DEVICE)
+    dummy_input = torch.randn(1, len(spec["channels"]), 25, 25).to(DEVICE)
     torch.onnx.export(q_net, dummy_input, "checkpoints/snake_policy.onnx",
                       opset_version=18,  # Stable opset for modern PyTorch
                       input_names=['input'], 
-                      output_names=['output'])
+                      output_names=['output'],
+                      dynamic_axes={'input': {0: 'batch'}, 'output': {0: 'batch'}})  # Batched inference in Go
+
+    # Export weights for the pure-Go backend (machines without ONNX Runtime)
+    export_weights(q_net, "checkpoints/snake_policy.bin")
+
+    # Declare the observation the model was trained on, so Go builds the same tensor
+    with open("checkpoints/snake_policy.spec.json", "w") as f:
+        json.dump(spec, f, indent=2)
         
     print("✨ RL Model Trained and Exported to ONNX!")
 
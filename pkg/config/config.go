@@ -9,6 +9,10 @@ const (
 	LargeWidth     = 38
 	LargeHeight    = 38
 	GameDuration   = 60 * time.Second
+
+	// Board sizes accepted from the command line or the network
+	MinBoardSize = 10
+	MaxBoardSize = 100
 )
 
 // Food spawn settings
@@ -57,6 +61,23 @@ const (
 	KeyRepeatWindow      = 200 * time.Millisecond // Time window for consecutive key detection
 )
 
+// Neural inference worker defaults
+const (
+	InferenceWorkers   = 1                    // Goroutines draining the prediction queue
+	InferenceBatchSize = 32                   // Most requests run in one forward pass
+	InferenceBatchWait = 2 * time.Millisecond // How long a worker waits to fill a batch
+	ModelPollInterval  = 10 * time.Second     // How often the model directory is checked for changes
+)
+
+// Bot API settings
+const (
+	BotMoveTimeout    = 100 * time.Millisecond // Reply time offered to a remote bot before its move interval is known; also bounds writes to it
+	WasmMoveTimeout   = 20 * time.Millisecond  // CPU time a WASM bot gets per move
+	WasmInitTimeout   = 500 * time.Millisecond // Time a WASM bot instance gets to initialise
+	WasmMemoryLimit   = 256                    // Memory cap of a WASM bot in 64 KiB pages (16 MiB)
+	WasmMaxModuleSize = 8 << 20                // Largest accepted .wasm upload in bytes
+)
+
 // Emoji characters for rendering
 const (
 	CharEmpty = "  " // Two spaces to match emoji width
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"math"
+	"time"
+)
+
+// Dynamic difficulty tuning
+const (
+	AdaptInterval      = 5 * time.Second // How often the AI is re-tuned during a match
+	AdaptStep          = 0.08            // Largest level change per re-tune
+	DefaultSkillLevel  = 0.5             // Starting level for players without history
+	adaptTargetSpeed   = 0.3             // Foods/second considered "on par"
+	adaptScoreGapScale = 100.0           // Score gap that counts as a clear lead
+)
+
+// AdaptiveDifficulty tunes the solo AI to the player's recent performance.
+// Level runs from 0 (beginner opponent) to 1 (full strength).
+type AdaptiveDifficulty struct {
+	Level    float64
+	lastEval time.Time
+	lastAI   int
+}
+
+// NewAdaptiveDifficulty starts at a persisted skill estimate
+func NewAdaptiveDifficulty(start float64) *AdaptiveDifficulty {
+	return &AdaptiveDifficulty{Level: clamp01(start)}
+}
+
+// DisplayLevel maps Level onto 1..10 for the HUD and session logs
+func (a *AdaptiveDifficulty) DisplayLevel() int {
+	return int(math.Round(a.Level*9)) + 1
+}
+
+// Profile builds a skill profile by interpolating between the beginner and
+// hard presets. Move speed, boost willingness and fire rate follow the level.
+func (a *AdaptiveDifficulty) Profile() *AIProfile {
+	lo, hi, t := ProfileBeginner, ProfileHard, a.Level
+	lerp := func(x, y float64) float64 { return x + (y-x)*t }
+
+	tier := "mid"
+	switch {
+	case t < 0.25:
+		tier = "low"
+	case t > 0.85:
+		tier = "high"
+	}
+
+	return &AIProfile{
+		Name:             "adaptive",
+		SpeedTier:        tier,
+		DecisionNoise:    lerp(lo.DecisionNoise, hi.DecisionNoise),
+		ReactionDelay:    int(math.Round(lerp(float64(lo.ReactionDelay), float64(hi.ReactionDelay)))),
+		LookaheadLimit:   int(lerp(float64(lo.LookaheadLimit), float64(hi.LookaheadLimit))),
+		FireRange:        int(math.Round(lerp(float64(lo.FireRange), float64(hi.FireRange)))),
+		FireAccuracy:     lerp(lo.FireAccuracy, hi.FireAccuracy),
+		PropAwareness:    lerp(lo.PropAwareness, hi.PropAwareness),
+		BoostWillingness: lerp(lo.BoostWillingness, hi.BoostWillingness),
+	}
+}
+
+// SessionEstimate returns the skill estimate to persist after a game: the
+// level reached, nudged by the outcome
+func (a *AdaptiveDifficulty) SessionEstimate(won bool) float64 {
+	if won {
+		return clamp01(a.Level + AdaptStep/2)
+	}
+	return clamp01(a.Level - AdaptStep/2)
+}
+
+// EnableAdaptiveDifficulty turns on in-match tuning starting at the given level
+func (g *Game) EnableAdaptiveDifficulty(start float64) {
+	g.Adaptive = NewAdaptiveDifficulty(start)
+	g.Adaptive.lastEval = g.Now()
+	g.applyAdaptiveProfile()
+}
+
+// UpdateAdaptiveDifficulty re-tunes the AI every AdaptInterval. The signal
+// mixes the score gap to the best AI, how fast the player is eating, and
+// whether the AI has been crashing.
+func (g *Game) UpdateAdaptiveDifficulty() {
+	a := g.Adaptive
+	if a == nil || g.IsPVP || g.GameOver || g.Paused || len(g.Players) < 2 {
+		return
+	}
+	now := g.Now()
+	if a.lastEval.IsZero() {
+		a.lastEval = now
+	}
+	if now.Sub(a.lastEval) < AdaptInterval {
+		return
+	}
+	a.lastEval = now
+
+	best := 0
+	aiDeaths := 0
+	for _, p := range g.Players[1:] {
+		if p.Score > best {
+			best = p.Score
+		}
+		aiDeaths += p.Deaths
+	}
+	gap := float64(g.Players[0].Score - best)
+	signal := 0.6*clampSigned(gap/adaptScoreGapScale) +
+		0.4*clampSigned((g.GetEatingSpeed()-adaptTargetSpeed)/adaptTargetSpeed)
+	// An AI that keeps crashing is too sloppy to be a real opponent
+	if aiDeaths > a.lastAI {
+		signal += 0.3
+	}
+	a.lastAI = aiDeaths
+
+	a.Level = clamp01(a.Level + AdaptStep*clampSigned(signal))
+	g.applyAdaptiveProfile()
+}
+
+// AdaptiveLevel returns the HUD level (1..10), or 0 when adaptation is off
+func (g *Game) AdaptiveLevel() int {
+	if g.Adaptive == nil {
+		return 0
+	}
+	return g.Adaptive.DisplayLevel()
+}
+
+func (g *Game) applyAdaptiveProfile() {
+	prof := g.Adaptive.Profile()
+	for i, p := range g.Players {
+		if i == 0 || p.Controller == "manual" {
+			continue
+		}
+		p.Profile = prof
+	}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func clampSigned(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// TestAdaptiveDifficultyFollowsScoreGap checks the AI gets stronger when the player leads and weaker when behind
+func TestAdaptiveDifficultyFollowsScoreGap(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.Clock = NewSimClock(g.Now())
+	g.EnableAdaptiveDifficulty(DefaultSkillLevel)
+	start := g.Adaptive.Level
+
+	g.Players[0].Score = 200
+	g.Clock.Advance(AdaptInterval)
+	g.UpdateAdaptiveDifficulty()
+	if g.Adaptive.Level <= start {
+		t.Errorf("expected level to rise while the player leads, got %.2f -> %.2f", start, g.Adaptive.Level)
+	}
+	if g.Players[1].Profile == nil || g.Players[1].Profile.Name != "adaptive" {
+		t.Errorf("expected the AI to carry the adaptive profile")
+	}
+
+	raised := g.Adaptive.Level
+	g.Players[0].Score = 0
+	g.Players[1].Score = 300
+	g.Clock.Advance(AdaptInterval)
+	g.UpdateAdaptiveDifficulty()
+	if g.Adaptive.Level >= raised {
+		t.Errorf("expected level to drop while the AI leads, got %.2f -> %.2f", raised, g.Adaptive.Level)
+	}
+
+	if got := g.GetGameStateSnapshot(true, false, "adaptive").AdaptiveLevel; got < 1 || got > 10 {
+		t.Errorf("expected a 1-10 level in the snapshot, got %d", got)
+	}
+}
+
+// TestAdaptiveProfileEndpoints checks the interpolated profile matches the presets at both ends
+func TestAdaptiveProfileEndpoints(t *testing.T) {
+	low := NewAdaptiveDifficulty(0).Profile()
+	if low.FireRange != ProfileBeginner.FireRange || low.SpeedTier != "low" || low.BoostWillingness != ProfileBeginner.BoostWillingness {
+		t.Errorf("level 0 should match the beginner preset, got %+v", low)
+	}
+	high := NewAdaptiveDifficulty(1).Profile()
+	if high.LookaheadLimit != ProfileHard.LookaheadLimit || high.DecisionNoise != 0 || high.SpeedTier != "high" {
+		t.Errorf("level 1 should match the hard preset, got %+v", high)
+	}
+
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.SetAIDifficulty("adaptive")
+	if g.AdaptiveLevel() == 0 {
+		t.Errorf("adaptive difficulty should enable adaptation")
+	}
+	g.SetAIDifficulty("mid")
+	if g.AdaptiveLevel() != 0 {
+		t.Errorf("a fixed difficulty should turn adaptation off")
+	}
+}
@@ -1,96 +1,15 @@
 package game
 
-import (
-	"math/rand"
-)
+import "slices"
 
 // UpdateAI decides the next move for the player snake when in AutoPlay mode
 // --- Obsolete functions removed (logic moved to Controller) ---
 
-// GetFeatureGrid generates the 6-channel input for the Neural Network
+// GetFeatureGrid generates the 6-channel ObservationV1 input for the Neural Network
 // Channels: 0:PlayerHead, 1:PlayerBody, 2:EnemyHead, 3:EnemyBody, 4:Food, 5:Hazard
-// FIXED: Now centers a 25x25 window on the player's head to support any board size.
+// The 25x25 window is centred on the player's head to support any board size.
 func (g *Game) GetFeatureGrid(playerIdx int) []float64 {
-	const AISize = 25
-	size := AISize * AISize
-	grid := make([]float64, 6*size)
-
-	if playerIdx >= len(g.Players) {
-		return grid
-	}
-
-	me := g.Players[playerIdx]
-	if len(me.Snake) == 0 {
-		return grid
-	}
-
-	head := me.Snake[0]
-	// Center the 25x25 window on the head
-	offsetX := head.X - AISize/2
-	offsetY := head.Y - AISize/2
-
-	set := func(c, x, y int) {
-		relX := x - offsetX
-		relY := y - offsetY
-		if relX >= 0 && relX < AISize && relY >= 0 && relY < AISize {
-			grid[c*size+relY*AISize+relX] = 1.0
-		}
-	}
-
-	// Ch 0: Player Head
-	set(0, head.X, head.Y)
-
-	// Ch 1: Player Body
-	if len(me.Snake) > 1 {
-		for _, p := range me.Snake[1:] {
-			set(1, p.X, p.Y)
-		}
-	}
-
-	// Iterate over other players as enemies
-	for i, other := range g.Players {
-		if i == playerIdx {
-			continue
-		}
-		// Ch 2: Enemy Head
-		if len(other.Snake) > 0 {
-			set(2, other.Snake[0].X, other.Snake[0].Y)
-		}
-		// Ch 3: Enemy Body
-		if len(other.Snake) > 1 {
-			for _, p := range other.Snake[1:] {
-				set(3, p.X, p.Y)
-			}
-		}
-	}
-
-	// Ch 4: Food
-	for _, f := range g.Foods {
-		set(4, f.Pos.X, f.Pos.Y)
-	}
-
-	// Ch 5: Hazard (Obstacles + Fireballs + Wall)
-	// Add walls relative to the centered view
-	for vy := 0; vy < AISize; vy++ {
-		for vx := 0; vx < AISize; vx++ {
-			worldX := vx + offsetX
-			worldY := vy + offsetY
-			if worldX <= 0 || worldX >= g.Width-1 || worldY <= 0 || worldY >= g.Height-1 {
-				grid[5*size+vy*AISize+vx] = 1.0
-			}
-		}
-	}
-
-	for _, obs := range g.Obstacles {
-		for _, p := range obs.Points {
-			set(5, p.X, p.Y)
-		}
-	}
-	for _, fb := range g.Fireballs {
-		set(5, fb.Pos.X, fb.Pos.Y)
-	}
-
-	return grid
+	return g.BuildObservation(ObservationV1, playerIdx)
 }
 
 // UpdateCompetitorAI decides the next move for the AI competitor (p2)
@@ -129,7 +48,7 @@ func (g *Game) UpdateCompetitorAI(p *Player) {
 		}
 
 		// If food is far away, occasionally boost to close the gap
-		if closestDist > 10 && rand.Float32() < 0.2 {
+		if closestDist > 10 && g.rng().Float32() < 0.2 {
 			boosting = true
 		}
 	}
@@ -207,7 +126,10 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 	head := snake[0]
 	var target Food
 	foundFood := false
-	currentDiff := "mid"
+	prof := g.aiProfile(playerIdx)
+	pers := g.aiPersonality(playerIdx)
+	ctx.Personality = pers.Name
+	currentDiff := prof.SpeedTier
 
 	// Find best target based on (Score / Distance) and (Time Check)
 	maxUtility := -1.0
@@ -219,7 +141,7 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 			dist = 0.5
 		}
 
-		remainingSec := food.GetRemainingSeconds(g.GetTotalPausedTime())
+		remainingSec := food.remainingSecondsAt(g.Now(), g.GetTotalPausedTime())
 		normalInterval := g.GetMoveIntervalExt(currentDiff, false)
 		timeNeededBoost := float64(dist) * g.GetMoveIntervalExt(currentDiff, true).Seconds()
 
@@ -228,7 +150,7 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 		}
 
 		totalScore := food.GetTotalScore(g.Width, g.Height)
-		utility := float64(totalScore) / dist
+		utility := float64(totalScore) * pers.FoodWeight / dist
 
 		if utility > maxUtility {
 			maxUtility = utility
@@ -241,6 +163,9 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 	// --- NEW: Prop Targeting ---
 	var targetProp *Prop
 	for i := range g.Props {
+		if prof.PropAwareness <= 0 {
+			break
+		}
 		p := &g.Props[i]
 		dist := float64(abs(p.Pos.X-head.X) + abs(p.Pos.Y-head.Y))
 		if dist == 0 {
@@ -248,7 +173,7 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 		}
 
 		// Treat props as high-value targets (base "score" of 80 for utility calc)
-		propUtility := 80.0 / dist
+		propUtility := 80.0 * prof.PropAwareness * pers.PropWeight / dist
 		if propUtility > maxUtility {
 			maxUtility = propUtility
 			targetProp = p
@@ -256,14 +181,32 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 		}
 	}
 
+	// --- Harassment: chasing personalities go after the enemy head ---
+	harass := false
+	var harassPos Point
+	if pers.ChaseWeight > 0 {
+		if pos, dist, ok := g.harassTarget(playerIdx, head); ok {
+			if u := 60.0 * pers.ChaseWeight / dist; u > maxUtility {
+				maxUtility = u
+				harassPos = pos
+				harass = true
+				foundFood = true
+			}
+		}
+	}
+
 	var targetPos Point
+	goalIntent := IntentHunt
 	if foundFood {
-		ctx.Intent = IntentHunt
-		if targetProp != nil {
+		if harass {
+			goalIntent = IntentHarass
+			targetPos = harassPos
+		} else if targetProp != nil {
 			targetPos = targetProp.Pos
 		} else {
 			targetPos = target.Pos
 		}
+		ctx.Intent = goalIntent
 		ctx.TargetPos = &targetPos
 
 		// --- NEW: Competitive Boosting ---
@@ -282,7 +225,7 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 		}
 
 		// 2. Catch-up logic (Berserker Mode): if food is far, occasionally boost to close gap
-		if !shouldBoost && distToTarget > 10 && g.BerserkerMode && rand.Float32() < 0.2 {
+		if !shouldBoost && distToTarget > 10 && g.isBerserker(playerIdx) && g.rng().Float32() < 0.2 {
 			shouldBoost = true
 		}
 	}
@@ -296,7 +239,7 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 		{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0},
 	}
 	// Shuffle dirs to avoid deterministic behavior when scores are equal
-	rand.Shuffle(len(possibleDirs), func(i, j int) {
+	g.rng().Shuffle(len(possibleDirs), func(i, j int) {
 		possibleDirs[i], possibleDirs[j] = possibleDirs[j], possibleDirs[i]
 	})
 
@@ -315,10 +258,11 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 
 		nextPos := Point{X: head.X + dir.X, Y: head.Y + dir.Y}
 		if !g.isSafe(nextPos, playerIdx) {
+			ctx.Candidates = append(ctx.Candidates, MoveCandidate{Dir: dir})
 			continue
 		}
 
-		reachableSpace := g.countReachableSpace(nextPos, playerIdx)
+		reachableSpace := g.countReachableSpace(nextPos, playerIdx, prof.LookaheadLimit)
 		score := float64(reachableSpace) * 50.0
 
 		isSurvive := false
@@ -334,6 +278,11 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 			score += 1000.0
 		}
 
+		// Cautious personalities keep away from enemy heads
+		if pers.DistanceWeight > 0 {
+			score -= g.enemyProximityPenalty(playerIdx, nextPos) * pers.DistanceWeight * 40.0
+		}
+
 		survivalThreshold := snakeLen + 10
 		if reachableSpace < survivalThreshold {
 			tail := snake[snakeLen-1]
@@ -345,6 +294,7 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 				isSurvive = true
 			}
 		}
+		ctx.Candidates = append(ctx.Candidates, MoveCandidate{Dir: dir, Safe: true, Space: reachableSpace, Score: score})
 
 		if score > bestScore {
 			bestScore = score
@@ -360,7 +310,7 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 				}
 			} else {
 				if foundFood {
-					ctx.Intent = IntentHunt
+					ctx.Intent = goalIntent
 				} else {
 					ctx.Intent = IntentIdle
 				}
@@ -368,6 +318,10 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 			}
 		}
 	}
+	// Undo the shuffle so the overlay lists directions in a stable order
+	slices.SortFunc(ctx.Candidates, func(a, b MoveCandidate) int {
+		return slices.Index(ActionDirections[:], a.Dir) - slices.Index(ActionDirections[:], b.Dir)
+	})
 
 	return bestDir, shouldBoost, ctx
 }
@@ -375,8 +329,9 @@ func (g *Game) CalculateBestMove(playerIdx int, snake []Point, lastMoveDir Point
 func (g *Game) handleAIFire(p *Player, ownerIdx int) bool {
 	head := p.Snake[0]
 	dir := p.Direction
-	// Look further for targets (up to 10 tiles)
-	for dist := 1; dist <= 10; dist++ {
+	// Look further for targets (up to 10 tiles, scaled by fire eagerness)
+	maxDist := int(10 * g.aiPersonality(ownerIdx).FireEagerness)
+	for dist := 1; dist <= maxDist; dist++ {
 		lookAhead := Point{X: head.X + dir.X*dist, Y: head.Y + dir.Y*dist}
 		if lookAhead.X <= 0 || lookAhead.X >= g.Width-1 || lookAhead.Y <= 0 || lookAhead.Y >= g.Height-1 {
 			break
@@ -435,8 +390,18 @@ func (g *Game) handleAIFire(p *Player, ownerIdx int) bool {
 
 // countReachableSpace uses a simple flood fill to count safe tiles.
 // It is now more optimistic about its own tail.
-func (g *Game) countReachableSpace(start Point, ownerIdx int) int {
-	visited := make(map[Point]bool)
+// The visited set is a flat grid rather than a map: this runs for every
+// candidate move, and MCTS rollouts call it thousands of times per decision.
+func (g *Game) countReachableSpace(start Point, ownerIdx int, limit int) int {
+	if start.X <= 0 || start.X >= g.Width-1 || start.Y <= 0 || start.Y >= g.Height-1 {
+		return 0
+	}
+	visited := make([]bool, g.Width*g.Height)
+	mark := func(p Point) {
+		if p.X >= 0 && p.X < g.Width && p.Y >= 0 && p.Y < g.Height {
+			visited[p.Y*g.Width+p.X] = true
+		}
+	}
 
 	// Pre-fill visited with all current obstacles
 	for i, p := range g.Players {
@@ -447,33 +412,32 @@ func (g *Game) countReachableSpace(start Point, ownerIdx int) int {
 			body = body[:len(body)-1]
 		}
 		for _, s := range body {
-			visited[s] = true
+			mark(s)
 		}
 	}
 	for _, obs := range g.Obstacles {
 		for _, op := range obs.Points {
-			visited[op] = true
+			mark(op)
 		}
 	}
 
-	if visited[start] {
+	if visited[start.Y*g.Width+start.X] {
 		return 0
 	}
 
 	queue := []Point{start}
-	visited[start] = true
+	visited[start.Y*g.Width+start.X] = true
 	count := 0
 
-	for len(queue) > 0 {
-		curr := queue[0]
-		queue = queue[1:]
+	for head := 0; head < len(queue); head++ {
+		curr := queue[head]
 		count++
 
-		if count > 400 { // Performance limit
+		if count > limit { // Lookahead / performance limit
 			return count
 		}
 
-		dirs := []Point{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
+		dirs := [4]Point{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
 		for _, d := range dirs {
 			next := Point{curr.X + d.X, curr.Y + d.Y}
 
@@ -482,8 +446,8 @@ func (g *Game) countReachableSpace(start Point, ownerIdx int) int {
 				continue
 			}
 
-			if !visited[next] {
-				visited[next] = true
+			if !visited[next.Y*g.Width+next.X] {
+				visited[next.Y*g.Width+next.X] = true
 				queue = append(queue, next)
 			}
 		}
@@ -549,7 +513,7 @@ func (g *Game) isSafe(p Point, ownerIdx int) bool {
 			if distToEnemyHead <= 1 {
 				// Only be cautious in non-berserker modes.
 				// In Berserker mode, we 'dare' to challenge for the same spot!
-				if !g.BerserkerMode {
+				if !g.isBerserker(ownerIdx) {
 					return false
 				}
 			}
@@ -559,6 +523,16 @@ func (g *Game) isSafe(p Point, ownerIdx int) bool {
 	return true
 }
 
+// IsSafeMove reports whether moving playerIdx one step in dir passes the same
+// check NeuralController uses before trusting the network
+func (g *Game) IsSafeMove(playerIdx int, dir Point) bool {
+	if playerIdx >= len(g.Players) || len(g.Players[playerIdx].Snake) == 0 {
+		return false
+	}
+	head := g.Players[playerIdx].Snake[0]
+	return g.isSafe(Point{X: head.X + dir.X, Y: head.Y + dir.Y}, playerIdx)
+}
+
 func abs(x int) int {
 	if x < 0 {
 		return -x
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// InferenceStats is a snapshot of the prediction workers' metrics
+type InferenceStats struct {
+	Backend         string  `json:"backend"` // Default model's backend: "onnx", "go" or "" when the service is not running
+	Workers         int     `json:"workers"`
+	QueueDepth      int     `json:"queue_depth"`
+	Requests        uint64  `json:"requests"`
+	Batches         uint64  `json:"batches"`
+	AvgBatchSize    float64 `json:"avg_batch_size"`
+	MaxBatchSize    int     `json:"max_batch_size"`
+	AvgLatencyMs    float64 `json:"avg_latency_ms"`    // Queue wait plus forward pass
+	RecentLatencyMs float64 `json:"recent_latency_ms"` // Exponential moving average
+	MaxLatencyMs    float64 `json:"max_latency_ms"`
+}
+
+// inferenceMetrics accumulates worker statistics
+type inferenceMetrics struct {
+	mu           sync.Mutex
+	workers      int
+	requests     uint64
+	batches      uint64
+	maxBatch     int
+	totalLatency time.Duration
+	maxLatency   time.Duration
+	ewmaLatency  float64 // Milliseconds
+}
+
+const latencyEWMAWeight = 0.05
+
+func (m *inferenceMetrics) start(workers int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers = workers
+}
+
+func (m *inferenceMetrics) observeBatch(size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batches++
+	if size > m.maxBatch {
+		m.maxBatch = size
+	}
+}
+
+func (m *inferenceMetrics) observeLatency(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests++
+	m.totalLatency += d
+	if d > m.maxLatency {
+		m.maxLatency = d
+	}
+	ms := float64(d) / float64(time.Millisecond)
+	if m.requests == 1 {
+		m.ewmaLatency = ms
+	} else {
+		m.ewmaLatency += latencyEWMAWeight * (ms - m.ewmaLatency)
+	}
+}
+
+// InferenceMetrics returns the current queue depth, batching and latency figures
+func InferenceMetrics() InferenceStats {
+	s := inferenceStats.snapshot()
+	s.QueueDepth = len(predictionQueue)
+	if Models != nil {
+		if mv, ok := Models.Get(""); ok {
+			s.Backend = mv.Backend
+		}
+	}
+	return s
+}
+
+func (m *inferenceMetrics) snapshot() InferenceStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := InferenceStats{
+		Workers:         m.workers,
+		Requests:        m.requests,
+		Batches:         m.batches,
+		MaxBatchSize:    m.maxBatch,
+		RecentLatencyMs: m.ewmaLatency,
+		MaxLatencyMs:    float64(m.maxLatency) / float64(time.Millisecond),
+	}
+	if m.batches > 0 {
+		s.AvgBatchSize = float64(m.requests) / float64(m.batches)
+	}
+	if m.requests > 0 {
+		s.AvgLatencyMs = float64(m.totalLatency) / float64(m.requests) / float64(time.Millisecond)
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// echoModel returns the first input value as every logit and records batch sizes
+type echoModel struct {
+	batches []int
+}
+
+func (m *echoModel) internalPredict(input []float64) []float32 {
+	return m.internalPredictBatch([][]float64{input})[0]
+}
+
+func (m *echoModel) internalPredictBatch(inputs [][]float64) [][]float32 {
+	m.batches = append(m.batches, len(inputs))
+	out := make([][]float32, len(inputs))
+	for i, in := range inputs {
+		v := float32(in[0])
+		out[i] = []float32{v, v, v, v}
+	}
+	return out
+}
+
+// TestInferenceWorkerBatches checks queued requests share a forward pass and get their own results
+func TestInferenceWorkerBatches(t *testing.T) {
+	queue := make(chan PredictRequest, 16)
+	model := &echoModel{}
+	stats := &inferenceMetrics{}
+
+	// Queue everything before the worker starts so it sees a full backlog
+	results := make([]chan []float32, 10)
+	for i := range results {
+		results[i] = make(chan []float32, 1)
+		queue <- PredictRequest{Input: []float64{float64(i)}, ResChan: results[i], Enqueued: time.Now(), Model: model}
+	}
+	stats.start(1)
+	go runInferenceWorker(queue, InferenceOptions{Workers: 1, BatchSize: 4, BatchWait: time.Millisecond}, stats)
+
+	for i, ch := range results {
+		select {
+		case logits := <-ch:
+			if logits[0] != float32(i) {
+				t.Errorf("request %d got logits for %v", i, logits[0])
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("request %d never answered", i)
+		}
+	}
+	close(queue)
+
+	s := stats.snapshot()
+	if s.Requests != 10 || s.Batches != 3 || s.MaxBatchSize != 4 {
+		t.Errorf("expected 10 requests in batches of 4, 4, 2, got %+v", s)
+	}
+}
@@ -3,159 +3,202 @@ package game
 import (
 	"fmt"
 	"log"
-	"os"
-	"runtime"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
-	ort "github.com/yalue/onnxruntime_go"
+	"github.com/trytobebee/snake_go/pkg/config"
 )
 
 // PredictRequest represents a single inference task in the queue
 type PredictRequest struct {
-	Input   []float64
-	ResChan chan []float32
+	Input    []float64
+	ResChan  chan []float32
+	Enqueued time.Time
+	Model    PolicyModel // Network that answers this request
+}
+
+// PolicyModel is an inference backend for the policy network:
+// ONNX Runtime when its shared library is available, pure Go otherwise.
+// internalPredictBatch may be called from several workers at once.
+type PolicyModel interface {
+	internalPredict(input []float64) []float32
+	internalPredictBatch(inputs [][]float64) [][]float32
+}
+
+// InferenceOptions tunes the global prediction workers. Zero fields keep the config defaults.
+type InferenceOptions struct {
+	Workers   int
+	BatchSize int
+	BatchWait time.Duration
 }
 
 var (
 	// The core queue for all games
 	predictionQueue   = make(chan PredictRequest, 200)
 	workerInitialized sync.Once
+	workerInitErr     error // Sticky result of the first initialization attempt
+
+	inferenceOpts = InferenceOptions{
+		Workers:   config.InferenceWorkers,
+		BatchSize: config.InferenceBatchSize,
+		BatchWait: config.InferenceBatchWait,
+	}
+	inferenceStats inferenceMetrics
 )
 
-// ONNXModel encapsulates the session and its dedicated tensors
-type ONNXModel struct {
-	session *ort.AdvancedSession
-	input   *ort.Tensor[float32]
-	output  *ort.Tensor[float32]
+// ConfigureInference sets the worker count and batching. It only has an
+// effect before the first StartInferenceService call.
+func ConfigureInference(opts InferenceOptions) {
+	if opts.Workers > 0 {
+		inferenceOpts.Workers = opts.Workers
+	}
+	if opts.BatchSize > 0 {
+		inferenceOpts.BatchSize = opts.BatchSize
+	}
+	if opts.BatchWait > 0 {
+		inferenceOpts.BatchWait = opts.BatchWait
+	}
 }
 
-// StartInferenceService initializes the global worker that "dumps" the queue
+// StartInferenceService initializes the global workers that "dump" the queue
 // This is the SINGLE point of execution for all AI Brains in the system.
+// Every model in modelPath's directory is loaded into Models and watched for
+// changes; modelPath itself names the default model.
 func StartInferenceService(modelPath string) error {
-	var err error
 	workerInitialized.Do(func() {
-		// 1. Init ONNX Env synchronously to ensure library exists
-		err = initORT()
-		if err != nil {
-			log.Printf("❌ AI Worker environment init failed: %v\n", err)
+		defaultName := strings.TrimSuffix(filepath.Base(modelPath), filepath.Ext(modelPath))
+		registry := NewModelRegistry(filepath.Dir(modelPath), defaultName)
+		_, err := registry.Reload()
+		if _, ok := registry.Get(""); !ok {
+			if err == nil {
+				err = fmt.Errorf("no model files for %s", modelPath)
+			}
+			log.Printf("❌ AI Worker init failed: %v\n", err)
+			workerInitErr = err
 			return
 		}
+		if err != nil {
+			log.Printf("⚠️ Some models failed to load: %v\n", err)
+		}
+		Models = registry
 
-		// 2. Start the worker goroutine
-		go func() {
-			// One model instance for the entire server
-			nn, nerr := loadModelInstance(modelPath)
-			if nerr != nil {
-				log.Printf("⚠️ AI Worker failed to load model: %v. Entering DRAIN mode.\n", nerr)
-				runDrainMode()
-				return
+		opts := inferenceOpts
+		inferenceStats.start(opts.Workers)
+		for i := 0; i < opts.Workers; i++ {
+			go runInferenceWorker(predictionQueue, opts, &inferenceStats)
+		}
+		go registry.Watch(config.ModelPollInterval, nil)
+		log.Printf("🚀 Global AI Optimizer-Worker is now online (%d workers, batches of up to %d)\n", opts.Workers, opts.BatchSize)
+	})
+	// Later games must see the same failure, not a silent success
+	return workerInitErr
+}
+
+// runInferenceWorker gathers whatever is queued, waiting up to BatchWait for
+// more, runs one batched forward pass per model and scatters the logits back
+func runInferenceWorker(queue <-chan PredictRequest, opts InferenceOptions, stats *inferenceMetrics) {
+	batch := make([]PredictRequest, 0, opts.BatchSize)
+
+	for req := range queue {
+		batch = append(batch[:0], req)
+		deadline := time.NewTimer(opts.BatchWait)
+	gather:
+		for len(batch) < opts.BatchSize {
+			select {
+			case next, ok := <-queue:
+				if !ok {
+					break gather
+				}
+				batch = append(batch, next)
+			case <-deadline.C:
+				break gather
+			}
+		}
+		deadline.Stop()
+
+		// Requests for different models (A/B tests) cannot share a tensor
+		var order []PolicyModel
+		groups := make(map[PolicyModel][]PredictRequest)
+		for _, r := range batch {
+			if _, ok := groups[r.Model]; !ok {
+				order = append(order, r.Model)
 			}
-			log.Println("🚀 Global AI Optimizer-Worker is now online (Queue-based)")
+			groups[r.Model] = append(groups[r.Model], r)
+		}
 
-			// The "Dumping" Loop
-			for req := range predictionQueue {
-				logits := nn.internalPredict(req.Input)
-				req.ResChan <- logits
+		for _, model := range order {
+			reqs := groups[model]
+			inputs := make([][]float64, len(reqs))
+			for i, r := range reqs {
+				inputs[i] = r.Input
 			}
-		}()
-	})
-	return err
-}
+			logits := model.internalPredictBatch(inputs)
 
-func runDrainMode() {
-	fallback := []float32{0.25, 0.25, 0.25, 0.25}
-	log.Println("⚡ AI Worker is in DRAIN mode (returning fallback predictions)")
-	for req := range predictionQueue {
-		// Immediately return to unblock callers
-		req.ResChan <- fallback
+			done := time.Now()
+			for i, r := range reqs {
+				r.ResChan <- logits[i]
+				stats.observeLatency(done.Sub(r.Enqueued))
+			}
+			stats.observeBatch(len(reqs))
+		}
 	}
 }
 
-// Predict is the client method. It pushes to the queue and waits for its turn.
-// This is non-blocking for the worker, and synchronous for the calling Game loop.
-func Predict(input []float64) []float32 {
-	resChan := make(chan []float32, 1)
-	predictionQueue <- PredictRequest{
-		Input:   input,
-		ResChan: resChan,
+// loadPolicyModel prefers ONNX Runtime and falls back to the pure-Go CNN,
+// which reads the weights exported next to the .onnx file (same name, .bin).
+// Both take their input shape from the model's observation spec.
+func loadPolicyModel(modelPath string, spec ObservationSpec) (PolicyModel, error) {
+	ortErr := initORT()
+	if ortErr == nil {
+		nn, err := loadModelInstance(modelPath, len(spec.Channels))
+		if err == nil {
+			return nn, nil
+		}
+		ortErr = err
 	}
-	return <-resChan
-}
-
-// --- Internal Helpers ---
-
-func loadModelInstance(modelPath string) (*ONNXModel, error) {
-	inputShape := ort.NewShape(1, 6, 25, 25)
-	inputData := make([]float32, 1*6*25*25)
-	inputTensor, _ := ort.NewTensor(inputShape, inputData)
-
-	outputShape := ort.NewShape(1, 4)
-	outputData := make([]float32, 1*4)
-	outputTensor, _ := ort.NewTensor(outputShape, outputData)
-
-	options, _ := ort.NewSessionOptions()
-	defer options.Destroy()
-	options.SetIntraOpNumThreads(0) // Full CPU power for the worker
-
-	session, err := ort.NewAdvancedSession(modelPath,
-		[]string{"input"}, []string{"output"},
-		[]ort.ArbitraryTensor{inputTensor}, []ort.ArbitraryTensor{outputTensor}, options)
 
+	weightsPath := strings.TrimSuffix(modelPath, filepath.Ext(modelPath)) + ".bin"
+	cnn, err := LoadCNNModel(weightsPath)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("ONNX Runtime unavailable (%v) and no pure-Go weights (%v)", ortErr, err)
 	}
-
-	return &ONNXModel{session, inputTensor, outputTensor}, nil
+	if cnn.InputChannels() != len(spec.Channels) {
+		return nil, fmt.Errorf("%s expects %d input channels but its observation spec v%d has %d",
+			weightsPath, cnn.InputChannels(), spec.Version, len(spec.Channels))
+	}
+	log.Printf("🐹 ONNX Runtime unavailable (%v). Running the policy network in pure Go from %s\n", ortErr, weightsPath)
+	return cnn, nil
 }
 
-func (m *ONNXModel) internalPredict(input []float64) []float32 {
-	inputData := m.input.GetData()
-	for i, v := range input {
-		inputData[i] = float32(v)
+// Predict is the client method. It pushes to the queue and waits for its turn.
+// This is non-blocking for the worker, and synchronous for the calling Game loop.
+// It uses the default model and returns nil when no model is loaded.
+func Predict(input []float64) []float32 {
+	if Models == nil {
+		return nil
 	}
-	_ = m.session.Run()
-
-	// Create a copy to ensure thread safety when handing back to game loop
-	res := m.output.GetData()
-	copied := make([]float32, len(res))
-	copy(copied, res)
-	return copied
+	mv, ok := Models.Get("")
+	if !ok {
+		return nil
+	}
+	return mv.Predict(input)
 }
 
-var ortInitialized sync.Once
-
-func initORT() error {
-	var err error
-	ortInitialized.Do(func() {
-		// Common paths for onnxruntime
-		possiblePaths := []string{
-			"/opt/homebrew/opt/onnxruntime/lib/libonnxruntime.dylib", // Apple Silicon Homebrew
-			"/usr/local/opt/onnxruntime/lib/libonnxruntime.dylib",    // Intel Homebrew
-			"/usr/local/lib/libonnxruntime.dylib",                    // Manual install
-		}
-
-		if runtime.GOOS == "linux" {
-			possiblePaths = []string{
-				"/usr/lib/libonnxruntime.so",
-				"/usr/local/lib/libonnxruntime.so",
-			}
-		}
-
-		var foundPath string
-		for _, path := range possiblePaths {
-			if _, e := os.Stat(path); e == nil {
-				foundPath = path
-				break
-			}
-		}
-
-		if foundPath == "" {
-			err = fmt.Errorf("onnxruntime library not found. Please install it (e.g., 'brew install onnxruntime' on macOS)")
-			return
-		}
-
-		ort.SetSharedLibraryPath(foundPath)
-		err = ort.InitializeEnvironment()
-	})
-	return err
+// Predict queues one observation, built from mv.Spec, for this model. It
+// returns nil if the version was unloaded in the meantime.
+func (mv *ModelVersion) Predict(input []float64) []float32 {
+	if !mv.acquire() {
+		return nil
+	}
+	defer mv.release()
+	resChan := make(chan []float32, 1)
+	predictionQueue <- PredictRequest{
+		Input:    input,
+		ResChan:  resChan,
+		Enqueued: time.Now(),
+		Model:    mv.model,
+	}
+	return <-resChan
 }
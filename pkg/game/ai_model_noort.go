@@ -0,0 +1,26 @@
+//go:build !cgo
+
+package game
+
+import "errors"
+
+var errNoORT = errors.New("built without cgo, ONNX Runtime is not available")
+
+// ONNXModel is a placeholder in CGO-free builds, where only the pure-Go backend exists
+type ONNXModel struct{}
+
+func (m *ONNXModel) internalPredict(input []float64) []float32 {
+	return nil
+}
+
+func (m *ONNXModel) internalPredictBatch(inputs [][]float64) [][]float32 {
+	return make([][]float32, len(inputs))
+}
+
+func loadModelInstance(modelPath string, channels int) (*ONNXModel, error) {
+	return nil, errNoORT
+}
+
+func initORT() error {
+	return errNoORT
+}
@@ -0,0 +1,181 @@
+//go:build cgo
+
+package game
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// ONNXModel encapsulates the session and its dedicated tensors
+type ONNXModel struct {
+	mu      sync.Mutex // Guards the single-sample tensors
+	session *ort.AdvancedSession
+	input   *ort.Tensor[float32]
+	output  *ort.Tensor[float32]
+
+	// batched runs whole batches when the model was exported with a dynamic
+	// batch axis. Older fixed-batch models disable it on the first failure.
+	batched     *ort.DynamicAdvancedSession
+	batchFailed atomic.Bool
+	channels    int // Observation planes per sample
+}
+
+func loadModelInstance(modelPath string, channels int) (*ONNXModel, error) {
+	inputShape := ort.NewShape(1, int64(channels), ObservationWindow, ObservationWindow)
+	inputData := make([]float32, channels*ObservationWindow*ObservationWindow)
+	inputTensor, _ := ort.NewTensor(inputShape, inputData)
+
+	outputShape := ort.NewShape(1, 4)
+	outputData := make([]float32, 1*4)
+	outputTensor, _ := ort.NewTensor(outputShape, outputData)
+
+	options, _ := ort.NewSessionOptions()
+	defer options.Destroy()
+	options.SetIntraOpNumThreads(0) // Full CPU power for the worker
+
+	session, err := ort.NewAdvancedSession(modelPath,
+		[]string{"input"}, []string{"output"},
+		[]ort.ArbitraryTensor{inputTensor}, []ort.ArbitraryTensor{outputTensor}, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ONNXModel{session: session, input: inputTensor, output: outputTensor, channels: channels}
+	if batched, err := ort.NewDynamicAdvancedSession(modelPath, []string{"input"}, []string{"output"}, options); err == nil {
+		m.batched = batched
+	} else {
+		log.Printf("⚠️ Batched ONNX session unavailable: %v. Running one sample at a time.\n", err)
+	}
+	return m, nil
+}
+
+// destroy frees the sessions and tensors once no prediction uses them anymore
+func (m *ONNXModel) destroy() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.session.Destroy()
+	if m.batched != nil {
+		m.batched.Destroy()
+	}
+	m.input.Destroy()
+	m.output.Destroy()
+}
+
+func (m *ONNXModel) internalPredict(input []float64) []float32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inputData := m.input.GetData()
+	for i, v := range input {
+		inputData[i] = float32(v)
+	}
+	_ = m.session.Run()
+
+	// Create a copy to ensure thread safety when handing back to game loop
+	res := m.output.GetData()
+	copied := make([]float32, len(res))
+	copy(copied, res)
+	return copied
+}
+
+func (m *ONNXModel) internalPredictBatch(inputs [][]float64) [][]float32 {
+	if len(inputs) > 1 && m.batched != nil && !m.batchFailed.Load() {
+		out, err := m.runBatched(inputs)
+		if err == nil {
+			return out
+		}
+		m.batchFailed.Store(true)
+		log.Printf("⚠️ Batched ONNX inference failed: %v. Falling back to one sample at a time.\n", err)
+	}
+
+	out := make([][]float32, len(inputs))
+	for i, input := range inputs {
+		out[i] = m.internalPredict(input)
+	}
+	return out
+}
+
+// runBatched stacks the inputs into one (N, C, 25, 25) tensor. Each call owns
+// its tensors, so several workers can share the session.
+func (m *ONNXModel) runBatched(inputs [][]float64) ([][]float32, error) {
+	n := len(inputs)
+	featureSize := m.channels * ObservationWindow * ObservationWindow
+	data := make([]float32, n*featureSize)
+	for i, input := range inputs {
+		row := data[i*featureSize : (i+1)*featureSize]
+		for j := 0; j < len(input) && j < featureSize; j++ {
+			row[j] = float32(input[j])
+		}
+	}
+
+	inputTensor, err := ort.NewTensor(ort.NewShape(int64(n), int64(m.channels), ObservationWindow, ObservationWindow), data)
+	if err != nil {
+		return nil, err
+	}
+	defer inputTensor.Destroy()
+	outputTensor, err := ort.NewEmptyTensor[float32](ort.NewShape(int64(n), int64(len(ActionDirections))))
+	if err != nil {
+		return nil, err
+	}
+	defer outputTensor.Destroy()
+
+	if err := m.batched.Run([]ort.Value{inputTensor}, []ort.Value{outputTensor}); err != nil {
+		return nil, err
+	}
+
+	res := outputTensor.GetData()
+	width := len(ActionDirections)
+	out := make([][]float32, n)
+	for i := range out {
+		out[i] = append([]float32(nil), res[i*width:(i+1)*width]...)
+	}
+	return out, nil
+}
+
+var (
+	ortInitialized sync.Once
+	ortInitErr     error // Sticky: every model load asks again
+)
+
+func initORT() error {
+	ortInitialized.Do(func() {
+		// Common paths for onnxruntime
+		possiblePaths := []string{
+			"/opt/homebrew/opt/onnxruntime/lib/libonnxruntime.dylib", // Apple Silicon Homebrew
+			"/usr/local/opt/onnxruntime/lib/libonnxruntime.dylib",    // Intel Homebrew
+			"/usr/local/lib/libonnxruntime.dylib",                    // Manual install
+		}
+
+		if runtime.GOOS == "linux" {
+			possiblePaths = []string{
+				"/usr/lib/libonnxruntime.so",
+				"/usr/local/lib/libonnxruntime.so",
+			}
+		}
+
+		var foundPath string
+		for _, path := range possiblePaths {
+			if _, e := os.Stat(path); e == nil {
+				foundPath = path
+				break
+			}
+		}
+
+		if foundPath == "" {
+			ortInitErr = fmt.Errorf("onnxruntime library not found. Please install it (e.g., 'brew install onnxruntime' on macOS)")
+			return
+		}
+
+		ort.SetSharedLibraryPath(foundPath)
+		ortInitErr = ort.InitializeEnvironment()
+	})
+	return ortInitErr
+}
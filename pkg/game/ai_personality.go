@@ -0,0 +1,139 @@
+package game
+
+import "sort"
+
+// AIPersonality weights the utility terms of CalculateBestMove. Where the
+// skill profile decides how well the AI plays, the personality decides what
+// it cares about.
+type AIPersonality struct {
+	Name           string
+	FoodWeight     float64 // Multiplier on food utility
+	PropWeight     float64 // Multiplier on prop utility
+	ChaseWeight    float64 // Pull towards cutting off the nearest enemy head (0 = never harass)
+	DistanceWeight float64 // Push away from enemy heads
+	FireEagerness  float64 // Scales fireball range and the chance of speculative shots
+}
+
+// AIPersonalities holds the built-in presets by name
+var AIPersonalities = map[string]*AIPersonality{
+	"balanced": {
+		Name:          "balanced",
+		FoodWeight:    1.0,
+		PropWeight:    1.0,
+		FireEagerness: 1.0,
+	},
+	"hunter": {
+		Name:          "hunter",
+		FoodWeight:    0.6,
+		PropWeight:    0.6,
+		ChaseWeight:   1.0,
+		FireEagerness: 1.2,
+	},
+	"collector": {
+		Name:           "collector",
+		FoodWeight:     1.3,
+		PropWeight:     1.4,
+		DistanceWeight: 0.3,
+		FireEagerness:  0.5,
+	},
+	"defender": {
+		Name:           "defender",
+		FoodWeight:     1.0,
+		PropWeight:     0.8,
+		DistanceWeight: 1.0,
+		FireEagerness:  0.8,
+	},
+	"sniper": {
+		Name:           "sniper",
+		FoodWeight:     0.8,
+		PropWeight:     1.0,
+		ChaseWeight:    0.3,
+		DistanceWeight: 0.6,
+		FireEagerness:  1.6,
+	},
+}
+
+// DefaultPersonality is used when a player has none assigned
+var DefaultPersonality = AIPersonalities["balanced"]
+
+// PersonalityNames returns the preset names in a stable order
+func PersonalityNames() []string {
+	names := make([]string, 0, len(AIPersonalities))
+	for name := range AIPersonalities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// aiPersonality returns the player's personality, balanced if none is set
+func (g *Game) aiPersonality(idx int) *AIPersonality {
+	if idx < len(g.Players) && g.Players[idx].Personality != nil {
+		return g.Players[idx].Personality
+	}
+	return DefaultPersonality
+}
+
+// SetAIPersonality assigns a preset to the AI competitors of a solo game.
+// Unknown names fall back to balanced.
+func (g *Game) SetAIPersonality(name string) {
+	if g.IsPVP {
+		return
+	}
+	pers, ok := AIPersonalities[name]
+	if !ok {
+		pers = DefaultPersonality
+	}
+	for i, p := range g.Players {
+		if i == 0 || p.Controller == "manual" {
+			continue
+		}
+		p.Personality = pers
+	}
+}
+
+// harassTarget returns the tile in front of the nearest enemy head, where
+// a hunter would cut it off
+func (g *Game) harassTarget(idx int, head Point) (Point, float64, bool) {
+	var best Point
+	bestDist := -1.0
+	for i, other := range g.Players {
+		if i == idx || len(other.Snake) == 0 {
+			continue
+		}
+		dir := other.LastMoveDir
+		if dir.X == 0 && dir.Y == 0 {
+			dir = other.Direction
+		}
+		cut := Point{X: other.Snake[0].X + dir.X*2, Y: other.Snake[0].Y + dir.Y*2}
+		if cut.X <= 0 || cut.X >= g.Width-1 || cut.Y <= 0 || cut.Y >= g.Height-1 {
+			cut = other.Snake[0]
+		}
+		d := float64(abs(cut.X-head.X) + abs(cut.Y-head.Y))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = cut, d
+		}
+	}
+	if bestDist < 0 {
+		return Point{}, 0, false
+	}
+	if bestDist == 0 {
+		bestDist = 0.5
+	}
+	return best, bestDist, true
+}
+
+// enemyProximityPenalty scores how close a tile is to enemy heads (0 beyond 6 tiles)
+func (g *Game) enemyProximityPenalty(idx int, pos Point) float64 {
+	penalty := 0.0
+	for i, other := range g.Players {
+		if i == idx || len(other.Snake) == 0 {
+			continue
+		}
+		d := abs(other.Snake[0].X-pos.X) + abs(other.Snake[0].Y-pos.Y)
+		if d < 6 {
+			penalty += float64(6 - d)
+		}
+	}
+	return penalty
+}
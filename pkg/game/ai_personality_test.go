@@ -0,0 +1,48 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// TestHunterHarassesEnemyHead checks that a hunter with nothing else to do goes after the player
+func TestHunterHarassesEnemyHead(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.Foods = nil
+	g.Props = nil
+	g.Players[0].Snake = []Point{{X: 5, Y: 12}, {X: 4, Y: 12}}
+	g.Players[0].LastMoveDir = Point{X: 1, Y: 0}
+	p := g.Players[1]
+	p.Snake = []Point{{X: 20, Y: 12}, {X: 21, Y: 12}}
+	p.LastMoveDir = Point{X: -1, Y: 0}
+
+	g.SetAIPersonality("hunter")
+	dir, _, ctx := g.CalculateBestMove(1, p.Snake, p.LastMoveDir)
+
+	if ctx.Intent != IntentHarass {
+		t.Errorf("expected HARASS intent, got %s", ctx.Intent)
+	}
+	if ctx.Personality != "hunter" {
+		t.Errorf("expected personality to be recorded in the context, got %q", ctx.Personality)
+	}
+	if dir != (Point{X: -1, Y: 0}) {
+		t.Errorf("expected hunter to head towards the player, got %v", dir)
+	}
+}
+
+// TestSetAIPersonalityFallsBack checks unknown presets become balanced and humans are untouched
+func TestSetAIPersonalityFallsBack(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.SetAIPersonality("pacifist")
+
+	if g.Players[1].Personality != DefaultPersonality {
+		t.Errorf("expected balanced fallback, got %v", g.Players[1].Personality)
+	}
+	if g.Players[0].Personality != nil {
+		t.Errorf("the human player must not get a personality")
+	}
+	if got := g.GetGameStateSnapshot(false, false, "mid").AIPersonality; got != "balanced" {
+		t.Errorf("expected snapshot to expose the personality, got %q", got)
+	}
+}
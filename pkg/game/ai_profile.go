@@ -0,0 +1,118 @@
+package game
+
+// AIProfile describes how well an AI competitor plays, independently of how
+// fast it moves. Solo difficulties map to profiles so that "low" is an
+// opponent a beginner can actually beat, not just a slower one.
+type AIProfile struct {
+	Name             string
+	SpeedTier        string  // Move interval tier used for timing ("low", "mid", "high")
+	DecisionNoise    float64 // Chance of taking a random safe move instead of the planned one
+	ReactionDelay    int     // Moves a decision is kept (while safe) before re-planning
+	LookaheadLimit   int     // Flood-fill cap when judging how much room a move leaves
+	FireRange        int     // Tiles scanned ahead for a fireball target
+	FireAccuracy     float64 // Chance a spotted shot is actually taken
+	PropAwareness    float64 // Multiplier on prop utility (0 ignores props)
+	BoostWillingness float64 // Chance a planned boost is actually used
+}
+
+// Built-in skill profiles, weakest first
+var (
+	ProfileBeginner = &AIProfile{
+		Name:             "beginner",
+		SpeedTier:        "mid",
+		DecisionNoise:    0.2,
+		ReactionDelay:    2,
+		LookaheadLimit:   30,
+		FireRange:        3,
+		FireAccuracy:     0.3,
+		PropAwareness:    0.2,
+		BoostWillingness: 0.3,
+	}
+	ProfileNormal = &AIProfile{
+		Name:             "normal",
+		SpeedTier:        "mid",
+		DecisionNoise:    0.06,
+		ReactionDelay:    1,
+		LookaheadLimit:   120,
+		FireRange:        5,
+		FireAccuracy:     0.6,
+		PropAwareness:    0.6,
+		BoostWillingness: 0.7,
+	}
+	ProfileHard = &AIProfile{
+		Name:             "hard",
+		SpeedTier:        "mid",
+		LookaheadLimit:   400,
+		FireRange:        8,
+		FireAccuracy:     1.0,
+		PropAwareness:    1.0,
+		BoostWillingness: 1.0,
+	}
+)
+
+// AIProfileForDifficulty maps a solo difficulty to the opponent's skill profile.
+// "expert" shares the hard profile; its extra strength comes from MCTS.
+func AIProfileForDifficulty(difficulty string) *AIProfile {
+	switch difficulty {
+	case "low":
+		return ProfileBeginner
+	case "mid":
+		return ProfileNormal
+	default:
+		return ProfileHard
+	}
+}
+
+// aiProfile returns the player's skill profile, full strength if none is set
+func (g *Game) aiProfile(idx int) *AIProfile {
+	if idx < len(g.Players) && g.Players[idx].Profile != nil {
+		return g.Players[idx].Profile
+	}
+	return ProfileHard
+}
+
+// aiWantsFire combines target detection (limited by FireRange) with FireAccuracy
+func (g *Game) aiWantsFire(idx int, dir Point) bool {
+	if !g.shouldAIFire(idx, dir) {
+		return false
+	}
+	acc := g.aiProfile(idx).FireAccuracy
+	return acc >= 1 || g.rng().Float64() < acc
+}
+
+// aiWantsBoost filters a planned boost through BoostWillingness
+func (g *Game) aiWantsBoost(idx int, boost bool) bool {
+	if !boost {
+		return false
+	}
+	will := g.aiProfile(idx).BoostWillingness
+	return will >= 1 || g.rng().Float64() < will
+}
+
+// noisyDirection returns a random safe, non-reversing direction with
+// probability DecisionNoise, or ok=false to keep the planned move
+func (g *Game) noisyDirection(idx int) (Point, bool) {
+	prof := g.aiProfile(idx)
+	if prof.DecisionNoise <= 0 || g.rng().Float64() >= prof.DecisionNoise {
+		return Point{}, false
+	}
+	p := g.Players[idx]
+	if len(p.Snake) == 0 {
+		return Point{}, false
+	}
+	head := p.Snake[0]
+
+	var options []Point
+	for _, dir := range []Point{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}} {
+		if (dir.X != 0 && p.LastMoveDir.X == -dir.X) || (dir.Y != 0 && p.LastMoveDir.Y == -dir.Y) {
+			continue
+		}
+		if g.isSafe(Point{X: head.X + dir.X, Y: head.Y + dir.Y}, idx) {
+			options = append(options, dir)
+		}
+	}
+	if len(options) == 0 {
+		return Point{}, false
+	}
+	return options[g.rng().Intn(len(options))], true
+}
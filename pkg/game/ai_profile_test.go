@@ -0,0 +1,71 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// TestSetAIDifficultyAssignsProfile checks each solo tier maps to a skill profile
+func TestSetAIDifficultyAssignsProfile(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.Players[1].Brain = &HeuristicController{}
+	g.Players[1].Controller = "heuristic"
+
+	cases := map[string]*AIProfile{
+		"low":    ProfileBeginner,
+		"mid":    ProfileNormal,
+		"high":   ProfileHard,
+		"expert": ProfileHard,
+	}
+	for diff, want := range cases {
+		g.SetAIDifficulty(diff)
+		if g.Players[1].Profile != want {
+			t.Errorf("%s: expected profile %s, got %v", diff, want.Name, g.Players[1].Profile)
+		}
+		if g.Players[0].Profile != nil {
+			t.Errorf("%s: the human player must not get an AI profile", diff)
+		}
+	}
+}
+
+// TestProfileLimitsFireRange checks that weaker profiles spot targets later
+func TestProfileLimitsFireRange(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.Players[1].Snake = []Point{{X: 5, Y: 10}, {X: 4, Y: 10}}
+	g.Obstacles = []Obstacle{{Points: []Point{{X: 10, Y: 10}}, SpawnTime: time.Now(), Duration: 30}}
+	right := Point{X: 1, Y: 0}
+
+	g.Players[1].Profile = ProfileHard
+	if !g.shouldAIFire(1, right) {
+		t.Errorf("hard profile should see an obstacle 5 tiles ahead")
+	}
+	g.Players[1].Profile = ProfileBeginner
+	if g.shouldAIFire(1, right) {
+		t.Errorf("beginner profile should not see an obstacle 5 tiles ahead")
+	}
+}
+
+// TestReactionDelayHoldsDirection checks a slow profile keeps its last decision while safe
+func TestReactionDelayHoldsDirection(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	p := g.Players[1]
+	p.Profile = &AIProfile{SpeedTier: "mid", ReactionDelay: 3, LookaheadLimit: 400, FireRange: 1}
+	p.Snake = []Point{{X: 5, Y: 18}, {X: 6, Y: 18}}
+	p.LastMoveDir = Point{X: -1, Y: 0}
+
+	c := &HeuristicController{heldDir: Point{X: 0, Y: -1}, heldMoves: 2}
+	if a := c.GetAction(g, 1); a.Direction != (Point{X: 0, Y: -1}) {
+		t.Errorf("expected held direction, got %v", a.Direction)
+	}
+	if c.heldMoves != 1 {
+		t.Errorf("expected one held move left, got %d", c.heldMoves)
+	}
+
+	// A held move into the wall is dropped immediately
+	p.Snake = []Point{{X: 5, Y: 1}, {X: 6, Y: 1}}
+	if a := c.GetAction(g, 1); a.Direction == (Point{X: 0, Y: -1}) {
+		t.Errorf("held direction into a wall should be re-planned")
+	}
+}
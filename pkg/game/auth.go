@@ -1,6 +1,8 @@
 package game
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -8,12 +10,16 @@ import (
 )
 
 type User struct {
-	Username     string    `json:"username"`
-	PasswordHash string    `json:"-"`
-	BestScore    int       `json:"best_score"`
-	TotalGames   int       `json:"total_games"`
-	TotalWins    int       `json:"total_wins"`
-	CreatedAt    time.Time `json:"created_at"`
+	Username      string    `json:"username"`
+	PasswordHash  string    `json:"-"`
+	BestScore     int       `json:"best_score"`
+	TotalGames    int       `json:"total_games"`
+	TotalWins     int       `json:"total_wins"`
+	CreatedAt     time.Time `json:"created_at"`
+	SkillEstimate float64   `json:"skill_estimate"` // Dynamic difficulty starting level (0-1)
+	IsBot         bool      `json:"is_bot"`         // Plays through the bot API with a token instead of a password
+	Rating        float64   `json:"rating"`         // Elo rating from matchmaking games
+	RatedGames    int       `json:"rated_games"`
 }
 
 type UserManager struct {
@@ -41,14 +47,49 @@ func (um *UserManager) Register(username, password string) error {
 	return nil
 }
 
+// RegisterBot creates a bot account and returns its API token. Like a
+// password, only the token's hash is stored, so it is shown once.
+func (um *UserManager) RegisterBot(username string) (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	hash, err := bcrypt.GenerateFromPassword([]byte(token), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = DB.Exec(
+		"INSERT INTO users (username, password_hash, is_bot) VALUES (?, ?, 1)",
+		username, string(hash),
+	)
+	if err != nil {
+		return "", errors.New("user already exists or database error")
+	}
+	return token, nil
+}
+
+// LoginBot authenticates a bot account with its API token
+func (um *UserManager) LoginBot(username, token string) (*User, error) {
+	user, err := um.Login(username, token)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsBot {
+		return nil, errors.New("not a bot account")
+	}
+	return user, nil
+}
+
 func (um *UserManager) Login(username, password string) (*User, error) {
 	user := &User{}
 	var hash string
 
 	err := DB.QueryRow(
-		"SELECT username, password_hash, best_score, total_games, total_wins, created_at FROM users WHERE username = ?",
+		"SELECT username, password_hash, best_score, total_games, total_wins, created_at, skill_estimate, is_bot, rating, rated_games FROM users WHERE username = ?",
 		username,
-	).Scan(&user.Username, &hash, &user.BestScore, &user.TotalGames, &user.TotalWins, &user.CreatedAt)
+	).Scan(&user.Username, &hash, &user.BestScore, &user.TotalGames, &user.TotalWins, &user.CreatedAt, &user.SkillEstimate, &user.IsBot, &user.Rating, &user.RatedGames)
 
 	if err != nil {
 		return nil, errors.New("user not found")
@@ -83,9 +124,21 @@ func (um *UserManager) UpdateStats(username string, score int, won bool) (*User,
 	// Fetch updated user
 	user := &User{}
 	err = DB.QueryRow(
-		"SELECT username, best_score, total_games, total_wins, created_at FROM users WHERE username = ?",
+		"SELECT username, best_score, total_games, total_wins, created_at, skill_estimate, is_bot, rating, rated_games FROM users WHERE username = ?",
 		username,
-	).Scan(&user.Username, &user.BestScore, &user.TotalGames, &user.TotalWins, &user.CreatedAt)
+	).Scan(&user.Username, &user.BestScore, &user.TotalGames, &user.TotalWins, &user.CreatedAt, &user.SkillEstimate, &user.IsBot, &user.Rating, &user.RatedGames)
 
 	return user, err
 }
+
+// UpdateSkillEstimate persists the dynamic difficulty level a user should start at next time
+func (um *UserManager) UpdateSkillEstimate(username string, skill float64) error {
+	_, err := DB.Exec("UPDATE users SET skill_estimate = ? WHERE username = ?", skill, username)
+	return err
+}
+
+// UpdateRating stores a user's new rating after a rated game
+func (um *UserManager) UpdateRating(username string, rating float64) error {
+	_, err := DB.Exec("UPDATE users SET rating = ?, rated_games = rated_games + 1 WHERE username = ?", rating, username)
+	return err
+}
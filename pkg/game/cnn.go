@@ -0,0 +1,280 @@
+package game
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Pure-Go inference for the policy network in ml/model.py.
+// ml/export_weights.py folds each BatchNorm into its convolution and writes
+// the tensors in a small binary format:
+//
+//	"SNKW" | uint32 version | uint32 tensor count
+//	per tensor: uint16 name length | name | uint8 rank | uint32 dims... | float32 data
+//
+// All integers and floats are little-endian.
+const (
+	cnnMagic   = "SNKW"
+	cnnVersion = 1
+	cnnGrid    = 25 // Spatial size of the feature grid
+)
+
+// convLayer is a 3x3 convolution with padding 1 followed by ReLU
+type convLayer struct {
+	in, out int
+	weight  []float32 // [out][in][3][3]
+	bias    []float32
+}
+
+// denseLayer is a fully connected layer
+type denseLayer struct {
+	in, out int
+	weight  []float32 // [out][in]
+	bias    []float32
+}
+
+// CNNModel runs the conv-conv-conv-fc-fc policy network on the CPU
+type CNNModel struct {
+	convs []convLayer
+	fc1   denseLayer
+	fc2   denseLayer
+}
+
+// LoadCNNModel reads weights written by ml/export_weights.py
+func LoadCNNModel(path string) (*CNNModel, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tensors, err := readTensors(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return newCNNModel(tensors)
+}
+
+type tensor struct {
+	dims []int
+	data []float32
+}
+
+func readTensors(r io.Reader) (map[string]tensor, error) {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != cnnMagic {
+		return nil, fmt.Errorf("not a weights file")
+	}
+	var version, count uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != cnnVersion {
+		return nil, fmt.Errorf("unsupported weights version %d", version)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+
+	tensors := make(map[string]tensor, count)
+	for i := uint32(0); i < count; i++ {
+		var nameLen uint16
+		if err := binary.Read(r, binary.LittleEndian, &nameLen); err != nil {
+			return nil, err
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, err
+		}
+		var rank uint8
+		if err := binary.Read(r, binary.LittleEndian, &rank); err != nil {
+			return nil, err
+		}
+		dims32 := make([]uint32, rank)
+		if err := binary.Read(r, binary.LittleEndian, dims32); err != nil {
+			return nil, err
+		}
+		t := tensor{dims: make([]int, rank)}
+		size := 1
+		for j, d := range dims32 {
+			t.dims[j] = int(d)
+			size *= int(d)
+		}
+		t.data = make([]float32, size)
+		if err := binary.Read(r, binary.LittleEndian, t.data); err != nil {
+			return nil, fmt.Errorf("tensor %s: %w", name, err)
+		}
+		tensors[string(name)] = t
+	}
+	return tensors, nil
+}
+
+func newCNNModel(tensors map[string]tensor) (*CNNModel, error) {
+	get := func(name string, rank int) (tensor, error) {
+		t, ok := tensors[name]
+		if !ok {
+			return t, fmt.Errorf("missing tensor %s", name)
+		}
+		if len(t.dims) != rank {
+			return t, fmt.Errorf("tensor %s has rank %d, want %d", name, len(t.dims), rank)
+		}
+		return t, nil
+	}
+
+	m := &CNNModel{}
+	conv1, err := get("conv1.weight", 4)
+	if err != nil {
+		return nil, err
+	}
+	channels := conv1.dims[1] // Input planes, checked against the model's observation spec
+	for _, name := range []string{"conv1", "conv2", "conv3"} {
+		w, err := get(name+".weight", 4)
+		if err != nil {
+			return nil, err
+		}
+		b, err := get(name+".bias", 1)
+		if err != nil {
+			return nil, err
+		}
+		if w.dims[1] != channels || w.dims[2] != 3 || w.dims[3] != 3 || b.dims[0] != w.dims[0] {
+			return nil, fmt.Errorf("%s has shape %v, want [*, %d, 3, 3]", name, w.dims, channels)
+		}
+		m.convs = append(m.convs, convLayer{in: channels, out: w.dims[0], weight: w.data, bias: b.data})
+		channels = w.dims[0]
+	}
+
+	in := channels * cnnGrid * cnnGrid
+	for _, spec := range []struct {
+		name  string
+		layer *denseLayer
+	}{{"fc1", &m.fc1}, {"fc2", &m.fc2}} {
+		w, err := get(spec.name+".weight", 2)
+		if err != nil {
+			return nil, err
+		}
+		b, err := get(spec.name+".bias", 1)
+		if err != nil {
+			return nil, err
+		}
+		if w.dims[1] != in || b.dims[0] != w.dims[0] {
+			return nil, fmt.Errorf("%s has shape %v, want [*, %d]", spec.name, w.dims, in)
+		}
+		*spec.layer = denseLayer{in: in, out: w.dims[0], weight: w.data, bias: b.data}
+		in = w.dims[0]
+	}
+	if m.fc2.out != len(ActionDirections) {
+		return nil, fmt.Errorf("fc2 has %d outputs, want %d", m.fc2.out, len(ActionDirections))
+	}
+	return m, nil
+}
+
+// InputChannels returns the number of observation planes the network reads
+func (m *CNNModel) InputChannels() int {
+	return m.convs[0].in
+}
+
+// Forward returns the action logits for one Cx25x25 observation
+func (m *CNNModel) Forward(input []float64) []float32 {
+	h := m.fc1.forward(m.features(input), true)
+	return m.fc2.forward(h, false)
+}
+
+// ForwardBatch runs several grids at once. The convolutions run per sample,
+// but the large fc1 matrix is streamed once per batch instead of once per sample.
+func (m *CNNModel) ForwardBatch(inputs [][]float64) [][]float32 {
+	feats := make([][]float32, len(inputs))
+	for i, input := range inputs {
+		feats[i] = m.features(input)
+	}
+	hidden := m.fc1.forwardBatch(feats, true)
+	out := make([][]float32, len(inputs))
+	for i, h := range hidden {
+		out[i] = m.fc2.forward(h, false)
+	}
+	return out
+}
+
+// features runs the convolution stack and returns the flattened activations
+func (m *CNNModel) features(input []float64) []float32 {
+	x := make([]float32, m.InputChannels()*cnnGrid*cnnGrid)
+	for i := 0; i < len(x) && i < len(input); i++ {
+		x[i] = float32(input[i])
+	}
+	for i := range m.convs {
+		x = m.convs[i].forward(x)
+	}
+	return x
+}
+
+func (m *CNNModel) internalPredict(input []float64) []float32 {
+	return m.Forward(input)
+}
+
+func (m *CNNModel) internalPredictBatch(inputs [][]float64) [][]float32 {
+	return m.ForwardBatch(inputs)
+}
+
+func (l *convLayer) forward(x []float32) []float32 {
+	const n = cnnGrid
+	out := make([]float32, l.out*n*n)
+	for o := 0; o < l.out; o++ {
+		plane := out[o*n*n : (o+1)*n*n]
+		for i := range plane {
+			plane[i] = l.bias[o]
+		}
+		for c := 0; c < l.in; c++ {
+			src := x[c*n*n : (c+1)*n*n]
+			k := l.weight[(o*l.in+c)*9 : (o*l.in+c)*9+9]
+			for ky := 0; ky < 3; ky++ {
+				for kx := 0; kx < 3; kx++ {
+					w := k[ky*3+kx]
+					if w == 0 {
+						continue
+					}
+					dy, dx := ky-1, kx-1
+					for y := max(0, -dy); y < min(n, n-dy); y++ {
+						row := plane[y*n : y*n+n]
+						srow := src[(y+dy)*n : (y+dy)*n+n]
+						for xx := max(0, -dx); xx < min(n, n-dx); xx++ {
+							row[xx] += w * srow[xx+dx]
+						}
+					}
+				}
+			}
+		}
+		for i, v := range plane {
+			if v < 0 {
+				plane[i] = 0
+			}
+		}
+	}
+	return out
+}
+
+func (l *denseLayer) forward(x []float32, relu bool) []float32 {
+	return l.forwardBatch([][]float32{x}, relu)[0]
+}
+
+func (l *denseLayer) forwardBatch(xs [][]float32, relu bool) [][]float32 {
+	out := make([][]float32, len(xs))
+	for n := range out {
+		out[n] = make([]float32, l.out)
+	}
+	for o := 0; o < l.out; o++ {
+		row := l.weight[o*l.in : (o+1)*l.in]
+		for n, x := range xs {
+			sum := l.bias[o]
+			for i, w := range row {
+				sum += w * x[i]
+			}
+			if relu && sum < 0 {
+				sum = 0
+			}
+			out[n][o] = sum
+		}
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeWeights encodes tensors in the export_weights.py format
+func writeWeights(t *testing.T, path string, tensors map[string]tensor) string {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString(cnnMagic)
+	binary.Write(&buf, binary.LittleEndian, uint32(cnnVersion))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(tensors)))
+	for name, tn := range tensors {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(name)))
+		buf.WriteString(name)
+		buf.WriteByte(uint8(len(tn.dims)))
+		for _, d := range tn.dims {
+			binary.Write(&buf, binary.LittleEndian, uint32(d))
+		}
+		binary.Write(&buf, binary.LittleEndian, tn.data)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// tinyCNNTensors builds a network that counts food shifted one tile right:
+// conv1 copies the food channel from the left neighbour, conv2/conv3 pass it
+// through, fc1 sums the plane and fc2 turns the sum n into logits [0, n, 2n, 3n]
+func tinyCNNTensors() map[string]tensor {
+	const plane = cnnGrid * cnnGrid
+	conv1 := make([]float32, 6*9)
+	conv1[4*9+1*3+0] = 1
+	center := make([]float32, 9)
+	center[4] = 1
+
+	fc1 := make([]float32, 2*plane)
+	for i := 0; i < plane; i++ {
+		fc1[i] = 1
+	}
+	fc2 := []float32{0, 0, 1, 0, 2, 0, 3, 0}
+
+	return map[string]tensor{
+		"conv1.weight": {dims: []int{1, 6, 3, 3}, data: conv1},
+		"conv1.bias":   {dims: []int{1}, data: []float32{0}},
+		"conv2.weight": {dims: []int{1, 1, 3, 3}, data: center},
+		"conv2.bias":   {dims: []int{1}, data: []float32{0}},
+		"conv3.weight": {dims: []int{1, 1, 3, 3}, data: center},
+		"conv3.bias":   {dims: []int{1}, data: []float32{0}},
+		"fc1.weight":   {dims: []int{2, plane}, data: fc1},
+		"fc1.bias":     {dims: []int{2}, data: []float32{0, -1}},
+		"fc2.weight":   {dims: []int{4, 2}, data: fc2},
+		"fc2.bias":     {dims: []int{4}, data: []float32{0, 0, 0, 0}},
+	}
+}
+
+// TestCNNForward runs the tiny hand-built network
+func TestCNNForward(t *testing.T) {
+	const plane = cnnGrid * cnnGrid
+	path := writeWeights(t, filepath.Join(t.TempDir(), "policy.bin"), tinyCNNTensors())
+
+	m, err := LoadCNNModel(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	input := make([]float64, 6*plane)
+	input[4*plane+5*cnnGrid+3] = 1         // Counted
+	input[4*plane+9*cnnGrid+7] = 1         // Counted
+	input[4*plane+2*cnnGrid+cnnGrid-1] = 1 // Shifted off the right edge
+	input[0*plane+5*cnnGrid+3] = 1         // Other channels are ignored
+	logits := m.Forward(input)
+
+	want := []float32{0, 2, 4, 6}
+	for i := range want {
+		if logits[i] != want[i] {
+			t.Fatalf("expected logits %v, got %v", want, logits)
+		}
+	}
+
+	// A batch must match running each sample on its own
+	batch := m.ForwardBatch([][]float64{make([]float64, 6*plane), input})
+	for i := range want {
+		if batch[0][i] != 0 || batch[1][i] != want[i] {
+			t.Fatalf("batched logits %v differ from single runs", batch)
+		}
+	}
+}
+
+// TestCNNRejectsBadWeights checks shape validation and the file header
+func TestCNNRejectsBadWeights(t *testing.T) {
+	path := writeWeights(t, filepath.Join(t.TempDir(), "bad.bin"), map[string]tensor{
+		"conv1.weight": {dims: []int{1, 5, 3, 3}, data: make([]float32, 45)},
+		"conv1.bias":   {dims: []int{1}, data: []float32{0}},
+	})
+	if _, err := LoadCNNModel(path); err == nil {
+		t.Error("expected an error for a conv1 with the wrong input channels")
+	}
+
+	junk := filepath.Join(t.TempDir(), "junk.bin")
+	os.WriteFile(junk, []byte("not weights"), 0644)
+	if _, err := LoadCNNModel(junk); err == nil {
+		t.Error("expected an error for a file without the weights header")
+	}
+}
@@ -1,7 +1,8 @@
 package game
 
 import (
-	"math/rand"
+	"fmt"
+	"strings"
 
 	"github.com/trytobebee/snake_go/pkg/config"
 )
@@ -11,6 +12,13 @@ type Controller interface {
 	GetAction(g *Game, playerIdx int) ActionData
 }
 
+// hasAIContext reports whether the player is driven by the engine's own AI,
+// which leaves its reasoning in LastContext. Humans, bots and WASM modules
+// decide elsewhere.
+func (p *Player) hasAIContext() bool {
+	return p.Controller != "manual" && p.Controller != "bot" && !strings.HasPrefix(p.Controller, WasmBotPrefix)
+}
+
 // --- Implementation: Manual Controller (Human) ---
 
 type ManualController struct {
@@ -36,21 +44,44 @@ func (c *ManualController) SetFire(fire bool) {
 
 // --- Implementation: Heuristic AI Controller ---
 
-type HeuristicController struct{}
+type HeuristicController struct {
+	// Reaction delay state (see AIProfile.ReactionDelay)
+	heldDir   Point
+	heldMoves int
+}
 
 func (c *HeuristicController) GetAction(g *Game, playerIdx int) ActionData {
 	if playerIdx >= len(g.Players) {
 		return ActionData{}
 	}
 	p := g.Players[playerIdx]
+	prof := g.aiProfile(playerIdx)
 
-	newDir, boosting, _ := g.CalculateBestMove(playerIdx, p.Snake, p.LastMoveDir)
+	var newDir Point
+	boosting := false
+	if c.heldMoves > 0 && c.canHold(g, playerIdx) {
+		// Slow reactions: keep going the way we decided earlier
+		c.heldMoves--
+		newDir = c.heldDir
+	} else {
+		var ctx AIContext
+		newDir, boosting, ctx = g.CalculateBestMove(playerIdx, p.Snake, p.LastMoveDir)
+		g.CurrentAIContext = ctx
+		p.LastContext = ctx
+		c.heldDir = newDir
+		c.heldMoves = prof.ReactionDelay
+	}
+	if dir, ok := g.noisyDirection(playerIdx); ok {
+		newDir = dir
+	}
+
+	boosting = g.aiWantsBoost(playerIdx, boosting)
 
 	// Intelligent Firing Module
-	fire := g.shouldAIFire(playerIdx, newDir)
+	fire := g.aiWantsFire(playerIdx, newDir)
 	if !fire && !g.IsPVP {
 		// Rare random shots in solo mode only
-		fire = rand.Float32() < 0.01
+		fire = g.rng().Float64() < 0.01*g.aiPersonality(playerIdx).FireEagerness
 	}
 
 	return ActionData{
@@ -60,11 +91,54 @@ func (c *HeuristicController) GetAction(g *Game, playerIdx int) ActionData {
 	}
 }
 
+// canHold reports whether the held direction is still a legal, safe move
+func (c *HeuristicController) canHold(g *Game, idx int) bool {
+	p := g.Players[idx]
+	if len(p.Snake) == 0 {
+		return false
+	}
+	d := c.heldDir
+	if (d.X != 0 && p.LastMoveDir.X == -d.X) || (d.Y != 0 && p.LastMoveDir.Y == -d.Y) {
+		return false
+	}
+	return g.isSafe(Point{X: p.Snake[0].X + d.X, Y: p.Snake[0].Y + d.Y}, idx)
+}
+
 // --- Implementation: Neural Network Controller ---
 
-type NeuralController struct{}
+// NeuralController plays the policy network. Model picks a registered model
+// by name; empty uses the game's model (see Game.Model).
+type NeuralController struct {
+	Model string
+}
+
+// DefaultModelPath is the model every game starts the inference service with
+const DefaultModelPath = "ml/checkpoints/snake_policy.onnx"
+
+// CheckNeural reports why a NeuralController for model (empty for the
+// default) would fall back to the heuristic on a width x height board
+func CheckNeural(model string, width, height int) error {
+	if width != config.StandardWidth || height != config.StandardHeight {
+		return fmt.Errorf("neural models only play %dx%d boards", config.StandardWidth, config.StandardHeight)
+	}
+	if err := StartInferenceService(DefaultModelPath); err != nil {
+		return fmt.Errorf("neural inference is not running: %w", err)
+	}
+	if _, ok := Models.Get(model); !ok {
+		var names []string
+		for _, mv := range Models.List() {
+			names = append(names, mv.Name)
+		}
+		return fmt.Errorf("no model %q loaded (available: %s)", model, strings.Join(names, ", "))
+	}
+	return nil
+}
 
 func (c *NeuralController) GetAction(g *Game, playerIdx int) ActionData {
+	if playerIdx < len(g.Players) {
+		g.Players[playerIdx].LastModel = ""
+	}
+
 	// Fallback check: If board size is not standard 25x25, use heuristic AI
 	// as the model is currently only trained for 25x25.
 	if g.Width != config.StandardWidth || g.Height != config.StandardHeight {
@@ -78,9 +152,23 @@ func (c *NeuralController) GetAction(g *Game, playerIdx int) ActionData {
 		return hc.GetAction(g, playerIdx)
 	}
 
+	model := c.Model
+	if model == "" {
+		model = g.Model
+	}
 	p := g.Players[playerIdx]
-	input := g.GetFeatureGrid(playerIdx)
-	logits := Predict(input)
+	mv, ok := g.NeuralNet.Get(model)
+	if !ok {
+		// The model was removed or never loaded
+		hc := &HeuristicController{}
+		return hc.GetAction(g, playerIdx)
+	}
+	logits := mv.Predict(g.BuildObservation(mv.Spec, playerIdx))
+	if logits == nil {
+		// Replaced by a newer version while we asked
+		hc := &HeuristicController{}
+		return hc.GetAction(g, playerIdx)
+	}
 
 	// Simple argmax
 	bestIdx := 0
@@ -93,36 +181,35 @@ func (c *NeuralController) GetAction(g *Game, playerIdx int) ActionData {
 	}
 
 	var newDir Point
-	switch bestIdx {
-	case 0:
-		newDir = Point{X: 0, Y: -1}
-	case 1:
-		newDir = Point{X: 0, Y: 1}
-	case 2:
-		newDir = Point{X: -1, Y: 0}
-	case 3:
-		newDir = Point{X: 1, Y: 0}
+	if bestIdx < len(ActionDirections) {
+		newDir = ActionDirections[bestIdx]
 	}
 
 	// Safety check - if NN suggests suicide, fallback
-	nextHead := Point{X: p.Snake[0].X + newDir.X, Y: p.Snake[0].Y + newDir.Y}
-	if !g.isSafe(nextHead, playerIdx) {
+	if !g.IsSafeMove(playerIdx, newDir) {
 		hc := &HeuristicController{}
 		return hc.GetAction(g, playerIdx)
 	}
+	p.LastModel = mv.Version
+
+	if dir, ok := g.noisyDirection(playerIdx); ok {
+		newDir = dir
+	}
 
 	// Intelligent Firing Module for Neural Controller (Auto-Aim)
 	fire := false
 	if len(p.Snake) > 0 {
-		fire = g.shouldAIFire(playerIdx, newDir)
+		fire = g.aiWantsFire(playerIdx, newDir)
 	}
 
 	// Determine boosting using the shared heuristic logic
-	_, shouldBoost, _ := g.CalculateBestMove(playerIdx, p.Snake, p.Direction)
+	_, shouldBoost, ctx := g.CalculateBestMove(playerIdx, p.Snake, p.Direction)
+	g.CurrentAIContext = ctx
+	p.LastContext = ctx
 
 	return ActionData{
 		Direction: newDir,
-		Boost:     shouldBoost,
+		Boost:     g.aiWantsBoost(playerIdx, shouldBoost),
 		Fire:      fire,
 	}
 }
@@ -138,8 +225,9 @@ func (g *Game) shouldAIFire(idx int, dir Point) bool {
 	}
 
 	head := p.Snake[0]
-	// Range: 8 tiles
-	for dist := 1; dist <= 8; dist++ {
+	// Range depends on the skill profile (8 tiles at full strength) and fire eagerness
+	maxDist := int(float64(g.aiProfile(idx).FireRange) * g.aiPersonality(idx).FireEagerness)
+	for dist := 1; dist <= maxDist; dist++ {
 		look := Point{X: head.X + dir.X*dist, Y: head.Y + dir.Y*dist}
 
 		// If it's a wall, stop looking (using g instance bounds)
@@ -2,6 +2,7 @@ package game
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"os"
 
@@ -15,7 +16,9 @@ func InitDB() {
 	if err := os.MkdirAll("data", 0755); err != nil {
 		log.Fatal("Failed to create data directory:", err)
 	}
-	DB, err = sql.Open("sqlite", "data/game.db")
+	// Wait for a busy database instead of failing: match results write
+	// several rows at once while other players log in
+	DB, err = sql.Open("sqlite", "data/game.db?_pragma=busy_timeout(5000)")
 	if err != nil {
 		log.Fatal("Failed to open database:", err)
 	}
@@ -51,6 +54,20 @@ func createTables() {
 			mode TEXT,
 			difficulty TEXT
 		)`,
+		`CREATE TABLE IF NOT EXISTS pvp_series (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			player1 TEXT,
+			player2 TEXT,
+			best_of INTEGER,
+			wins1 INTEGER,
+			wins2 INTEGER,
+			draws INTEGER,
+			games INTEGER,
+			winner TEXT,
+			forfeit INTEGER DEFAULT 0,
+			start_time DATETIME,
+			end_time DATETIME
+		)`,
 		`CREATE TABLE IF NOT EXISTS feedback (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			username TEXT,
@@ -65,4 +82,45 @@ func createTables() {
 			log.Fatalf("Failed to create table (%s): %v", query, err)
 		}
 	}
+
+	// Columns added after the first release
+	ensureColumn("leaderboard", "opponents", "INTEGER DEFAULT 1")
+	ensureColumn("users", "skill_estimate", "REAL DEFAULT 0.5")
+	ensureColumn("game_sessions", "adaptive_level", "INTEGER DEFAULT 0")
+	ensureColumn("users", "is_bot", "INTEGER DEFAULT 0")
+	ensureColumn("users", "rating", fmt.Sprintf("REAL DEFAULT %v", DefaultRating))
+	ensureColumn("users", "rated_games", "INTEGER DEFAULT 0")
+	ensureColumn("game_sessions", "vs_bot", "INTEGER DEFAULT 0")
+}
+
+// ensureColumn adds a column to an existing table if it is missing
+func ensureColumn(table, column, definition string) {
+	rows, err := DB.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		log.Fatalf("Failed to inspect table %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			log.Fatalf("Failed to read columns of %s: %v", table, err)
+		}
+		if name == column {
+			return
+		}
+	}
+	rows.Close()
+
+	if _, err := DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		log.Fatalf("Failed to add column %s.%s: %v", table, column, err)
+	}
+	log.Printf("🛠️ Migrated database: added %s.%s\n", table, column)
 }
@@ -0,0 +1,135 @@
+package game
+
+// Transition is one (S, A, R, S', Done) sample for offline training, built
+// from one player's perspective with the engine's own observation builder
+type Transition struct {
+	Obs     []float64
+	Action  int // Index into ActionDirections
+	Reward  float64
+	NextObs []float64
+	Done    bool
+}
+
+// EpisodeSummary describes a recorded game for dataset filtering
+type EpisodeSummary struct {
+	Steps      int
+	Mode       string
+	Difficulty string
+	Outcome    string // "win", "loss", "draw" or "unfinished"
+	Score      int    // The player's final score
+	Controller string // How the player was controlled ("" in recordings made before per-player steps)
+}
+
+// EpisodePlayers returns how many players a recording has a perspective for.
+// Older recordings only carry P1's actions.
+func EpisodePlayers(records []StepRecord) int {
+	n := 1
+	for _, rec := range records {
+		n = max(n, len(rec.Players))
+	}
+	return n
+}
+
+// SummarizeEpisode reads the outcome and final score of player idx from a
+// game's records, played on a width x height board
+func SummarizeEpisode(records []StepRecord, idx, width, height int) EpisodeSummary {
+	if len(records) == 0 {
+		return EpisodeSummary{Outcome: "unfinished"}
+	}
+	lastRec := records[len(records)-1]
+	last := lastRec.State
+	g := GameFromState(last, width, height)
+	s := EpisodeSummary{
+		Steps:      len(records),
+		Mode:       last.Mode,
+		Difficulty: last.Difficulty,
+		Outcome:    "unfinished",
+	}
+	if idx < len(g.Players) {
+		s.Score = g.Players[idx].Score
+	}
+	if idx < len(lastRec.Players) {
+		s.Controller = lastRec.Players[idx].Controller
+	}
+	if last.GameOver {
+		switch {
+		case last.Winner == "draw" || last.Winner == "none":
+			s.Outcome = "draw"
+		case g.wonBy(idx):
+			s.Outcome = "win"
+		default:
+			s.Outcome = "loss"
+		}
+	}
+	return s
+}
+
+// BuildTransitions pairs each recorded step with the next one, seen by player
+// idx. A record holds the state after a move together with that move, so the
+// action taken in state i is the one stored with record i+1. The reward
+// applies the given weights to the recorded RewardEvents; recordings made
+// before events were stored only yield score, survival and outcome terms.
+// Steps where the player did not move (paused, waiting to start, or another
+// player's turn) are skipped.
+func BuildTransitions(records []StepRecord, spec ObservationSpec, reward RewardWeights, idx, width, height int) []Transition {
+	var out []Transition
+	// The next state's game and observation become the current ones on the following step
+	var carried *Game
+	var carriedObs []float64
+	for i := 0; i+1 < len(records); i++ {
+		cur, nxt := records[i].State, records[i+1].State
+		if !cur.Started || cur.GameOver || cur.Paused || len(cur.Snake) == 0 || len(nxt.Snake) == 0 {
+			carried = nil
+			continue
+		}
+
+		g, obs := carried, carriedObs
+		if g == nil {
+			g = GameFromState(cur, width, height)
+			obs = g.BuildObservation(spec, idx)
+		}
+		next := GameFromState(nxt, width, height)
+		carried, carriedObs = next, next.BuildObservation(spec, idx)
+		if idx >= len(g.Players) || idx >= len(next.Players) || len(g.Players[idx].Snake) == 0 {
+			continue
+		}
+
+		events := next.RewardEvents(idx, g.Players[idx].Tally())
+		var dir Point
+		switch after := records[i+1]; {
+		case idx < len(after.Players):
+			if !after.Players[idx].Moved {
+				continue
+			}
+			dir = after.Players[idx].Action.Direction
+			if after.Players[idx].Events != nil {
+				events = *after.Players[idx].Events
+			}
+		case idx == 0:
+			dir = after.Action.Direction // Recorded before per-player steps
+		default:
+			continue
+		}
+		if dir == (Point{}) {
+			dir = g.Players[idx].LastMoveDir
+		}
+		action := -1
+		for a, d := range ActionDirections {
+			if d == dir {
+				action = a
+			}
+		}
+		if action < 0 {
+			continue
+		}
+
+		out = append(out, Transition{
+			Obs:     obs,
+			Action:  action,
+			Reward:  reward.Reward(events),
+			NextObs: carriedObs,
+			Done:    nxt.GameOver,
+		})
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package game
+
+import "testing"
+
+// recordedStep is a minimal record of P1 at head, having just moved in dir
+func recordedStep(head, dir Point, score int, over bool) StepRecord {
+	tail := Point{X: head.X - dir.X, Y: head.Y - dir.Y}
+	state := GameState{
+		Snake:         []Point{head, tail},
+		AISnake:       []Point{{X: 3, Y: 3}, {X: 3, Y: 4}},
+		Score:         score,
+		Started:       true,
+		GameOver:      over,
+		TimeRemaining: 50,
+		Mode:          "battle",
+		Difficulty:    "mid",
+	}
+	if over {
+		state.Winner = "ai"
+	}
+	return StepRecord{State: state, Action: ActionData{Direction: dir}}
+}
+
+// TestBuildTransitions checks actions come from the following record and rewards from PlayerReward
+func TestBuildTransitions(t *testing.T) {
+	right, down := Point{X: 1}, Point{Y: 1}
+	records := []StepRecord{
+		recordedStep(Point{X: 10, Y: 10}, right, 0, false),
+		recordedStep(Point{X: 11, Y: 10}, right, 10, false), // Ate food on the way here
+		recordedStep(Point{X: 11, Y: 11}, down, 10, false),
+		recordedStep(Point{X: 11, Y: 12}, down, 10, true), // Crashed
+	}
+
+	ts := BuildTransitions(records, ObservationV1, DefaultReward, 0, 25, 25)
+	if len(ts) != 3 {
+		t.Fatalf("expected 3 transitions, got %d", len(ts))
+	}
+	wantActions := []int{3, 1, 1} // right, down, down
+	wantRewards := []float64{10.1, 0.1, -100}
+	for i, tr := range ts {
+		if tr.Action != wantActions[i] {
+			t.Errorf("transition %d: action %d, want %d", i, tr.Action, wantActions[i])
+		}
+		if diff := tr.Reward - wantRewards[i]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("transition %d: reward %v, want %v", i, tr.Reward, wantRewards[i])
+		}
+		if tr.Done != (i == 2) {
+			t.Errorf("transition %d: done %v", i, tr.Done)
+		}
+	}
+
+	// The next observation of one step is the observation of the following step
+	for i := range ts[0].NextObs {
+		if ts[0].NextObs[i] != ts[1].Obs[i] {
+			t.Fatal("consecutive transitions should share the observation between them")
+		}
+	}
+
+	s := SummarizeEpisode(records, 0, 25, 25)
+	if s.Outcome != "loss" || s.Score != 10 || s.Mode != "battle" || s.Steps != 4 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+}
+
+// TestBuildTransitionsPerPlayer checks the opponent's perspective uses its own
+// recorded moves and skips steps where it did not move
+func TestBuildTransitionsPerPlayer(t *testing.T) {
+	right, up := Point{X: 1}, Point{Y: -1}
+	records := []StepRecord{
+		recordedStep(Point{X: 10, Y: 10}, right, 0, false),
+		recordedStep(Point{X: 11, Y: 10}, right, 0, false),
+		recordedStep(Point{X: 12, Y: 10}, right, 0, false),
+		recordedStep(Point{X: 13, Y: 10}, right, 0, true),
+	}
+	records[1].Players = []PlayerStep{{Index: 0, Moved: true, Action: ActionData{Direction: right}}, {Index: 1, Controller: "heuristic", Moved: true, Action: ActionData{Direction: up}}}
+	records[2].Players = []PlayerStep{{Index: 0, Moved: true, Action: ActionData{Direction: right}}, {Index: 1, Controller: "heuristic"}}
+	records[3].Players = []PlayerStep{{Index: 0, Moved: true, Action: ActionData{Direction: right}}, {Index: 1, Controller: "heuristic", Moved: true, Action: ActionData{Direction: up}}}
+
+	if n := EpisodePlayers(records); n != 2 {
+		t.Fatalf("expected 2 players, got %d", n)
+	}
+	ts := BuildTransitions(records, ObservationV1, DefaultReward, 1, 25, 25)
+	if len(ts) != 2 {
+		t.Fatalf("expected 2 transitions for the opponent, got %d", len(ts))
+	}
+	for i, tr := range ts {
+		if tr.Action != 0 {
+			t.Errorf("transition %d: action %d, want up", i, tr.Action)
+		}
+	}
+	// P1 crashed with the winner recorded as "ai", so the opponent is not penalised
+	if ts[1].Reward != 0 || !ts[1].Done {
+		t.Errorf("final opponent transition: reward %v done %v", ts[1].Reward, ts[1].Done)
+	}
+	if s := SummarizeEpisode(records, 1, 25, 25); s.Outcome != "win" || s.Controller != "heuristic" {
+		t.Errorf("unexpected opponent summary %+v", s)
+	}
+}
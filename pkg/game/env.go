@@ -0,0 +1,360 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// ActionDirections maps a discrete action index onto a heading.
+// The order matches the policy network's output: up, down, left, right.
+var ActionDirections = [4]Point{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}}
+
+// ObservationScalars names the scalar features that accompany the feature grid, in order
+var ObservationScalars = []string{
+	"dir_x", "dir_y", "length", "score_diff", "time_left",
+	"boosting", "stunned", "fire_ready", "opponent_dist",
+}
+
+// EnvConfig describes the episodes an Env plays
+type EnvConfig struct {
+	Width       int    // Board width (default StandardWidth, MinBoardSize to MaxBoardSize)
+	Height      int    // Board height (default StandardHeight, same range)
+	Mode        string // "battle" (opponent respawns) or "pvp" (either crash ends it)
+	Opponent    string // Registered controller driving the other snakes
+	Opponents   int    // Number of opponents in battle mode (free-for-all when > 1)
+	Speed       string // Speed tier of the agent: low, mid or high
+	Observation int    // Observation spec version of the grid (default 1)
+	Reward      string // Reward preset (default "default")
+	Curriculum  string // Start from generated scenarios (see ParseCurriculum), "" for normal starts
+}
+
+func (c EnvConfig) withDefaults() EnvConfig {
+	if c.Width <= 0 {
+		c.Width = config.StandardWidth
+	}
+	if c.Height <= 0 {
+		c.Height = config.StandardHeight
+	}
+	if c.Mode == "" {
+		c.Mode = "battle"
+	}
+	if c.Opponent == "" {
+		c.Opponent = "heuristic"
+	}
+	if c.Opponents <= 0 {
+		c.Opponents = 1
+	}
+	if c.Speed == "" {
+		c.Speed = "mid"
+	}
+	if c.Observation <= 0 {
+		c.Observation = 1
+	}
+	return c
+}
+
+// EnvAction is one decision of the external agent
+type EnvAction struct {
+	Direction int // Index into ActionDirections, -1 keeps the current heading
+	Boost     bool
+	Fire      bool
+}
+
+// Observation is what the agent sees after each step
+type Observation struct {
+	Grid    []float64 // Agent's view, one plane per channel of the configured ObservationSpec
+	Scalars []float64 // Values named by ObservationScalars
+}
+
+// EnvInfo carries diagnostics that are not part of the observation
+type EnvInfo struct {
+	Score         int
+	OpponentScore int
+	Winner        string
+	Steps         int
+	Ticks         int
+	Scenario      string  // Curriculum scenario of the episode, if any
+	Difficulty    float64 // Its difficulty
+}
+
+// EnvStep is the result of Reset or Step
+type EnvStep struct {
+	Observation Observation
+	Reward      float64
+	Done        bool
+	Info        EnvInfo
+}
+
+// agentController replays whatever the external agent chose for this move
+type agentController struct {
+	action EnvAction
+}
+
+func (c *agentController) GetAction(g *Game, playerIdx int) ActionData {
+	a := ActionData{Boost: c.action.Boost, Fire: c.action.Fire}
+	if c.action.Direction >= 0 && c.action.Direction < len(ActionDirections) {
+		a.Direction = ActionDirections[c.action.Direction]
+	}
+	return a
+}
+
+// Env is a reinforcement learning environment around one headless game.
+// The agent always plays the first snake; one Step lasts until the agent
+// has moved once, however many BaseTicks that takes at its current speed.
+type Env struct {
+	Game *Game
+
+	cfg    EnvConfig
+	spec   ObservationSpec
+	reward RewardWeights
+	sim    *Simulator
+	agent  *agentController
+	steps  int
+	ticks  int
+}
+
+// Reset starts a new seeded episode and returns the first observation
+func (e *Env) Reset(seed int64, cfg EnvConfig) (EnvStep, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Width < config.MinBoardSize || cfg.Width > config.MaxBoardSize || cfg.Height < config.MinBoardSize || cfg.Height > config.MaxBoardSize {
+		return EnvStep{}, fmt.Errorf("board size %dx%d out of range (%d to %d)", cfg.Width, cfg.Height, config.MinBoardSize, config.MaxBoardSize)
+	}
+	if cfg.Mode != "battle" && cfg.Mode != "pvp" {
+		return EnvStep{}, fmt.Errorf("unknown mode %q", cfg.Mode)
+	}
+	spec, err := ObservationSpecVersion(cfg.Observation)
+	if err != nil {
+		return EnvStep{}, err
+	}
+	reward, err := RewardPreset(cfg.Reward)
+	if err != nil {
+		return EnvStep{}, err
+	}
+	curriculum, err := ParseCurriculum(cfg.Curriculum)
+	if err != nil {
+		return EnvStep{}, err
+	}
+
+	g := NewSeededGame(cfg.Width, cfg.Height, seed)
+	g.Headless = true
+	if cfg.Mode == "pvp" {
+		g.SetupPVP("agent", cfg.Opponent)
+	} else {
+		g.SetupFFA(cfg.Opponents)
+	}
+	for _, p := range g.Players[1:] {
+		brain, err := NewController(cfg.Opponent)
+		if err != nil {
+			g.ReleaseControllers()
+			return EnvStep{}, err
+		}
+		p.Brain = brain
+		p.Controller = cfg.Opponent
+	}
+
+	e.agent = &agentController{action: EnvAction{Direction: -1}}
+	g.Players[0].Brain = e.agent
+	g.Players[0].Controller = "agent"
+	if curriculum != nil {
+		if _, err := curriculum.Apply(g, seed); err != nil {
+			g.ReleaseControllers()
+			return EnvStep{}, err
+		}
+	}
+
+	if e.Game != nil {
+		e.Game.ReleaseControllers()
+	}
+	e.Game = g
+	e.cfg = cfg
+	e.spec = spec
+	e.reward = reward
+	e.sim = NewSimulator(g, cfg.Speed)
+	e.steps = 0
+	e.ticks = 0
+	return e.result(0), nil
+}
+
+// errNotReset is returned by Step before a Reset has succeeded
+var errNotReset = errors.New("environment has no episode, reset it first")
+
+// Step applies the agent's action and advances the game until the agent's next move
+func (e *Env) Step(a EnvAction) (EnvStep, error) {
+	g := e.Game
+	if g == nil {
+		return EnvStep{}, errNotReset
+	}
+	if g.GameOver {
+		return e.result(0), nil
+	}
+
+	e.agent.action = a
+	since := g.Players[0].Tally()
+	moves := e.sim.Moves(0)
+	for !g.GameOver && e.sim.Moves(0) == moves {
+		e.sim.Tick()
+		e.ticks++
+	}
+	e.steps++
+	return e.result(e.reward.Reward(g.RewardEvents(0, since))), nil
+}
+
+func (e *Env) result(reward float64) EnvStep {
+	g := e.Game
+	best := 0
+	for _, p := range g.Players[1:] {
+		if p.Score > best {
+			best = p.Score
+		}
+	}
+	info := EnvInfo{
+		Score:         g.Players[0].Score,
+		OpponentScore: best,
+		Winner:        g.Winner,
+		Steps:         e.steps,
+		Ticks:         e.ticks,
+	}
+	if g.Scenario != nil {
+		info.Scenario, info.Difficulty = g.Scenario.Name, g.Scenario.Difficulty
+	}
+	return EnvStep{
+		Observation: e.observe(),
+		Reward:      reward,
+		Done:        g.GameOver,
+		Info:        info,
+	}
+}
+
+// observe builds the agent's view: the observation grid plus ObservationScalars
+func (e *Env) observe() Observation {
+	g := e.Game
+	me := g.Players[0]
+	obs := Observation{Grid: g.BuildObservation(e.spec, 0)}
+
+	boolf := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	best := 0
+	nearest := g.Width + g.Height
+	for _, p := range g.Players[1:] {
+		if p.Score > best {
+			best = p.Score
+		}
+		if len(p.Snake) > 0 && len(me.Snake) > 0 {
+			if d := manhattan(me.Snake[0], p.Snake[0]); d < nearest {
+				nearest = d
+			}
+		}
+	}
+
+	cooldown := config.FireballCooldown
+	for _, eff := range me.Effects {
+		if eff.Type == EffectRapidFire {
+			cooldown /= 2
+		}
+	}
+
+	obs.Scalars = []float64{
+		float64(me.Direction.X),
+		float64(me.Direction.Y),
+		float64(len(me.Snake)) / float64(g.Width*g.Height),
+		clampSigned(float64(me.Score-best) / adaptScoreGapScale),
+		float64(g.GetTimeRemaining()) / g.GameDuration().Seconds(),
+		boolf(me.Boosting),
+		boolf(me.Stunned),
+		boolf(g.Now().Sub(me.LastFireTime) >= cooldown),
+		float64(nearest) / float64(g.Width+g.Height),
+	}
+	return obs
+}
+
+func manhattan(a, b Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+// EnvPool runs many independent environments in lockstep
+type EnvPool struct {
+	Envs      []*Env
+	AutoReset bool // Start a new episode (next seed) as soon as one ends
+
+	cfg   EnvConfig
+	seeds []int64
+}
+
+// Reset (re)creates the pool with one environment per seed. If any reset
+// fails, the pool keeps its old environments.
+func (p *EnvPool) Reset(seeds []int64, cfg EnvConfig) ([]EnvStep, error) {
+	envs := make([]*Env, len(seeds))
+	out := make([]EnvStep, len(seeds))
+	for i, seed := range seeds {
+		envs[i] = &Env{}
+		res, err := envs[i].Reset(seed, cfg)
+		if err != nil {
+			for _, env := range envs[:i] {
+				env.Game.ReleaseControllers()
+			}
+			return nil, fmt.Errorf("env %d: reset with seed %d: %w", i, seed, err)
+		}
+		out[i] = res
+	}
+
+	p.Close()
+	p.Envs = envs
+	p.seeds = append([]int64(nil), seeds...)
+	p.cfg = cfg
+	return out, nil
+}
+
+// Close releases the controllers of every environment
+func (p *EnvPool) Close() {
+	for _, env := range p.Envs {
+		if env.Game != nil {
+			env.Game.ReleaseControllers()
+		}
+	}
+}
+
+// Step advances every environment by one agent move in parallel.
+// With AutoReset, a finished environment reports its terminal reward and
+// info but the observation of the fresh episode; a failed reset is an error.
+func (p *EnvPool) Step(actions []EnvAction) ([]EnvStep, error) {
+	if len(actions) != len(p.Envs) {
+		return nil, fmt.Errorf("got %d actions for %d environments", len(actions), len(p.Envs))
+	}
+	out := make([]EnvStep, len(p.Envs))
+	errs := make([]error, len(p.Envs))
+	var wg sync.WaitGroup
+	for i, env := range p.Envs {
+		wg.Add(1)
+		go func(i int, env *Env) {
+			defer wg.Done()
+			res, err := env.Step(actions[i])
+			if err != nil {
+				errs[i] = fmt.Errorf("env %d: %w", i, err)
+				return
+			}
+			out[i] = res
+			if out[i].Done && p.AutoReset {
+				p.seeds[i] += int64(len(p.Envs))
+				fresh, err := env.Reset(p.seeds[i], p.cfg)
+				if err != nil {
+					errs[i] = fmt.Errorf("env %d: reset with seed %d: %w", i, p.seeds[i], err)
+					return
+				}
+				out[i].Observation = fresh.Observation
+			}
+		}(i, env)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
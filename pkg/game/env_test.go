@@ -0,0 +1,114 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestEnvResetAndStep checks observation shapes and that a step is one agent move
+func TestEnvResetAndStep(t *testing.T) {
+	env := &Env{}
+	res, err := env.Reset(1, EnvConfig{Mode: "pvp"})
+	if err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if len(res.Observation.Grid) != 6*25*25 {
+		t.Errorf("expected a 6x25x25 grid, got %d values", len(res.Observation.Grid))
+	}
+	if v2, err := (&Env{}).Reset(1, EnvConfig{Observation: 2}); err != nil || len(v2.Observation.Grid) != ObservationV2.Size() {
+		t.Errorf("expected a spec v2 grid, got %d values (%v)", len(v2.Observation.Grid), err)
+	}
+	if len(res.Observation.Scalars) != len(ObservationScalars) {
+		t.Errorf("expected %d scalars, got %d", len(ObservationScalars), len(res.Observation.Scalars))
+	}
+	if res.Done {
+		t.Fatal("a fresh episode should not be done")
+	}
+
+	// The timer is relative to the game's own length
+	env.Game.Duration = 30 * time.Second
+	if left := env.observe().Scalars[4]; left < 0.9 {
+		t.Errorf("expected time_left near 1 early in a 30s game, got %.2f", left)
+	}
+
+	if _, err := (&Env{}).Step(EnvAction{}); err == nil {
+		t.Error("expected an error stepping an environment that was never reset")
+	}
+
+	head := env.Game.Players[0].Snake[0]
+	res, err = env.Step(EnvAction{Direction: 3})
+	if err != nil {
+		t.Fatalf("step failed: %v", err)
+	}
+	next := env.Game.Players[0].Snake[0]
+	if next.X != head.X+1 || next.Y != head.Y {
+		t.Errorf("expected the agent to move right once, went from %v to %v", head, next)
+	}
+	if res.Info.Steps != 1 || res.Info.Ticks == 0 {
+		t.Errorf("unexpected info after one step: %+v", res.Info)
+	}
+
+	if _, err := env.Reset(1, EnvConfig{Opponent: "nope"}); err == nil {
+		t.Error("expected an error for an unknown opponent controller")
+	}
+	for _, size := range [][2]int{{4, 25}, {25, 9}, {101, 25}} {
+		if _, err := env.Reset(1, EnvConfig{Width: size[0], Height: size[1]}); err == nil {
+			t.Errorf("expected an error for a %dx%d board", size[0], size[1])
+		}
+	}
+}
+
+// TestEnvPoolLockstep runs a small pool to completion with auto-reset
+func TestEnvPoolLockstep(t *testing.T) {
+	pool := &EnvPool{AutoReset: true}
+	if _, err := pool.Reset([]int64{1, 2, 3}, EnvConfig{}); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if _, err := pool.Step([]EnvAction{{}}); err == nil {
+		t.Error("expected an error when the action count does not match")
+	}
+
+	// A rejected reset leaves the running environments in place
+	if _, err := pool.Reset([]int64{1, 2}, EnvConfig{Width: 5}); err == nil {
+		t.Error("expected an error for a 5 wide board")
+	}
+	if len(pool.Envs) != 3 {
+		t.Fatalf("expected the 3 old environments to stay, got %d", len(pool.Envs))
+	}
+
+	// Walk straight into the wall: every episode ends and restarts
+	actions := []EnvAction{{Direction: 0}, {Direction: 0}, {Direction: 0}}
+	finished := make([]bool, 3)
+	for i := 0; i < 100; i++ {
+		steps, err := pool.Step(actions)
+		if err != nil {
+			t.Fatalf("step failed: %v", err)
+		}
+		for j, s := range steps {
+			if s.Done {
+				finished[j] = true
+				if s.Reward >= 0 {
+					t.Errorf("env %d: expected a death penalty, got %.1f", j, s.Reward)
+				}
+			}
+		}
+	}
+	for j, ok := range finished {
+		if !ok {
+			t.Errorf("env %d never finished an episode", j)
+		}
+		if pool.Envs[j].Game.GameOver {
+			t.Errorf("env %d should have been reset", j)
+		}
+	}
+
+	// A reset that fails between episodes is reported, not dropped
+	pool.cfg.Opponent = "nope"
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		_, err = pool.Step(actions)
+	}
+	if err == nil {
+		t.Error("expected the failed auto-reset to be reported")
+	}
+}
@@ -0,0 +1,153 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxFFAOpponents caps the number of AI snakes in a solo free-for-all
+const MaxFFAOpponents = 5
+
+// aiSpawn returns the spawn point and heading of the n-th AI (0-based).
+// The first AI keeps the classic bottom-right corner.
+func (g *Game) aiSpawn(n int) (Point, Point) {
+	left, right := Point{X: -1, Y: 0}, Point{X: 1, Y: 0}
+	spawns := []struct{ pos, dir Point }{
+		{Point{X: g.Width - 2, Y: g.Height - 2}, left},
+		{Point{X: 1, Y: 1}, right},
+		{Point{X: g.Width - 2, Y: 1}, left},
+		{Point{X: 1, Y: g.Height - 2}, right},
+		{Point{X: g.Width / 2, Y: 1}, Point{X: 0, Y: 1}},
+	}
+	s := spawns[n%len(spawns)]
+	return s.pos, s.dir
+}
+
+// SetupFFA replaces the AI competitors with n opponents (clamped to 1..MaxFFAOpponents).
+// Controllers alternate between the default brain and the heuristic so the field is mixed.
+func (g *Game) SetupFFA(n int) {
+	if g.IsPVP || len(g.Players) == 0 {
+		return
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n > MaxFFAOpponents {
+		n = MaxFFAOpponents
+	}
+
+	for _, p := range g.Players[1:] {
+		ReleaseController(p.Brain)
+	}
+	g.Players = g.Players[:1]
+	for i := 0; i < n; i++ {
+		pos, dir := g.aiSpawn(i)
+		brain, controller := g.defaultAIBrain()
+		if i%2 == 1 {
+			brain, controller = &HeuristicController{}, "heuristic"
+		}
+		name := "AI"
+		if n > 1 {
+			name = fmt.Sprintf("AI %d", i+1)
+		}
+		g.Players = append(g.Players, &Player{
+			Snake:       []Point{pos},
+			Direction:   dir,
+			LastMoveDir: dir,
+			Name:        name,
+			Brain:       brain,
+			Controller:  controller,
+			Spawn:       pos,
+			SpawnDir:    dir,
+		})
+	}
+}
+
+// Opponents returns how many snakes the first player is competing against
+func (g *Game) Opponents() int {
+	if len(g.Players) == 0 {
+		return 0
+	}
+	return len(g.Players) - 1
+}
+
+// respawnAI puts a crashed AI competitor back on its spawn point, or on a
+// random free tile if another snake is sitting there
+func (g *Game) respawnAI(p *Player) {
+	spawn, dir := p.Spawn, p.SpawnDir
+	if spawn == (Point{}) {
+		spawn, dir = g.aiSpawn(0)
+	}
+	p.Snake = nil
+	if !g.isCellEmpty(spawn) {
+		for attempts := 0; attempts < 100; attempts++ {
+			pos := Point{X: g.rng().Intn(g.Width-2) + 1, Y: g.rng().Intn(g.Height-2) + 1}
+			if g.isCellEmpty(pos) {
+				spawn = pos
+				break
+			}
+		}
+	}
+	p.Snake = []Point{spawn}
+	p.Direction = dir
+	p.LastMoveDir = dir
+}
+
+// Placements ranks every player by score: 1 is first, tied scores share a place
+func (g *Game) Placements() []int {
+	order := make([]int, len(g.Players))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return g.Players[order[a]].Score > g.Players[order[b]].Score
+	})
+
+	places := make([]int, len(g.Players))
+	for rank, idx := range order {
+		if rank > 0 && g.Players[idx].Score == g.Players[order[rank-1]].Score {
+			places[idx] = places[order[rank-1]]
+		} else {
+			places[idx] = rank + 1
+		}
+	}
+	return places
+}
+
+// Result returns "won", "lost" or "draw" for player idx of a finished game.
+// One on one games follow Winner; larger ones rank by score, and sharing
+// first place is a draw.
+func (g *Game) Result(idx int) string {
+	if g.Winner == "" || g.Winner == "none" || idx >= len(g.Players) {
+		return "lost"
+	}
+	if len(g.Players) <= 2 && g.Winner != "draw" {
+		if g.wonBy(idx) {
+			return "won"
+		}
+		return "lost"
+	}
+	places := g.Placements()
+	if places[idx] != 1 {
+		return "lost"
+	}
+	for i, place := range places {
+		if i != idx && place == 1 {
+			return "draw"
+		}
+	}
+	return "won"
+}
+
+// Forfeit ends a running one on one game as lost by player idx, who left it
+func (g *Game) Forfeit(idx int) {
+	if g.GameOver || len(g.Players) != 2 {
+		return
+	}
+	g.GameOver = true
+	g.EndTime = g.Now()
+	g.Winner = "player"
+	if idx == 0 {
+		g.Winner = "ai"
+	}
+}
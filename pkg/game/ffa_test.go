@@ -0,0 +1,73 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// TestSetupFFASpawnsOpponents checks opponent count clamping and distinct spawn points
+func TestSetupFFASpawnsOpponents(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.SetupFFA(9)
+
+	if g.Opponents() != MaxFFAOpponents {
+		t.Fatalf("expected %d opponents, got %d", MaxFFAOpponents, g.Opponents())
+	}
+	seen := map[Point]bool{g.Players[0].Snake[0]: true}
+	for _, p := range g.Players[1:] {
+		if seen[p.Snake[0]] {
+			t.Errorf("%s spawned on an occupied tile %v", p.Name, p.Snake[0])
+		}
+		seen[p.Snake[0]] = true
+		if p.Spawn != p.Snake[0] {
+			t.Errorf("%s should remember its spawn point", p.Name)
+		}
+	}
+
+	g.SetupFFA(0)
+	if g.Opponents() != 1 {
+		t.Errorf("expected at least one opponent, got %d", g.Opponents())
+	}
+}
+
+// TestFFAWinnerAndPlacements checks the time limit ranks P1 against every AI
+func TestFFAWinnerAndPlacements(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.SetupFFA(3)
+	g.Players[0].Score = 50
+	g.Players[1].Score = 20
+	g.Players[2].Score = 80
+	g.Players[3].Score = 50
+	g.StartTime = time.Now().Add(-config.GameDuration - time.Second)
+
+	g.CheckTimeLimit()
+	if g.Winner != "ai" {
+		t.Errorf("expected AI win when any AI outscores P1, got %s", g.Winner)
+	}
+
+	places := g.Placements()
+	want := []int{2, 4, 1, 2}
+	for i := range want {
+		if places[i] != want[i] {
+			t.Errorf("player %d: expected place %d, got %d", i, want[i], places[i])
+		}
+	}
+	if got := g.GetGameStateSnapshot(true, false, "mid"); got.Placement != 2 || len(got.Opponents) != 2 {
+		t.Errorf("expected placement 2 and 2 extra opponents, got %d and %d", got.Placement, len(got.Opponents))
+	}
+}
+
+// TestFireballCreditsShooterIndex checks hits are credited to the right AI in free-for-all
+func TestFireballCreditsShooterIndex(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.SetupFFA(3)
+	g.Obstacles = []Obstacle{{Points: []Point{{X: 6, Y: 5}}, SpawnTime: time.Now(), Duration: 30}}
+	g.Fireballs = []*Fireball{{Pos: Point{X: 5, Y: 5}, Dir: Point{X: 1, Y: 0}, Owner: "ai", OwnerIdx: 3}}
+
+	g.UpdateFireballs()
+	if g.Players[3].Score != 10 || g.Players[1].Score != 0 {
+		t.Errorf("expected AI 3 to be credited, got scores %d (AI 1) and %d (AI 3)", g.Players[1].Score, g.Players[3].Score)
+	}
+}
@@ -79,15 +79,25 @@ func (f *Food) GetDuration() time.Duration {
 
 // IsExpired checks if the food has expired, accounting for paused time that occurred AFTER spawn
 func (f *Food) IsExpired(currentTotalPaused time.Duration) bool {
-	pausedSinceSpawn := currentTotalPaused - f.PausedTimeAtSpawn
-	elapsed := time.Since(f.SpawnTime) - pausedSinceSpawn
-	return elapsed > f.GetDuration()
+	return f.isExpiredAt(time.Now(), currentTotalPaused)
 }
 
 // GetRemainingSeconds returns remaining seconds before expiration, accounting for paused time AFTER spawn
 func (f *Food) GetRemainingSeconds(currentTotalPaused time.Duration) int {
+	return f.remainingSecondsAt(time.Now(), currentTotalPaused)
+}
+
+// isExpiredAt is IsExpired measured against the given clock reading (see Game.Now)
+func (f *Food) isExpiredAt(now time.Time, currentTotalPaused time.Duration) bool {
+	pausedSinceSpawn := currentTotalPaused - f.PausedTimeAtSpawn
+	elapsed := now.Sub(f.SpawnTime) - pausedSinceSpawn
+	return elapsed > f.GetDuration()
+}
+
+// remainingSecondsAt is GetRemainingSeconds measured against the given clock reading
+func (f *Food) remainingSecondsAt(now time.Time, currentTotalPaused time.Duration) int {
 	pausedSinceSpawn := currentTotalPaused - f.PausedTimeAtSpawn
-	elapsed := time.Since(f.SpawnTime) - pausedSinceSpawn
+	elapsed := now.Sub(f.SpawnTime) - pausedSinceSpawn
 	remaining := f.GetDuration() - elapsed
 	if remaining < 0 {
 		return 0
@@ -11,7 +11,13 @@ import (
 
 // NewGame creates a new game instance with specified dimensions
 func NewGame(width, height int) *Game {
+	return newGame(width, height, nil)
+}
+
+// newGame builds a game using rng for randomness (nil = shared generator)
+func newGame(width, height int, rng *rand.Rand) *Game {
 	g := &Game{
+		Rand:   rng,
 		Width:  width,
 		Height: height,
 		Players: []*Player{
@@ -45,11 +51,11 @@ func NewGame(width, height int) *Game {
 	})
 
 	// Start Global AI Inference Service if not already started
-	onnxPath := "ml/checkpoints/snake_policy.onnx"
-	err := StartInferenceService(onnxPath)
+	err := StartInferenceService(DefaultModelPath)
 	if err == nil {
-		g.NeuralNet = &ONNXModel{}
-		log.Println("🧠 Global AI Service (ONNX) is active for this game!")
+		g.NeuralNet = Models
+		g.Model = Models.Assign(g)
+		log.Println("🧠 Global AI Service is active for this game!")
 
 		// If board size is standard, upgrade AI to Neural by default
 		if g.Width == config.StandardWidth && g.Height == config.StandardHeight && len(g.Players) > 1 {
@@ -65,6 +71,32 @@ func NewGame(width, height int) *Game {
 	return g
 }
 
+// SetupPVP turns the game into a two-player duel. The snakes start at
+// different heights so they cannot crash head-on straight away.
+func (g *Game) SetupPVP(name1, name2 string) {
+	g.Mode = "pvp"
+	g.IsPVP = true
+	g.ReleaseControllers()
+	g.Players = []*Player{
+		{
+			Snake:       []Point{{X: g.Width / 4, Y: g.Height / 3}},
+			Direction:   Point{X: 1, Y: 0},
+			LastMoveDir: Point{X: 1, Y: 0},
+			Name:        name1,
+			Brain:       &ManualController{},
+			Controller:  "manual",
+		},
+		{
+			Snake:       []Point{{X: (g.Width * 3) / 4, Y: (g.Height * 2) / 3}},
+			Direction:   Point{X: -1, Y: 0},
+			LastMoveDir: Point{X: -1, Y: 0},
+			Name:        name2,
+			Brain:       &ManualController{},
+			Controller:  "manual",
+		},
+	}
+}
+
 // spawnOneFood generates one food of random type
 func (g *Game) spawnOneFood() {
 	if len(g.Foods) >= config.MaxFoodsOnBoard {
@@ -72,7 +104,7 @@ func (g *Game) spawnOneFood() {
 	}
 
 	// Randomly select food type with weighted probability
-	randNum := rand.Intn(100)
+	randNum := g.rng().Intn(100)
 	var foodType FoodType
 	switch {
 	case randNum < 15: // 15% red (high score)
@@ -88,8 +120,8 @@ func (g *Game) spawnOneFood() {
 	// Find position that doesn't overlap with snakes, foods or obstacles
 	for attempts := 0; attempts < 100; attempts++ {
 		pos := Point{
-			X: rand.Intn(g.Width-2) + 1,
-			Y: rand.Intn(g.Height-2) + 1,
+			X: g.rng().Intn(g.Width-2) + 1,
+			Y: g.rng().Intn(g.Height-2) + 1,
 		}
 
 		if !g.isCellEmpty(pos) {
@@ -100,10 +132,10 @@ func (g *Game) spawnOneFood() {
 		g.Foods = append(g.Foods, Food{
 			Pos:               pos,
 			FoodType:          foodType,
-			SpawnTime:         time.Now(),
+			SpawnTime:         g.Now(),
 			PausedTimeAtSpawn: g.GetTotalPausedTime(),
 		})
-		g.LastFoodSpawn = time.Now()
+		g.LastFoodSpawn = g.Now()
 		return
 	}
 }
@@ -113,7 +145,7 @@ func (g *Game) removeExpiredFoods() {
 	newFoods := make([]Food, 0)
 	totalPaused := g.GetTotalPausedTime()
 	for _, food := range g.Foods {
-		if !food.IsExpired(totalPaused) {
+		if !food.isExpiredAt(g.Now(), totalPaused) {
 			newFoods = append(newFoods, food)
 		}
 	}
@@ -133,7 +165,7 @@ func (g *Game) TrySpawnFood() {
 		return
 	}
 
-	if time.Since(g.LastFoodSpawn) > config.FoodSpawnInterval && len(g.Foods) < config.MaxFoodsOnBoard {
+	if g.Now().Sub(g.LastFoodSpawn) > config.FoodSpawnInterval && len(g.Foods) < config.MaxFoodsOnBoard {
 		g.spawnOneFood()
 	}
 }
@@ -157,11 +189,12 @@ func (g *Game) Update() {
 	g.TrySpawnProp()
 	g.TrySpawnObstacle()
 	g.CheckTimeLimit()
+	g.UpdateAdaptiveDifficulty()
 	g.updateActiveEffects()
 }
 
 func (g *Game) updateActiveEffects() {
-	now := time.Now()
+	now := g.Now()
 	for _, p := range g.Players {
 		var active []*ActiveEffect
 		for _, e := range p.Effects {
@@ -177,7 +210,7 @@ func (g *Game) updateActiveEffects() {
 	totalPaused := g.GetTotalPausedTime()
 	var remainingProps []Prop
 	for _, pr := range g.Props {
-		if !pr.IsExpired(totalPaused) {
+		if !pr.isExpiredAt(g.Now(), totalPaused) {
 			remainingProps = append(remainingProps, pr)
 		}
 	}
@@ -191,7 +224,7 @@ func (g *Game) UpdatePlayer(idx int) {
 	}
 
 	p := g.Players[idx]
-	p.Stunned = time.Now().Before(p.StunnedUntil)
+	p.Stunned = g.Now().Before(p.StunnedUntil)
 	if p.Stunned {
 		return
 	}
@@ -212,6 +245,7 @@ func (g *Game) UpdatePlayer(idx int) {
 		}
 	}
 	p.LastMoveDir = p.Direction
+	p.Moves++
 
 	// 2. Calculate next head
 	nextHead := Point{X: p.Snake[0].X + p.Direction.X, Y: p.Snake[0].Y + p.Direction.Y}
@@ -232,10 +266,11 @@ func (g *Game) UpdatePlayer(idx int) {
 		}
 
 		if !hasShield {
-			if g.IsPVP || idx == 0 {
+			if (g.IsPVP || idx == 0) && !g.Respawn {
 				// Player or PVP participant died
+				p.Deaths++
 				g.GameOver = true
-				g.EndTime = time.Now()
+				g.EndTime = g.Now()
 				g.CrashPoint = nextHead
 				if g.IsPVP {
 					if idx == 0 {
@@ -244,11 +279,15 @@ func (g *Game) UpdatePlayer(idx int) {
 						g.Winner = "player"
 					}
 				}
+			} else if g.Respawn {
+				// Room battles keep going until the clock runs out
+				p.Deaths++
+				g.respawnAI(p)
+				g.SetMessage(fmt.Sprintf("💥 %s 撞墙了！重新出发", p.Name))
 			} else {
 				// AI competitor died, reset it
-				p.Snake = []Point{{X: g.Width - 2, Y: g.Height - 2}}
-				p.Direction = Point{X: -1, Y: 0}
-				p.LastMoveDir = Point{X: -1, Y: 0}
+				p.Deaths++
+				g.respawnAI(p)
 				g.SetMessage("🤖 AI 竞争者撞墙了！")
 			}
 			return
@@ -299,6 +338,7 @@ func (g *Game) handlePropCollision(pos Point, p *Player) {
 	for _, pr := range g.Props {
 		if pr.Pos == pos {
 			// Collected!
+			p.PropsCollected++
 			if pr.Type == PropTrimmer {
 				// Instant effect: shorten snake
 				if len(p.Snake) > 5 {
@@ -331,7 +371,7 @@ func (g *Game) handlePropCollision(pos Point, p *Player) {
 					found := false
 					for _, e := range p.Effects {
 						if e.Type == effectType {
-							e.ExpireAt = time.Now().Add(duration)
+							e.ExpireAt = g.Now().Add(duration)
 							found = true
 							break
 						}
@@ -339,7 +379,7 @@ func (g *Game) handlePropCollision(pos Point, p *Player) {
 					if !found {
 						p.Effects = append(p.Effects, &ActiveEffect{
 							Type:     effectType,
-							ExpireAt: time.Now().Add(duration),
+							ExpireAt: g.Now().Add(duration),
 						})
 					}
 					g.SetMessageWithType(fmt.Sprintf("%s 拾取道具: %s!", pr.GetEmoji(), effectType), "bonus")
@@ -442,15 +482,22 @@ func (g *Game) CheckTimeLimit() {
 
 	remaining := g.GetTimeRemaining()
 	if remaining <= 0 {
-		log.Printf("[Game] Time Limit Reached (IsPVP: %v)", g.IsPVP)
-		log.Printf("[Game] TIME LIMIT EXPIRED! Duration: %v, Elapsed: %v, Remaining: %d", config.GameDuration, time.Since(g.StartTime), remaining)
+		if !g.Headless {
+			log.Printf("[Game] Time Limit Reached (IsPVP: %v)", g.IsPVP)
+			log.Printf("[Game] TIME LIMIT EXPIRED! Duration: %v, Elapsed: %v, Remaining: %d", g.GameDuration(), g.Now().Sub(g.StartTime), remaining)
+		}
 		g.GameOver = true
-		g.EndTime = time.Now()
+		g.EndTime = g.Now()
 
-		// Determine winner
+		// Determine winner: P1 against the best of the rest (one rival outside free-for-all)
 		if len(g.Players) >= 2 {
 			s1 := g.Players[0].Score
 			s2 := g.Players[1].Score
+			for _, p := range g.Players[2:] {
+				if p.Score > s2 {
+					s2 = p.Score
+				}
+			}
 			switch {
 			case s1 > s2:
 				g.Winner = "player"
@@ -465,17 +512,25 @@ func (g *Game) CheckTimeLimit() {
 	}
 }
 
+// GameDuration returns how long a timed game lasts
+func (g *Game) GameDuration() time.Duration {
+	if g.Duration > 0 {
+		return g.Duration
+	}
+	return config.GameDuration
+}
+
 // GetTimeRemaining returns the remaining game time in seconds
 func (g *Game) GetTimeRemaining() int {
 	if !g.TimerStarted {
-		return int(config.GameDuration.Seconds())
+		return int(g.GameDuration().Seconds())
 	}
-	endTime := time.Now()
+	endTime := g.Now()
 	if g.GameOver {
 		endTime = g.EndTime
 	}
 	elapsed := endTime.Sub(g.StartTime) - g.GetTotalPausedTime()
-	remaining := config.GameDuration - elapsed
+	remaining := g.GameDuration() - elapsed
 	if remaining < 0 {
 		return 0
 	}
@@ -539,9 +594,9 @@ func (g *Game) TogglePause() {
 		return
 	}
 	if !g.Paused {
-		g.PauseStart = time.Now()
+		g.PauseStart = g.Now()
 	} else {
-		g.PausedTime += time.Since(g.PauseStart)
+		g.PausedTime += g.Now().Sub(g.PauseStart)
 	}
 	g.Paused = !g.Paused
 }
@@ -552,6 +607,7 @@ func (g *Game) TogglePlayerAutoPlay(idx int, requestedMode string) {
 		return
 	}
 	p := g.Players[idx]
+	defer ReleaseController(p.Brain) // Every branch below replaces it
 
 	// If it's currently manual or we want to change the agent type while running
 	isSwitchingModes := p.Controller != "manual" && requestedMode != "" && p.Controller != requestedMode
@@ -566,7 +622,12 @@ func (g *Game) TogglePlayerAutoPlay(idx int, requestedMode string) {
 			}
 		}
 
-		if modeToUse == "neural" && g.NeuralNet != nil {
+		if modeToUse == "mcts" {
+			p.Brain = NewMCTSController()
+			p.Controller = "mcts"
+			g.SetMessage(p.Name + ": 🌲 蒙特卡洛树搜索已注入")
+			log.Printf("[Game] Player %d (%s) switched to MCTS controller", idx, p.Name)
+		} else if modeToUse == "neural" && g.NeuralNet != nil {
 			if g.Width == config.StandardWidth && g.Height == config.StandardHeight {
 				p.Brain = &NeuralController{}
 				p.Controller = "neural"
@@ -599,6 +660,48 @@ func (g *Game) TogglePlayerAutoPlay(idx int, requestedMode string) {
 	}
 }
 
+// SetAIDifficulty configures the AI competitors of a solo game for a difficulty tier.
+// Each tier sets the skill profile; "expert" also hands them to the MCTS searcher,
+// any other tier restores the default brain.
+func (g *Game) SetAIDifficulty(difficulty string) {
+	if g.IsPVP {
+		return
+	}
+	g.SpeedTier = difficulty // The solo player moves at the tier's speed
+	if difficulty != "adaptive" {
+		g.Adaptive = nil
+	}
+	for i, p := range g.Players {
+		if i == 0 || p.Controller == "manual" {
+			continue
+		}
+		if difficulty == "adaptive" {
+			if g.Adaptive == nil {
+				g.Adaptive = NewAdaptiveDifficulty(DefaultSkillLevel)
+			}
+			p.Profile = g.Adaptive.Profile()
+		} else {
+			p.Profile = AIProfileForDifficulty(difficulty)
+		}
+		// Only the first AI searches: several MCTS brains would not fit in one server tick
+		if difficulty == "expert" && i == 1 {
+			ReleaseController(p.Brain)
+			p.Brain = NewMCTSController()
+			p.Controller = "mcts"
+		} else if p.Controller == "mcts" {
+			p.Brain, p.Controller = g.defaultAIBrain()
+		}
+	}
+}
+
+// defaultAIBrain returns the strongest standard controller available for this board
+func (g *Game) defaultAIBrain() (Controller, string) {
+	if g.NeuralNet != nil && g.Width == config.StandardWidth && g.Height == config.StandardHeight {
+		return &NeuralController{}, "neural"
+	}
+	return &HeuristicController{}, "heuristic"
+}
+
 // ToggleBerserkerMode toggles the aggressive AI mode
 func (g *Game) ToggleBerserkerMode() {
 	g.BerserkerMode = !g.BerserkerMode
@@ -629,7 +732,7 @@ func (g *Game) GetMoveIntervalExt(difficulty string, boosted bool) time.Duration
 	case "mid":
 		ticks = config.MidTicks
 		boostTicks = config.MidBoostTicks
-	case "high":
+	case "high", "expert":
 		ticks = config.HighTicks
 		boostTicks = config.HighBoostTicks
 	}
@@ -640,13 +743,18 @@ func (g *Game) GetMoveIntervalExt(difficulty string, boosted bool) time.Duration
 	return time.Duration(ticks) * config.BaseTick
 }
 
-// GetAIMoveInterval (AI defaults to mid speed unless boosting)
+// GetAIMoveInterval (AI moves at its profile's speed tier, mid by default)
 func (g *Game) GetAIMoveInterval() time.Duration {
+	return g.GetAIMoveIntervalFor(1)
+}
+
+// GetAIMoveIntervalFor returns the move interval of any AI competitor
+func (g *Game) GetAIMoveIntervalFor(idx int) time.Duration {
 	boosted := false
-	if len(g.Players) > 1 {
-		boosted = g.Players[1].Boosting
+	if idx < len(g.Players) {
+		boosted = g.Players[idx].Boosting
 	}
-	return g.GetMoveIntervalExt("mid", boosted)
+	return g.GetMoveIntervalExt(g.aiProfile(idx).SpeedTier, boosted)
 }
 
 // SetDirection sets the direction for Player 1
@@ -685,7 +793,7 @@ func (g *Game) GetEatingSpeed() float64 {
 	if len(g.Players) == 0 {
 		return 0
 	}
-	endTime := time.Now()
+	endTime := g.Now()
 	if g.GameOver {
 		endTime = g.EndTime
 	}
@@ -700,7 +808,7 @@ func (g *Game) GetEatingSpeed() float64 {
 func (g *Game) GetTotalPausedTime() time.Duration {
 	totalPaused := g.PausedTime
 	if g.Paused && !g.PauseStart.IsZero() {
-		endTime := time.Now()
+		endTime := g.Now()
 		if g.GameOver {
 			endTime = g.EndTime
 		}
@@ -716,6 +824,9 @@ func (g *Game) SetMessage(message string) {
 
 // SetMessageWithType sets a message with specific type
 func (g *Game) SetMessageWithType(message string, msgType string) {
+	if g.Headless {
+		return
+	}
 	g.Message = message
 	g.MessageType = msgType
 }
@@ -734,13 +845,13 @@ func (g *Game) TrySpawnObstacle() {
 	totalPaused := g.GetTotalPausedTime()
 	for _, obs := range g.Obstacles {
 		pausedSinceSpawn := totalPaused - obs.PausedTimeAtSpawn
-		elapsed := time.Since(obs.SpawnTime) - pausedSinceSpawn
+		elapsed := g.Now().Sub(obs.SpawnTime) - pausedSinceSpawn
 		if elapsed.Seconds() <= obs.Duration && len(obs.Points) > 0 {
 			newObs = append(newObs, obs)
 		}
 	}
 	g.Obstacles = newObs
-	if len(g.Obstacles) < config.MaxObstacles && time.Since(g.LastObstacleSpawn) > config.ObstacleSpawnInterval {
+	if len(g.Obstacles) < config.MaxObstacles && g.Now().Sub(g.LastObstacleSpawn) > config.ObstacleSpawnInterval {
 		g.spawnOneObstacle()
 	}
 }
@@ -749,7 +860,7 @@ func (g *Game) spawnOneObstacle() {
 	var start Point
 	found := false
 	for attempts := 0; attempts < 50; attempts++ {
-		p := Point{X: rand.Intn(g.Width-4) + 2, Y: rand.Intn(g.Height-4) + 2}
+		p := Point{X: g.rng().Intn(g.Width-4) + 2, Y: g.rng().Intn(g.Height-4) + 2}
 		if g.isCellEmpty(p) {
 			start = p
 			found = true
@@ -761,11 +872,11 @@ func (g *Game) spawnOneObstacle() {
 	}
 
 	points := []Point{start}
-	numPoints := rand.Intn(5) + 1
+	numPoints := g.rng().Intn(5) + 1
 	dirs := []Point{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
 	for i := 1; i < numPoints; i++ {
-		base := points[rand.Intn(len(points))]
-		rand.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
+		base := points[g.rng().Intn(len(points))]
+		g.rng().Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
 		for _, d := range dirs {
 			next := Point{base.X + d.X, base.Y + d.Y}
 			if next.X > 1 && next.X < g.Width-2 && next.Y > 1 && next.Y < g.Height-2 && g.isCellEmpty(next) {
@@ -784,9 +895,9 @@ func (g *Game) spawnOneObstacle() {
 		}
 	}
 	g.Obstacles = append(g.Obstacles, Obstacle{
-		Points: points, SpawnTime: time.Now(), Duration: config.ObstacleDuration.Seconds(), PausedTimeAtSpawn: g.GetTotalPausedTime(),
+		Points: points, SpawnTime: g.Now(), Duration: config.ObstacleDuration.Seconds(), PausedTimeAtSpawn: g.GetTotalPausedTime(),
 	})
-	g.LastObstacleSpawn = time.Now()
+	g.LastObstacleSpawn = g.Now()
 }
 
 func (g *Game) isCellEmpty(p Point) bool {
@@ -837,7 +948,7 @@ func (g *Game) FireByTypeIdx(idx int) {
 		}
 	}
 
-	if time.Since(p.LastFireTime) < cooldown {
+	if g.Now().Sub(p.LastFireTime) < cooldown {
 		return
 	}
 
@@ -849,8 +960,9 @@ func (g *Game) FireByTypeIdx(idx int) {
 	fb := &Fireball{
 		Pos:       p.Snake[0],
 		Dir:       p.Direction,
-		SpawnTime: time.Now(),
+		SpawnTime: g.Now(),
 		Owner:     owner,
+		OwnerIdx:  idx,
 	}
 	g.Fireballs = append(g.Fireballs, fb)
 
@@ -876,19 +988,21 @@ func (g *Game) FireByTypeIdx(idx int) {
 			g.Fireballs = append(g.Fireballs, &Fireball{
 				Pos:       p.Snake[0],
 				Dir:       d1,
-				SpawnTime: time.Now(),
+				SpawnTime: g.Now(),
 				Owner:     owner,
+				OwnerIdx:  idx,
 			}, &Fireball{
 				Pos:       p.Snake[0],
 				Dir:       d2,
-				SpawnTime: time.Now(),
+				SpawnTime: g.Now(),
 				Owner:     owner,
+				OwnerIdx:  idx,
 			})
 			break
 		}
 	}
 
-	p.LastFireTime = time.Now()
+	p.LastFireTime = g.Now()
 }
 
 // UpdateFireballs
@@ -901,10 +1015,7 @@ func (g *Game) UpdateFireballs() {
 	for _, fb := range g.Fireballs {
 		hit := false
 		steps := 1
-		ownerIdx := 0
-		if fb.Owner == "ai" {
-			ownerIdx = 1
-		}
+		ownerIdx := fb.ownerIndex()
 		if ownerIdx < len(g.Players) {
 			for _, e := range g.Players[ownerIdx].Effects {
 				if e.Type == EffectRapidFire {
@@ -930,11 +1041,7 @@ func (g *Game) UpdateFireballs() {
 					for i, p := range player.Snake {
 						if p == fb.Pos {
 							// Don't hit own head when firing
-							selfIdx := 0
-							if fb.Owner == "ai" {
-								selfIdx = 1
-							}
-							if pIdx == selfIdx && i == 0 {
+							if pIdx == ownerIdx && i == 0 {
 								continue
 							}
 
@@ -943,18 +1050,23 @@ func (g *Game) UpdateFireballs() {
 
 							// Hit logic
 							targetPlayer := player
-							attackerIdx := 0
-							if fb.Owner == "ai" {
-								attackerIdx = 1
-							}
+							attackerIdx := ownerIdx
 
 							var attackerScore int
 							var label string
 
+							if pIdx != attackerIdx {
+								targetPlayer.HitsTaken++
+								if attackerIdx < len(g.Players) {
+									g.Players[attackerIdx].HitsLanded++
+								}
+							}
+
 							if i == 0 {
 								attackerScore = 50
 								label = "🎯 HEADSHOT +50"
-								targetPlayer.StunnedUntil = time.Now().Add(2 * time.Second)
+								targetPlayer.StunnedUntil = g.Now().Add(2 * time.Second)
+								targetPlayer.TimesStunned++
 								if pIdx == 0 {
 									g.SetMessageWithType("😱 警告！头部被击中，麻痹2秒！", "important")
 								}
@@ -995,12 +1107,8 @@ func (g *Game) UpdateFireballs() {
 							hit = true
 							g.HitPoints = append(g.HitPoints, fb.Pos)
 
-							curOwnerIdx := 0
-							if fb.Owner == "ai" {
-								curOwnerIdx = 1
-							}
-							if curOwnerIdx < len(g.Players) {
-								g.Players[curOwnerIdx].Score += 10
+							if ownerIdx < len(g.Players) {
+								g.Players[ownerIdx].Score += 10
 							}
 
 							g.ScoreEvents = append(g.ScoreEvents, ScoreEvent{
@@ -1037,7 +1145,7 @@ func (g *Game) GetGameStateSnapshot(started bool, serverBoosting bool, difficult
 		foods[i] = FoodInfo{
 			Pos:              f.Pos,
 			FoodType:         int(f.FoodType),
-			RemainingSeconds: f.GetRemainingSeconds(totalPaused),
+			RemainingSeconds: f.remainingSecondsAt(g.Now(), totalPaused),
 		}
 	}
 
@@ -1067,6 +1175,29 @@ func (g *Game) GetGameStateSnapshot(started bool, serverBoosting bool, difficult
 	}
 	if len(g.Players) > 1 {
 		state.P2Effects = g.Players[1].Effects
+		if !g.IsPVP {
+			state.AIPersonality = g.aiPersonality(1).Name
+		}
+	}
+	for _, p := range g.Players[min(2, len(g.Players)):] {
+		state.Opponents = append(state.Opponents, OpponentInfo{
+			Name:       p.Name,
+			Snake:      p.Snake,
+			Score:      p.Score,
+			Stunned:    p.Stunned,
+			Controller: p.Controller,
+		})
+	}
+	if g.GameOver && len(g.Players) > 1 {
+		state.Placement = g.Placements()[0]
+	}
+	state.AdaptiveLevel = g.AdaptiveLevel()
+	if g.ShowAIDebug {
+		state.AIDebug = g.AIDecisions()
+	}
+	if g.Series != nil {
+		score := g.Series.Score()
+		state.Series = &score
 	}
 
 	// Populate P1 fields
@@ -1076,7 +1207,7 @@ func (g *Game) GetGameStateSnapshot(started bool, serverBoosting bool, difficult
 		state.Score = p1.Score
 		state.FoodEaten = p1.FoodEaten
 		state.Boosting = p1.Boosting || serverBoosting
-		state.PlayerStunned = time.Now().Before(p1.StunnedUntil)
+		state.PlayerStunned = g.Now().Before(p1.StunnedUntil)
 		state.P1Name = p1.Name
 	}
 
@@ -1085,7 +1216,7 @@ func (g *Game) GetGameStateSnapshot(started bool, serverBoosting bool, difficult
 		p2 := g.Players[1]
 		state.AISnake = p2.Snake
 		state.AIScore = p2.Score
-		state.AIStunned = time.Now().Before(p2.StunnedUntil)
+		state.AIStunned = g.Now().Before(p2.StunnedUntil)
 		state.P2Name = p2.Name
 	}
 
@@ -1096,12 +1227,25 @@ func (g *Game) GetGameStateSnapshot(started bool, serverBoosting bool, difficult
 	return state
 }
 
+// AIDecisions returns the latest decision of every player the engine's AI
+// drives. MCTS does not explain its moves, so it is skipped too.
+func (g *Game) AIDecisions() []AIDecision {
+	var out []AIDecision
+	for i, p := range g.Players {
+		if !p.hasAIContext() || p.LastContext.Intent == "" {
+			continue
+		}
+		out = append(out, AIDecision{Player: i, Controller: p.Controller, AIContext: p.LastContext})
+	}
+	return out
+}
+
 // GetGameConfig returns the current game configuration
 func (g *Game) GetGameConfig() GameConfig {
 	return GameConfig{
 		Width:            g.Width,
 		Height:           g.Height,
-		GameDuration:     int(config.GameDuration.Seconds()),
+		GameDuration:     int(g.GameDuration().Seconds()),
 		FireballCooldown: int(config.FireballCooldown.Milliseconds()),
 	}
 }
@@ -157,3 +157,28 @@ func TestDirectionValidation(t *testing.T) {
 
 	t.Log("✅ Direction validation test passed!")
 }
+
+// TestPropsStayOnBoard spawns props on boards smaller and larger than the
+// standard one and checks they land inside the walls and use the whole board
+func TestPropsStayOnBoard(t *testing.T) {
+	for _, size := range []int{config.MinBoardSize, config.LargeWidth} {
+		g := NewSeededGame(size, size, 1)
+		far := false
+		for i := 0; i < 500; i++ {
+			g.Props = nil
+			g.LastPropSpawn = g.Now().Add(-time.Hour)
+			g.TrySpawnProp()
+			for _, p := range g.Props {
+				if p.Pos.X < 1 || p.Pos.Y < 1 || p.Pos.X > size-2 || p.Pos.Y > size-2 {
+					t.Fatalf("prop at %v is outside the %dx%d board", p.Pos, size, size)
+				}
+				if p.Pos.X > 23 || p.Pos.Y > 23 {
+					far = true
+				}
+			}
+		}
+		if size > config.StandardWidth && !far {
+			t.Errorf("expected props beyond the standard board on %dx%d", size, size)
+		}
+	}
+}
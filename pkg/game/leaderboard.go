@@ -19,8 +19,9 @@ func NewLeaderboardManager() *LeaderboardManager {
 	return &LeaderboardManager{}
 }
 
-func (lm *LeaderboardManager) AddEntry(name string, score int, difficulty string, mode string) bool {
-	log.Printf("📊 Attempting to add leaderboard entry: Player=%s, Score=%d, Mode=%s\n", name, score, mode)
+// AddEntry records a score; opponents is the number of AI snakes faced (more than 1 in free-for-all)
+func (lm *LeaderboardManager) AddEntry(name string, score int, difficulty string, mode string, opponents int) bool {
+	log.Printf("📊 Attempting to add leaderboard entry: Player=%s, Score=%d, Mode=%s, Opponents=%d\n", name, score, mode, opponents)
 
 	// Check if this score makes it to top 10
 	entries := lm.GetEntries()
@@ -30,8 +31,8 @@ func (lm *LeaderboardManager) AddEntry(name string, score int, difficulty string
 	}
 
 	_, err := DB.Exec(
-		"INSERT INTO leaderboard (name, score, difficulty, mode) VALUES (?, ?, ?, ?)",
-		name, score, difficulty, mode,
+		"INSERT INTO leaderboard (name, score, difficulty, mode, opponents) VALUES (?, ?, ?, ?, ?)",
+		name, score, difficulty, mode, opponents,
 	)
 	if err != nil {
 		log.Printf("❌ Error adding leaderboard entry to DB: %v\n", err)
@@ -58,7 +59,7 @@ func (lm *LeaderboardManager) AddEntry(name string, score int, difficulty string
 
 func (lm *LeaderboardManager) GetEntries() []LeaderboardEntry {
 	rows, err := DB.Query(
-		"SELECT name, score, date, difficulty, mode FROM leaderboard ORDER BY score DESC LIMIT ?",
+		"SELECT name, score, date, difficulty, mode, opponents FROM leaderboard ORDER BY score DESC LIMIT ?",
 		MaxLeaderboardEntries,
 	)
 	if err != nil {
@@ -70,7 +71,7 @@ func (lm *LeaderboardManager) GetEntries() []LeaderboardEntry {
 	var entries []LeaderboardEntry
 	for rows.Next() {
 		var e LeaderboardEntry
-		if err := rows.Scan(&e.Name, &e.Score, &e.Date, &e.Difficulty, &e.Mode); err != nil {
+		if err := rows.Scan(&e.Name, &e.Score, &e.Date, &e.Difficulty, &e.Mode, &e.Opponents); err != nil {
 			log.Printf("❌ Error scanning leaderboard row: %v\n", err)
 			continue
 		}
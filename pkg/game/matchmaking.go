@@ -0,0 +1,223 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Ratings and matchmaking
+const (
+	DefaultRating    = 1200.0
+	ratingK          = 24.0 // Elo K-factor of established players
+	provisionalK     = 48.0 // Larger steps while a new player's rating settles
+	provisionalGames = 10   // Rated games before a rating counts as established
+
+	botRatingLow  = 800.0  // Fill bots play like ProfileBeginner at this rating...
+	botRatingHigh = 1600.0 // ...and like ProfileHard at this one
+
+	MatchGapStart    = 100.0            // Rating gap accepted right away
+	MatchGapGrowth   = 25.0             // Added to the accepted gap per second of waiting
+	MatchGapMax      = 800.0            // Widest gap ever accepted
+	DefaultMatchWait = 15 * time.Second // Wait estimate before any match was made
+)
+
+// ExpectedScore is the Elo chance of a rating to beat another (a draw counts half)
+func ExpectedScore(rating, other float64) float64 {
+	return 1 / (1 + math.Pow(10, (other-rating)/400))
+}
+
+// RatingChange is how much a rating moves after a game against other, where
+// score is 1 for a win, 0.5 for a draw and 0 for a loss
+func RatingChange(rating, other, score float64, ratedGames int) float64 {
+	k := ratingK
+	if ratedGames < provisionalGames {
+		k = provisionalK
+	}
+	return k * (score - ExpectedScore(rating, other))
+}
+
+// ResultScore maps a Game.Result onto an Elo score
+func ResultScore(result string) float64 {
+	switch result {
+	case "won":
+		return 1
+	case "draw":
+		return 0.5
+	}
+	return 0
+}
+
+// RatingProfile tunes the AI to play about as well as a player of this rating
+func RatingProfile(rating float64) *AIProfile {
+	p := NewAdaptiveDifficulty((rating - botRatingLow) / (botRatingHigh - botRatingLow)).Profile()
+	p.Name = "rating"
+	return p
+}
+
+// SetupBotOpponent hands player idx of a PVP game to the AI, playing at the
+// level of the given rating. It moves at the match speed like a human would.
+func (g *Game) SetupBotOpponent(idx int, rating float64) {
+	p := g.Players[idx]
+	p.Brain, p.Controller = g.defaultAIBrain()
+	p.Profile = RatingProfile(rating)
+	p.Profile.SpeedTier = "mid"
+}
+
+// MatchGap is the rating gap a player accepts after waiting this long
+func MatchGap(waited time.Duration) float64 {
+	return min(MatchGapStart+MatchGapGrowth*waited.Seconds(), MatchGapMax)
+}
+
+// QueueEntry is a player waiting for a rated match
+type QueueEntry struct {
+	Name   string
+	Rating float64
+	Joined time.Time
+}
+
+// MatchQueue pairs waiting players by rating. Two players are matched once
+// their rating gap is within what the longer waiting one accepts, closest
+// ratings first. Not safe for concurrent use.
+type MatchQueue struct {
+	FillAfter time.Duration // Players waiting this long are due a bot (see Overdue); 0 for never
+
+	entries []QueueEntry  // Oldest first
+	avgWait time.Duration // Moving average of how long matched players waited
+}
+
+// Add queues a player. A player already waiting (from another session) is
+// queued again in their old place, so nobody is stuck behind a stale entry.
+func (q *MatchQueue) Add(e QueueEntry) {
+	if i := q.index(e.Name); i >= 0 {
+		e.Joined = q.entries[i].Joined
+		q.entries[i] = e
+		return
+	}
+	q.entries = append(q.entries, e)
+}
+
+// Remove takes a player out of the queue, reporting whether they were in it
+func (q *MatchQueue) Remove(name string) bool {
+	i := q.index(name)
+	if i < 0 {
+		return false
+	}
+	q.entries = slices.Delete(q.entries, i, i+1)
+	return true
+}
+
+func (q *MatchQueue) index(name string) int {
+	return slices.IndexFunc(q.entries, func(e QueueEntry) bool { return e.Name == name })
+}
+
+// Len is the number of waiting players
+func (q *MatchQueue) Len() int {
+	return len(q.entries)
+}
+
+// Entry returns a waiting player's entry
+func (q *MatchQueue) Entry(name string) (QueueEntry, bool) {
+	if i := q.index(name); i >= 0 {
+		return q.entries[i], true
+	}
+	return QueueEntry{}, false
+}
+
+// Match takes every pair that can play now out of the queue. The longest
+// waiting player of each pair comes first.
+func (q *MatchQueue) Match(now time.Time) [][2]QueueEntry {
+	var pairs [][2]QueueEntry
+	taken := make([]bool, len(q.entries))
+	for i, e := range q.entries {
+		if taken[i] {
+			continue
+		}
+		best, bestGap := -1, math.Inf(1)
+		for j := i + 1; j < len(q.entries); j++ {
+			o := q.entries[j]
+			gap := math.Abs(e.Rating - o.Rating)
+			if taken[j] || gap >= bestGap || gap > MatchGap(now.Sub(e.Joined)) {
+				continue // e waited longest, so it accepts the widest gap of the two
+			}
+			best, bestGap = j, gap
+		}
+		if best < 0 {
+			continue
+		}
+		taken[i], taken[best] = true, true
+		pairs = append(pairs, [2]QueueEntry{e, q.entries[best]})
+		q.recordWait(now.Sub(e.Joined))
+		q.recordWait(now.Sub(q.entries[best].Joined))
+	}
+
+	rest := q.entries[:0]
+	for i, e := range q.entries {
+		if !taken[i] {
+			rest = append(rest, e)
+		}
+	}
+	q.entries = rest
+	return pairs
+}
+
+// Overdue takes the players who waited FillAfter or longer out of the queue
+func (q *MatchQueue) Overdue(now time.Time) []QueueEntry {
+	if q.FillAfter <= 0 {
+		return nil
+	}
+	var due []QueueEntry
+	q.entries = slices.DeleteFunc(q.entries, func(e QueueEntry) bool {
+		if now.Sub(e.Joined) < q.FillAfter {
+			return false
+		}
+		due = append(due, e)
+		q.recordWait(now.Sub(e.Joined))
+		return true
+	})
+	return due
+}
+
+func (q *MatchQueue) recordWait(w time.Duration) {
+	if q.avgWait == 0 {
+		q.avgWait = w
+		return
+	}
+	q.avgWait = (q.avgWait*4 + w) / 5
+}
+
+// EstimatedWait guesses how much longer a waiting player will wait: until
+// their gap reaches the closest rating already waiting, or else the recent
+// average wait. Either way no longer than until a bot fills in.
+func (q *MatchQueue) EstimatedWait(name string, now time.Time) time.Duration {
+	e, ok := q.Entry(name)
+	if !ok {
+		return 0
+	}
+	waited := now.Sub(e.Joined)
+
+	estimate := time.Duration(-1)
+	for _, o := range q.entries {
+		gap := math.Abs(e.Rating - o.Rating)
+		if o.Name == e.Name || gap > MatchGapMax {
+			continue
+		}
+		// Either player's gap may reach it first; the longer waiting one decides
+		longest := max(waited, now.Sub(o.Joined))
+		need := time.Duration(max(gap-MatchGapStart, 0)/MatchGapGrowth*float64(time.Second)) - longest
+		if estimate < 0 || need < estimate {
+			estimate = max(need, 0)
+		}
+	}
+	if estimate < 0 {
+		avg := q.avgWait
+		if avg == 0 {
+			avg = DefaultMatchWait
+		}
+		estimate = max(avg-waited, 0)
+	}
+	if q.FillAfter > 0 {
+		estimate = min(estimate, max(q.FillAfter-waited, 0))
+	}
+	return estimate
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestRatingChange checks the Elo updates are zero-sum and favour upsets
+func TestRatingChange(t *testing.T) {
+	if e := ExpectedScore(1400, 1200); math.Abs(e-0.76) > 0.01 {
+		t.Errorf("expected a 200 point favourite to score about 0.76, got %v", e)
+	}
+	win := RatingChange(1200, 1200, 1, provisionalGames)
+	loss := RatingChange(1200, 1200, 0, provisionalGames)
+	if win != ratingK/2 || win != -loss {
+		t.Errorf("expected ±%v between equals, got %v/%v", ratingK/2, win, loss)
+	}
+	if upset := RatingChange(1000, 1400, 1, provisionalGames); upset <= win {
+		t.Errorf("expected an upset to pay more than %v, got %v", win, upset)
+	}
+	if RatingChange(1200, 1200, 1, 0) != 2*win {
+		t.Error("expected new players to move faster")
+	}
+	if RatingChange(1200, 1200, ResultScore("draw"), 0) != 0 {
+		t.Error("expected a draw between equals to change nothing")
+	}
+}
+
+// TestMatchQueue pairs the closest ratings and widens the gap over time
+func TestMatchQueue(t *testing.T) {
+	start := time.Unix(1000, 0)
+	q := &MatchQueue{}
+	q.Add(QueueEntry{Name: "ann", Rating: 1200, Joined: start})
+	q.Add(QueueEntry{Name: "bob", Rating: 1500, Joined: start.Add(time.Second)})
+	q.Add(QueueEntry{Name: "cat", Rating: 1260, Joined: start.Add(2 * time.Second)})
+	q.Add(QueueEntry{Name: "ann", Rating: 1210, Joined: start.Add(3 * time.Second)}) // Again, from another session
+	if q.Len() != 3 {
+		t.Fatalf("expected ann to be queued once, got %d entries", q.Len())
+	}
+	if e, _ := q.Entry("ann"); e.Joined != start || e.Rating != 1210 {
+		t.Errorf("expected ann to keep her place with the new entry, got %+v", e)
+	}
+
+	pairs := q.Match(start.Add(3 * time.Second))
+	if len(pairs) != 1 || pairs[0][0].Name != "ann" || pairs[0][1].Name != "cat" {
+		t.Fatalf("expected ann vs cat, got %+v", pairs)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expected bob to keep waiting, got %d entries", q.Len())
+	}
+
+	// Alone in the queue: the recent average wait
+	if w := q.EstimatedWait("bob", start.Add(3*time.Second)); w <= 0 || w > 3*time.Second {
+		t.Errorf("expected about the average wait left, got %v", w)
+	}
+
+	// dan is 400 points away: bob's gap reaches that 12s into his wait
+	q.Add(QueueEntry{Name: "dan", Rating: 1100, Joined: start.Add(4 * time.Second)})
+	if w := q.EstimatedWait("dan", start.Add(5*time.Second)); w != 8*time.Second {
+		t.Errorf("expected 8s to go, got %v", w)
+	}
+	if pairs := q.Match(start.Add(12 * time.Second)); len(pairs) != 0 {
+		t.Errorf("expected no match before the gap is wide enough, got %+v", pairs)
+	}
+	if pairs := q.Match(start.Add(13 * time.Second)); len(pairs) != 1 {
+		t.Errorf("expected bob and dan to meet once bob waited long enough, got %+v", pairs)
+	}
+
+	if q.Remove("bob") || q.Len() != 0 {
+		t.Errorf("expected an empty queue, got %d entries", q.Len())
+	}
+	if MatchGap(time.Hour) != MatchGapMax {
+		t.Errorf("expected the gap to stop at %v, got %v", MatchGapMax, MatchGap(time.Hour))
+	}
+}
+
+// TestForfeit hands a one on one game to the player who stayed
+func TestForfeit(t *testing.T) {
+	g := NewSeededGame(25, 25, 1)
+	g.SetupPVP("a", "b")
+	g.Forfeit(0)
+	if !g.GameOver || g.Result(0) != "lost" || g.Result(1) != "won" {
+		t.Errorf("expected b to win, got %s/%s", g.Result(0), g.Result(1))
+	}
+}
+
+// TestBotFill takes players out of the queue once they waited FillAfter
+func TestBotFill(t *testing.T) {
+	start := time.Unix(1000, 0)
+	q := &MatchQueue{FillAfter: 20 * time.Second}
+	q.Add(QueueEntry{Name: "ann", Rating: 1200, Joined: start})
+	q.Add(QueueEntry{Name: "bob", Rating: 2000, Joined: start.Add(10 * time.Second)})
+
+	if w := q.EstimatedWait("ann", start.Add(15*time.Second)); w != 5*time.Second {
+		t.Errorf("expected the bot to fill in 5s from now, got %v", w)
+	}
+	if due := q.Overdue(start.Add(19 * time.Second)); len(due) != 0 {
+		t.Errorf("expected nobody due yet, got %+v", due)
+	}
+	due := q.Overdue(start.Add(25 * time.Second))
+	if len(due) != 1 || due[0].Name != "ann" || q.Len() != 1 {
+		t.Errorf("expected only ann to get a bot, got %+v", due)
+	}
+	if due := (&MatchQueue{}).Overdue(start.Add(time.Hour)); due != nil {
+		t.Errorf("expected no bots without FillAfter, got %+v", due)
+	}
+}
+
+// TestSetupBotOpponent tunes the bot by rating at the match speed
+func TestSetupBotOpponent(t *testing.T) {
+	weak, strong := RatingProfile(800), RatingProfile(1600)
+	if weak.DecisionNoise != ProfileBeginner.DecisionNoise || strong.DecisionNoise != ProfileHard.DecisionNoise {
+		t.Errorf("expected beginner to hard noise, got %v to %v", weak.DecisionNoise, strong.DecisionNoise)
+	}
+
+	g := NewSeededGame(25, 25, 1)
+	g.SetupPVP("a", "bot")
+	g.SetupBotOpponent(1, 1200)
+	p := g.Players[1]
+	if p.Controller == "manual" || p.Profile == nil || p.Profile.SpeedTier != "mid" {
+		t.Fatalf("expected an AI at mid speed, got %+v", p)
+	}
+	if g.Players[0].Controller != "manual" {
+		t.Error("expected the human seat to stay manual")
+	}
+}
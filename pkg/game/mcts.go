@@ -0,0 +1,229 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// MCTS defaults tuned so a decision fits comfortably inside one AI move interval.
+// The rollout cap is what normally ends a search; the budget only bounds it
+// on a slow or busy machine.
+const (
+	DefaultMCTSRollouts     = 32
+	DefaultMCTSBudget       = 40 * time.Millisecond
+	DefaultMCTSRolloutMoves = 10
+	DefaultMCTSExploration  = 1.4
+)
+
+// mctsDeathPenalty is subtracted from a rollout's value when the searching snake crashes
+const mctsDeathPenalty = 200.0
+
+// --- Implementation: Monte Carlo Tree Search Controller ---
+
+// MCTSController searches over root actions (direction x boost x fire) by
+// cloning the game and playing heuristic rollouts until the rollout cap is
+// reached or the time budget runs out. The most visited root action is returned.
+type MCTSController struct {
+	Rollouts     int           // Rollouts per move, 0 for as many as the budget allows
+	Budget       time.Duration // Upper bound on thinking time per move, 0 for none
+	RolloutMoves int           // Moves the searching snake makes per rollout
+	Exploration  float64       // UCB1 exploration constant
+	Difficulty   string        // Speed tier used inside rollouts, empty for the game's
+}
+
+// NewMCTSController creates a searcher with the default budget
+func NewMCTSController() *MCTSController {
+	return &MCTSController{
+		Rollouts:     DefaultMCTSRollouts,
+		Budget:       DefaultMCTSBudget,
+		RolloutMoves: DefaultMCTSRolloutMoves,
+		Exploration:  DefaultMCTSExploration,
+	}
+}
+
+type mctsArm struct {
+	action ActionData
+	visits int
+	total  float64
+}
+
+func (c *MCTSController) GetAction(g *Game, playerIdx int) ActionData {
+	if playerIdx >= len(g.Players) || len(g.Players[playerIdx].Snake) == 0 {
+		return ActionData{}
+	}
+
+	arms := c.rootActions(g, playerIdx)
+	if len(arms) == 0 {
+		// Boxed in: let the heuristic pick the least bad option
+		hc := &HeuristicController{}
+		return hc.GetAction(g, playerIdx)
+	}
+
+	deadline := time.Now().Add(c.Budget)
+	minVal, maxVal := math.Inf(1), math.Inf(-1)
+	totalVisits := 0
+
+	for totalVisits < len(arms) || c.searching(totalVisits, deadline) {
+		arm := c.selectArm(arms, totalVisits, minVal, maxVal)
+		v := c.rollout(g, playerIdx, arm.action)
+		arm.visits++
+		arm.total += v
+		totalVisits++
+		minVal = math.Min(minVal, v)
+		maxVal = math.Max(maxVal, v)
+	}
+
+	best := arms[0]
+	for _, arm := range arms[1:] {
+		if arm.visits > best.visits || (arm.visits == best.visits && arm.total > best.total) {
+			best = arm
+		}
+	}
+	return best.action
+}
+
+// searching reports whether another rollout fits the cap and the budget.
+// Without a time budget the search is the same on any machine.
+func (c *MCTSController) searching(visits int, deadline time.Time) bool {
+	if c.Rollouts > 0 && visits >= c.Rollouts {
+		return false
+	}
+	if c.Budget > 0 {
+		return time.Now().Before(deadline)
+	}
+	return c.Rollouts > 0
+}
+
+// speedTier is the tier rollouts move unprofiled snakes at
+func (c *MCTSController) speedTier(g *Game) string {
+	if c.Difficulty != "" {
+		return c.Difficulty
+	}
+	return g.SpeedTier
+}
+
+// rootActions enumerates the legal, immediately safe actions for the player
+func (c *MCTSController) rootActions(g *Game, idx int) []*mctsArm {
+	p := g.Players[idx]
+	head := p.Snake[0]
+
+	canFire := !p.Stunned && g.Now().Sub(p.LastFireTime) >= config.FireballCooldown
+	fireOpts := []bool{false}
+	if canFire {
+		fireOpts = append(fireOpts, true)
+	}
+
+	var arms []*mctsArm
+	dirs := []Point{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}}
+	for _, dir := range dirs {
+		if (dir.X != 0 && p.LastMoveDir.X == -dir.X) || (dir.Y != 0 && p.LastMoveDir.Y == -dir.Y) {
+			continue
+		}
+		if !g.isSafe(Point{X: head.X + dir.X, Y: head.Y + dir.Y}, idx) {
+			continue
+		}
+		for _, boost := range []bool{false, true} {
+			for _, fire := range fireOpts {
+				arms = append(arms, &mctsArm{action: ActionData{Direction: dir, Boost: boost, Fire: fire}})
+			}
+		}
+	}
+	return arms
+}
+
+// selectArm applies UCB1 with values normalised to [0, 1] by the observed range
+func (c *MCTSController) selectArm(arms []*mctsArm, totalVisits int, minVal, maxVal float64) *mctsArm {
+	for _, arm := range arms {
+		if arm.visits == 0 {
+			return arm
+		}
+	}
+
+	span := maxVal - minVal
+	if span <= 0 {
+		span = 1
+	}
+	logN := math.Log(float64(totalVisits))
+
+	var best *mctsArm
+	bestScore := math.Inf(-1)
+	for _, arm := range arms {
+		mean := (arm.total/float64(arm.visits) - minVal) / span
+		score := mean + c.Exploration*math.Sqrt(logN/float64(arm.visits))
+		if score > bestScore {
+			bestScore = score
+			best = arm
+		}
+	}
+	return best
+}
+
+// rollout plays the action on a cloned game, then lets the heuristic policy
+// drive every snake, and scores the outcome from the searcher's point of view
+func (c *MCTSController) rollout(g *Game, idx int, action ActionData) float64 {
+	sim := g.Clone()
+	for i, p := range sim.Players {
+		if i == idx {
+			p.Brain = &rolloutController{first: action}
+		} else {
+			p.Brain = &HeuristicController{}
+		}
+	}
+
+	me := sim.Players[idx]
+	startScore := me.Score
+	startDeaths := me.Deaths
+	oppStart := make([]int, len(sim.Players))
+	oppDeaths := make([]int, len(sim.Players))
+	for i, p := range sim.Players {
+		oppStart[i] = p.Score
+		oppDeaths[i] = p.Deaths
+	}
+
+	s := NewSimulator(sim, c.speedTier(g))
+	for s.Moves(idx) < c.RolloutMoves && !sim.GameOver && !sim.Paused && me.Deaths == startDeaths {
+		s.Tick()
+	}
+
+	value := float64(me.Score - startScore)
+	bestOpp := 0
+	for i, p := range sim.Players {
+		if i == idx {
+			continue
+		}
+		if p.Score-oppStart[i] > bestOpp {
+			bestOpp = p.Score - oppStart[i]
+		}
+		// A crashed opponent ends a PVP match; in solo it only loses its length
+		if p.Deaths > oppDeaths[i] {
+			if sim.IsPVP {
+				value += mctsDeathPenalty
+			} else {
+				value += mctsDeathPenalty / 4
+			}
+		}
+	}
+	value -= 0.5 * float64(bestOpp)
+
+	if me.Deaths > startDeaths {
+		value -= mctsDeathPenalty
+	}
+	return value
+}
+
+// rolloutController plays a fixed first action, then defers to the heuristic policy
+type rolloutController struct {
+	first  ActionData
+	used   bool
+	policy HeuristicController
+}
+
+func (c *rolloutController) GetAction(g *Game, playerIdx int) ActionData {
+	if !c.used {
+		c.used = true
+		return c.first
+	}
+	return c.policy.GetAction(g, playerIdx)
+}
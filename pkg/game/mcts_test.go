@@ -0,0 +1,133 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// TestCloneIsIndependent checks that simulating a clone never touches the original game
+func TestCloneIsIndependent(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.Players[0].Snake = []Point{{X: 10, Y: 10}, {X: 9, Y: 10}, {X: 8, Y: 10}}
+	g.Players[0].Effects = []*ActiveEffect{{Type: EffectShield, ExpireAt: time.Now().Add(time.Minute)}}
+	g.Fireballs = []*Fireball{{Pos: Point{X: 5, Y: 5}, Dir: Point{X: 1, Y: 0}, Owner: "player"}}
+	g.Obstacles = []Obstacle{{Points: []Point{{X: 3, Y: 3}}, SpawnTime: time.Now(), Duration: 30}}
+
+	c := g.Clone()
+	if !c.Headless || c.Clock == nil || c.Recorder != nil {
+		t.Fatalf("clone should be headless with a sim clock and no recorder")
+	}
+
+	c.Players[0].Snake[0] = Point{X: 1, Y: 1}
+	c.Players[0].Effects[0].Type = EffectMagnet
+	c.Fireballs[0].Pos = Point{X: 6, Y: 5}
+	c.Obstacles[0].Points[0] = Point{X: 4, Y: 4}
+	c.SetMessage("should not appear")
+
+	for _, p := range c.Players {
+		p.Brain = &HeuristicController{}
+	}
+	sim := NewSimulator(c, "mid")
+	for i := 0; i < 200 && !c.GameOver; i++ {
+		sim.Tick()
+	}
+
+	if g.Players[0].Snake[0] != (Point{X: 10, Y: 10}) {
+		t.Errorf("original snake modified: %v", g.Players[0].Snake)
+	}
+	if g.Players[0].Effects[0].Type != EffectShield {
+		t.Errorf("original effects modified")
+	}
+	if g.Fireballs[0].Pos != (Point{X: 5, Y: 5}) {
+		t.Errorf("original fireball modified: %v", g.Fireballs[0].Pos)
+	}
+	if g.Obstacles[0].Points[0] != (Point{X: 3, Y: 3}) {
+		t.Errorf("original obstacle modified")
+	}
+	if c.Message != "" {
+		t.Errorf("headless clone should not carry messages, got %q", c.Message)
+	}
+}
+
+// TestSimulatorUsesVirtualTime checks that a simulated minute passes without waiting for it
+func TestSimulatorUsesVirtualTime(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.Players = g.Players[:1]
+	g.Players[0].Brain = &HeuristicController{}
+
+	sim := NewSimulator(g, "mid")
+	start := time.Now()
+	ticks := int(config.GameDuration/config.BaseTick) + 10
+	for i := 0; i < ticks && !g.GameOver; i++ {
+		sim.Tick()
+	}
+
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("simulation should not depend on wall-clock time")
+	}
+	if !g.GameOver {
+		t.Errorf("expected the time limit to end the simulated game")
+	}
+}
+
+// TestMCTSAvoidsCrash checks that the searcher never picks a move into a wall
+func TestMCTSAvoidsCrash(t *testing.T) {
+	g := NewGame(config.StandardWidth, config.StandardHeight)
+	g.Players[1].Brain = &HeuristicController{}
+	// Heading left along the top wall: only "down" keeps the snake alive
+	g.Players[0].Snake = []Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}
+	g.Players[0].Direction = Point{X: -1, Y: 0}
+	g.Players[0].LastMoveDir = Point{X: -1, Y: 0}
+	score := g.Players[0].Score
+
+	c := NewMCTSController()
+	c.Budget = 20 * time.Millisecond
+	action := c.GetAction(g, 0)
+
+	if action.Direction != (Point{X: 0, Y: 1}) {
+		t.Errorf("expected MCTS to turn down, got %v", action.Direction)
+	}
+	if g.Players[0].Snake[0] != (Point{X: 1, Y: 1}) || g.Players[0].Score != score {
+		t.Errorf("search must not mutate the live game")
+	}
+}
+
+// TestMCTSRolloutCap checks that a search without a time budget stops at its
+// rollout cap and picks the same move on the same game
+func TestMCTSRolloutCap(t *testing.T) {
+	pick := func() ActionData {
+		g := NewSeededGame(config.StandardWidth, config.StandardHeight, 7)
+		g.Players[1].Brain = &HeuristicController{}
+		c := NewMCTSController()
+		c.Budget = 0
+		c.Rollouts = 40
+		return c.GetAction(g, 0)
+	}
+
+	start := time.Now()
+	first := pick()
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("search should stop at its rollout cap")
+	}
+	for i := 0; i < 3; i++ {
+		if a := pick(); a != first {
+			t.Errorf("expected the same move on every run, got %+v and %+v", first, a)
+		}
+	}
+}
+
+// TestMCTSRolloutSpeed simulates the snakes at the game's speed tier, so an
+// expert game is searched at the expert move rate
+func TestMCTSRolloutSpeed(t *testing.T) {
+	g := NewSeededGame(25, 25, 1)
+	g.SetAIDifficulty("expert")
+	if g.SpeedTier != "expert" {
+		t.Fatalf("expected the expert speed tier, got %q", g.SpeedTier)
+	}
+	s := NewSimulator(g.Clone(), NewMCTSController().speedTier(g))
+	if ticks := s.ticksNeeded(0); ticks != config.HighTicks {
+		t.Errorf("expected the player to move every %d ticks, got %d", config.HighTicks, ticks)
+	}
+}
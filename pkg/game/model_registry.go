@@ -0,0 +1,285 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ModelVersion is one loaded policy network. Name comes from the file name
+// (snake_policy.onnx and snake_policy.bin are both "snake_policy").
+type ModelVersion struct {
+	Name     string          `json:"name"`
+	Version  string          `json:"version"` // Name plus file timestamp, recorded with every neural move
+	Backend  string          `json:"backend"` // "onnx" or "go"
+	LoadedAt time.Time       `json:"loaded_at"`
+	Spec     ObservationSpec `json:"spec"` // Input the model was trained on, from <name>.spec.json
+
+	model   PolicyModel
+	modTime time.Time
+	refs    *atomic.Int32 // The registry while it serves this version, plus predictions in flight
+}
+
+// acquire keeps the model alive for one prediction. It fails once the
+// version was replaced and its last prediction finished.
+func (mv *ModelVersion) acquire() bool {
+	for {
+		n := mv.refs.Load()
+		if n <= 0 {
+			return false
+		}
+		if mv.refs.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// release drops a reference; the last one frees the model's native resources
+func (mv *ModelVersion) release() {
+	if mv.refs.Add(-1) != 0 {
+		return
+	}
+	if m, ok := mv.model.(interface{ destroy() }); ok {
+		m.destroy()
+		log.Printf("📦 Model %s unloaded\n", mv.Version)
+	}
+}
+
+// ModelRegistry holds every policy network found in a directory and
+// reloads them when their files change
+type ModelRegistry struct {
+	reloadMu sync.Mutex // Serializes Reload calls (watcher and admin endpoint)
+	mu       sync.RWMutex
+	dir      string
+	def      string
+	models   map[string]*ModelVersion
+	abSplit  []string
+}
+
+// Models is the registry started by StartInferenceService (nil until then)
+var Models *ModelRegistry
+
+// NewModelRegistry creates an empty registry for dir; call Reload to load it
+func NewModelRegistry(dir, defaultName string) *ModelRegistry {
+	return &ModelRegistry{dir: dir, def: defaultName, models: make(map[string]*ModelVersion)}
+}
+
+// Reload loads new and changed models and forgets deleted ones. A model
+// that fails to load keeps serving its previous version.
+func (r *ModelRegistry) Reload() (changed []string, err error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// A model may have an .onnx file, a .bin file or both, plus a .spec.json
+	// that only triggers a reload when the model itself exists
+	found := make(map[string]time.Time)
+	specs := make(map[string]time.Time)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, ierr := e.Info()
+		if ierr != nil {
+			continue
+		}
+		if name, ok := strings.CutSuffix(e.Name(), ".spec.json"); ok {
+			specs[name] = info.ModTime()
+			continue
+		}
+		ext := filepath.Ext(e.Name())
+		if ext != ".onnx" && ext != ".bin" {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), ext)
+		if info.ModTime().After(found[name]) {
+			found[name] = info.ModTime()
+		}
+	}
+	for name, modTime := range specs {
+		if current, ok := found[name]; ok && modTime.After(current) {
+			found[name] = modTime
+		}
+	}
+
+	var errs []error
+	for name, modTime := range found {
+		r.mu.RLock()
+		current := r.models[name]
+		r.mu.RUnlock()
+		if current != nil && current.modTime.Equal(modTime) {
+			continue
+		}
+
+		mv, lerr := loadModelVersion(filepath.Join(r.dir, name+".onnx"), modTime)
+		if lerr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, lerr))
+			continue
+		}
+		r.mu.Lock()
+		old := r.models[name]
+		r.models[name] = mv
+		r.mu.Unlock()
+		if old != nil {
+			old.release() // Freed once its running predictions are done
+		}
+		changed = append(changed, mv.Version)
+		log.Printf("📦 Model %s loaded (%s backend, observation v%d)\n", mv.Version, mv.Backend, mv.Spec.Version)
+	}
+
+	r.mu.Lock()
+	for name, mv := range r.models {
+		if _, ok := found[name]; !ok {
+			delete(r.models, name)
+			mv.release()
+			changed = append(changed, name+" (removed)")
+			log.Printf("📦 Model %s removed\n", name)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Strings(changed)
+	return changed, errors.Join(errs...)
+}
+
+// LoadModel loads one model and its observation spec outside any registry,
+// for offline tools. modelPath names the .onnx file; the .bin weights next
+// to it are used when ONNX Runtime is unavailable.
+func LoadModel(modelPath string) (*ModelVersion, error) {
+	base := strings.TrimSuffix(modelPath, filepath.Ext(modelPath))
+	var modTime time.Time
+	for _, ext := range []string{".onnx", ".bin"} {
+		if info, err := os.Stat(base + ext); err == nil && info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return loadModelVersion(base+".onnx", modTime)
+}
+
+func loadModelVersion(modelPath string, modTime time.Time) (*ModelVersion, error) {
+	spec, err := LoadObservationSpec(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	model, err := loadPolicyModel(modelPath, spec)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(modelPath), filepath.Ext(modelPath))
+	refs := new(atomic.Int32)
+	refs.Store(1) // Held by whoever loaded it
+	return &ModelVersion{
+		Name:     name,
+		Version:  name + "@" + modTime.Format("20060102-150405"),
+		Backend:  backendName(model),
+		LoadedAt: time.Now(),
+		Spec:     spec,
+		model:    model,
+		modTime:  modTime,
+		refs:     refs,
+	}, nil
+}
+
+// Run evaluates a batch of observations directly, bypassing the shared
+// prediction queue. Games should use Predict.
+func (mv *ModelVersion) Run(inputs [][]float64) [][]float32 {
+	if !mv.acquire() {
+		return make([][]float32, len(inputs))
+	}
+	defer mv.release()
+	return mv.model.internalPredictBatch(inputs)
+}
+
+// Watch polls the directory and reloads on changes until stop is closed
+func (r *ModelRegistry) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := r.Reload(); err != nil {
+				log.Printf("⚠️ Model reload: %v\n", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Get returns a model by name; an empty name means the default model
+func (r *ModelRegistry) Get(name string) (*ModelVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		name = r.def
+	}
+	mv, ok := r.models[name]
+	return mv, ok
+}
+
+// Default returns the name of the model used when a game does not pick one
+func (r *ModelRegistry) Default() string {
+	return r.def
+}
+
+// List returns the loaded models sorted by name
+func (r *ModelRegistry) List() []ModelVersion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ModelVersion, 0, len(r.models))
+	for _, mv := range r.models {
+		out = append(out, *mv)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// SetABTest spreads new games evenly over the named models. An empty list
+// ends the test and every game uses the default model again.
+func (r *ModelRegistry) SetABTest(names []string) error {
+	for _, name := range names {
+		if _, ok := r.Get(name); !ok {
+			return fmt.Errorf("unknown model %q", name)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.abSplit = append([]string(nil), names...)
+	return nil
+}
+
+// ABTest returns the models new games are currently split across
+func (r *ModelRegistry) ABTest() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.abSplit...)
+}
+
+// Assign picks the model for a new game: a random arm of the A/B test if one
+// is running, the default model otherwise
+func (r *ModelRegistry) Assign(g *Game) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.abSplit) == 0 {
+		return ""
+	}
+	return r.abSplit[g.rng().Intn(len(r.abSplit))]
+}
+
+func backendName(model PolicyModel) string {
+	if _, ok := model.(*CNNModel); ok {
+		return "go"
+	}
+	return "onnx"
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestModelRegistryReload checks models are found, hot reloaded and removed with their files
+func TestModelRegistryReload(t *testing.T) {
+	dir := t.TempDir()
+	writeWeights(t, filepath.Join(dir, "alpha.bin"), tinyCNNTensors())
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644)
+
+	r := NewModelRegistry(dir, "alpha")
+	changed, err := r.Reload()
+	if err != nil || len(changed) != 1 {
+		t.Fatalf("expected alpha to load, got %v (%v)", changed, err)
+	}
+	alpha, ok := r.Get("")
+	if !ok || alpha.Name != "alpha" || alpha.Backend != "go" {
+		t.Fatalf("expected the default model to be alpha on the go backend, got %+v", alpha)
+	}
+
+	// Unchanged files are not reloaded
+	if changed, _ := r.Reload(); len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+
+	// A new file and a touched file are both picked up
+	writeWeights(t, filepath.Join(dir, "beta.bin"), tinyCNNTensors())
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(dir, "alpha.bin"), later, later)
+	changed, _ = r.Reload()
+	if len(changed) != 2 {
+		t.Errorf("expected alpha and beta to change, got %v", changed)
+	}
+	if mv, _ := r.Get("alpha"); mv.Version == alpha.Version {
+		t.Error("expected a new version after the file changed")
+	}
+
+	// A broken file keeps the previous version serving
+	os.WriteFile(filepath.Join(dir, "beta.bin"), []byte("broken"), 0644)
+	if _, err := r.Reload(); err == nil {
+		t.Error("expected a reload error for a broken model")
+	}
+	if _, ok := r.Get("beta"); !ok {
+		t.Error("beta should keep serving its last good version")
+	}
+
+	os.Remove(filepath.Join(dir, "beta.bin"))
+	r.Reload()
+	if _, ok := r.Get("beta"); ok {
+		t.Error("beta should be gone after its file was deleted")
+	}
+}
+
+// TestModelRegistryABTest checks games are spread over the arms of an A/B test
+func TestModelRegistryABTest(t *testing.T) {
+	dir := t.TempDir()
+	writeWeights(t, filepath.Join(dir, "alpha.bin"), tinyCNNTensors())
+	writeWeights(t, filepath.Join(dir, "beta.bin"), tinyCNNTensors())
+	r := NewModelRegistry(dir, "alpha")
+	r.Reload()
+
+	if err := r.SetABTest([]string{"alpha", "gamma"}); err == nil {
+		t.Error("expected an error for an unknown model")
+	}
+	if err := r.SetABTest([]string{"alpha", "beta"}); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewSeededGame(25, 25, 7)
+	seen := map[string]int{}
+	for i := 0; i < 50; i++ {
+		seen[r.Assign(g)]++
+	}
+	if seen["alpha"] == 0 || seen["beta"] == 0 || len(seen) != 2 {
+		t.Errorf("expected both arms to be assigned, got %v", seen)
+	}
+
+	r.SetABTest(nil)
+	if name := r.Assign(g); name != "" {
+		t.Errorf("expected the default model after the test ended, got %q", name)
+	}
+}
+
+// TestInferenceWorkerSplitsModels checks one batch is split per model
+func TestInferenceWorkerSplitsModels(t *testing.T) {
+	queue := make(chan PredictRequest, 8)
+	a, b := &echoModel{}, &echoModel{}
+	results := make([]chan []float32, 6)
+	for i := range results {
+		results[i] = make(chan []float32, 1)
+		model := PolicyModel(a)
+		if i%2 == 1 {
+			model = b
+		}
+		queue <- PredictRequest{Input: []float64{float64(i)}, ResChan: results[i], Enqueued: time.Now(), Model: model}
+	}
+	stats := &inferenceMetrics{}
+	go runInferenceWorker(queue, InferenceOptions{Workers: 1, BatchSize: 8, BatchWait: time.Millisecond}, stats)
+
+	for i, ch := range results {
+		if logits := <-ch; logits[0] != float32(i) {
+			t.Errorf("request %d got logits for %v", i, logits[0])
+		}
+	}
+	close(queue)
+	if len(a.batches) != 1 || a.batches[0] != 3 || len(b.batches) != 1 || b.batches[0] != 3 {
+		t.Errorf("expected one batch of 3 per model, got %v and %v", a.batches, b.batches)
+	}
+}
+
+// closingModel records when its native resources would be freed
+type closingModel struct {
+	echoModel
+	destroyed bool
+}
+
+func (m *closingModel) destroy() { m.destroyed = true }
+
+// TestModelVersionRelease checks a replaced model is freed only after its
+// last prediction, and takes no new ones after that
+func TestModelVersionRelease(t *testing.T) {
+	m := &closingModel{}
+	mv := &ModelVersion{Version: "old", model: m, refs: new(atomic.Int32)}
+	mv.refs.Store(1)
+
+	if !mv.acquire() {
+		t.Fatal("a served model should take predictions")
+	}
+	mv.release() // The registry swapped in a new version
+	if m.destroyed {
+		t.Fatal("the model was freed while a prediction still used it")
+	}
+	mv.release()
+	if !m.destroyed {
+		t.Error("expected the model to be freed after its last prediction")
+	}
+	if mv.acquire() || mv.Predict([]float64{1}) != nil {
+		t.Error("an unloaded model should refuse predictions")
+	}
+}
@@ -0,0 +1,295 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ObservationWindow is the side of the head-centred window every channel covers
+const ObservationWindow = 25
+
+// ObservationSpec names the feature planes of a network input, in order.
+// Each model declares the spec it was trained with in <name>.spec.json next
+// to its weights; the Go side builds the matching tensor from it.
+type ObservationSpec struct {
+	Version  int      `json:"version"`
+	Channels []string `json:"channels"`
+}
+
+// ObservationV1 is the original six-channel grid. Models without a spec file use it.
+var ObservationV1 = ObservationSpec{
+	Version:  1,
+	Channels: []string{"head", "body", "enemy_head", "enemy_body", "food", "hazard"},
+}
+
+// ObservationV2 adds food value, props by type, fireball headings, the
+// agent's active effects, time remaining and stun state
+var ObservationV2 = ObservationSpec{
+	Version: 2,
+	Channels: append(append([]string(nil), ObservationV1.Channels...),
+		"food_value",
+		"prop_shield", "prop_timewarp", "prop_trimmer", "prop_magnet", "prop_chest", "prop_rapidfire", "prop_scatter",
+		"fireball_dx", "fireball_dy",
+		"effect_shield", "effect_timewarp", "effect_magnet", "effect_rapidfire", "effect_scatter",
+		"time_left", "stunned", "enemy_stunned",
+	),
+}
+
+var observationSpecs = map[int]ObservationSpec{
+	1: ObservationV1,
+	2: ObservationV2,
+}
+
+// ObservationSpecVersion returns a built-in spec; 0 means version 1
+func ObservationSpecVersion(version int) (ObservationSpec, error) {
+	if version == 0 {
+		version = 1
+	}
+	spec, ok := observationSpecs[version]
+	if !ok {
+		return ObservationSpec{}, fmt.Errorf("unknown observation version %d", version)
+	}
+	return spec, nil
+}
+
+// Size returns the length of one observation built from the spec
+func (s ObservationSpec) Size() int {
+	return len(s.Channels) * ObservationWindow * ObservationWindow
+}
+
+// Validate checks that every channel is known and appears once
+func (s ObservationSpec) Validate() error {
+	if len(s.Channels) == 0 {
+		return errors.New("observation spec has no channels")
+	}
+	seen := make(map[string]bool, len(s.Channels))
+	for _, name := range s.Channels {
+		if _, ok := observationChannels[name]; !ok {
+			return fmt.Errorf("unknown observation channel %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("observation channel %q listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// LoadObservationSpec reads the spec declared next to a model file. A missing
+// file means the model predates specs and uses ObservationV1. A file with
+// only a version uses that built-in spec's channels.
+func LoadObservationSpec(modelPath string) (ObservationSpec, error) {
+	path := strings.TrimSuffix(modelPath, filepath.Ext(modelPath)) + ".spec.json"
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ObservationV1, nil
+	}
+	if err != nil {
+		return ObservationSpec{}, err
+	}
+
+	var spec ObservationSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return ObservationSpec{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(spec.Channels) == 0 {
+		builtin, err := ObservationSpecVersion(spec.Version)
+		if err != nil {
+			return ObservationSpec{}, fmt.Errorf("%s: %w", path, err)
+		}
+		spec.Channels = builtin.Channels
+	}
+	if err := spec.Validate(); err != nil {
+		return ObservationSpec{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// obsView is the head-centred window one observation is built in
+type obsView struct {
+	g       *Game
+	me      int
+	offsetX int
+	offsetY int
+}
+
+// set writes v at a world position if it falls inside the window
+func (v *obsView) set(plane []float64, x, y int, val float64) {
+	relX, relY := x-v.offsetX, y-v.offsetY
+	if relX >= 0 && relX < ObservationWindow && relY >= 0 && relY < ObservationWindow {
+		plane[relY*ObservationWindow+relX] = val
+	}
+}
+
+// fill sets the whole plane, for scalar features
+func fill(plane []float64, val float64) {
+	for i := range plane {
+		plane[i] = val
+	}
+}
+
+type channelFunc func(v *obsView, plane []float64)
+
+// observationChannels fills one plane each. New channels only need an entry
+// here and a place in a spec.
+var observationChannels = map[string]channelFunc{
+	"head": func(v *obsView, plane []float64) {
+		head := v.g.Players[v.me].Snake[0]
+		v.set(plane, head.X, head.Y, 1)
+	},
+	"body": func(v *obsView, plane []float64) {
+		for _, p := range v.g.Players[v.me].Snake[1:] {
+			v.set(plane, p.X, p.Y, 1)
+		}
+	},
+	"enemy_head": func(v *obsView, plane []float64) {
+		v.eachEnemy(func(other *Player) {
+			if len(other.Snake) > 0 {
+				v.set(plane, other.Snake[0].X, other.Snake[0].Y, 1)
+			}
+		})
+	},
+	"enemy_body": func(v *obsView, plane []float64) {
+		v.eachEnemy(func(other *Player) {
+			if len(other.Snake) > 1 {
+				for _, p := range other.Snake[1:] {
+					v.set(plane, p.X, p.Y, 1)
+				}
+			}
+		})
+	},
+	"food": func(v *obsView, plane []float64) {
+		for _, f := range v.g.Foods {
+			v.set(plane, f.Pos.X, f.Pos.Y, 1)
+		}
+	},
+	// Obstacles, fireballs and the walls around the board
+	"hazard": func(v *obsView, plane []float64) {
+		g := v.g
+		for vy := 0; vy < ObservationWindow; vy++ {
+			for vx := 0; vx < ObservationWindow; vx++ {
+				worldX, worldY := vx+v.offsetX, vy+v.offsetY
+				if worldX <= 0 || worldX >= g.Width-1 || worldY <= 0 || worldY >= g.Height-1 {
+					plane[vy*ObservationWindow+vx] = 1
+				}
+			}
+		}
+		for _, obs := range g.Obstacles {
+			for _, p := range obs.Points {
+				v.set(plane, p.X, p.Y, 1)
+			}
+		}
+		for _, fb := range g.Fireballs {
+			v.set(plane, fb.Pos.X, fb.Pos.Y, 1)
+		}
+	},
+	// Total score of each food, corner and edge bonus included, scaled so a red corner food is 1
+	"food_value": func(v *obsView, plane []float64) {
+		for _, f := range v.g.Foods {
+			score := float64(f.GetTotalScore(v.g.Width, v.g.Height))
+			v.set(plane, f.Pos.X, f.Pos.Y, score/140)
+		}
+	},
+	"prop_shield":    propChannel(PropShield),
+	"prop_timewarp":  propChannel(PropTimeWarp),
+	"prop_trimmer":   propChannel(PropTrimmer),
+	"prop_magnet":    propChannel(PropMagnet),
+	"prop_chest":     propChannel(PropChestBig, PropChestSmall),
+	"prop_rapidfire": propChannel(PropRapidFire),
+	"prop_scatter":   propChannel(PropScatterShot),
+	"fireball_dx": func(v *obsView, plane []float64) {
+		for _, fb := range v.g.Fireballs {
+			v.set(plane, fb.Pos.X, fb.Pos.Y, float64(fb.Dir.X))
+		}
+	},
+	"fireball_dy": func(v *obsView, plane []float64) {
+		for _, fb := range v.g.Fireballs {
+			v.set(plane, fb.Pos.X, fb.Pos.Y, float64(fb.Dir.Y))
+		}
+	},
+	"effect_shield":    effectChannel(EffectShield),
+	"effect_timewarp":  effectChannel(EffectTimeWarp),
+	"effect_magnet":    effectChannel(EffectMagnet),
+	"effect_rapidfire": effectChannel(EffectRapidFire),
+	"effect_scatter":   effectChannel(EffectScatterShot),
+	"time_left": func(v *obsView, plane []float64) {
+		fill(plane, float64(v.g.GetTimeRemaining())/v.g.GameDuration().Seconds())
+	},
+	"stunned": func(v *obsView, plane []float64) {
+		if v.g.Players[v.me].Stunned {
+			fill(plane, 1)
+		}
+	},
+	// Marks the cells of stunned enemies, which are safe to approach for a moment
+	"enemy_stunned": func(v *obsView, plane []float64) {
+		v.eachEnemy(func(other *Player) {
+			if other.Stunned {
+				for _, p := range other.Snake {
+					v.set(plane, p.X, p.Y, 1)
+				}
+			}
+		})
+	},
+}
+
+func (v *obsView) eachEnemy(fn func(*Player)) {
+	for i, other := range v.g.Players {
+		if i != v.me {
+			fn(other)
+		}
+	}
+}
+
+// propChannel marks props of the given types
+func propChannel(types ...PropType) channelFunc {
+	return func(v *obsView, plane []float64) {
+		for _, pr := range v.g.Props {
+			for _, t := range types {
+				if pr.Type == t {
+					v.set(plane, pr.Pos.X, pr.Pos.Y, 1)
+				}
+			}
+		}
+	}
+}
+
+// effectChannel is a constant plane: 1 while the agent has the effect
+func effectChannel(effect EffectType) channelFunc {
+	return func(v *obsView, plane []float64) {
+		for _, e := range v.g.Players[v.me].Effects {
+			if e.Type == effect {
+				fill(plane, 1)
+				return
+			}
+		}
+	}
+}
+
+// BuildObservation builds the network input for player idx, one 25x25 plane
+// per spec channel, centred on the player's head. A player without a snake
+// gets an all-zero observation.
+func (g *Game) BuildObservation(spec ObservationSpec, idx int) []float64 {
+	size := ObservationWindow * ObservationWindow
+	grid := make([]float64, len(spec.Channels)*size)
+	if idx >= len(g.Players) || len(g.Players[idx].Snake) == 0 {
+		return grid
+	}
+
+	head := g.Players[idx].Snake[0]
+	v := &obsView{
+		g:       g,
+		me:      idx,
+		offsetX: head.X - ObservationWindow/2,
+		offsetY: head.Y - ObservationWindow/2,
+	}
+	for c, name := range spec.Channels {
+		if fn, ok := observationChannels[name]; ok {
+			fn(v, grid[c*size:(c+1)*size])
+		}
+	}
+	return grid
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// observationGame places a player at (12,12) and an enemy at (5,5) on an empty board
+func observationGame() *Game {
+	g := NewSeededGame(25, 25, 1)
+	g.Players = []*Player{
+		{Snake: []Point{{X: 12, Y: 12}, {X: 11, Y: 12}, {X: 10, Y: 12}}},
+		{Snake: []Point{{X: 5, Y: 5}, {X: 5, Y: 6}}},
+	}
+	g.Foods = []Food{{Pos: Point{X: 1, Y: 1}, FoodType: FoodRed}}
+	g.Props = nil
+	g.Obstacles = nil
+	g.Fireballs = nil
+	g.TimerStarted = false // Full round left
+	return g
+}
+
+// plane returns one channel of an observation as a lookup by world position
+func plane(t *testing.T, spec ObservationSpec, obs []float64, channel string, head Point) func(x, y int) float64 {
+	t.Helper()
+	c := -1
+	for i, name := range spec.Channels {
+		if name == channel {
+			c = i
+		}
+	}
+	if c < 0 {
+		t.Fatalf("spec v%d has no channel %s", spec.Version, channel)
+	}
+	size := ObservationWindow * ObservationWindow
+	return func(x, y int) float64 {
+		relX := x - head.X + ObservationWindow/2
+		relY := y - head.Y + ObservationWindow/2
+		return obs[c*size+relY*ObservationWindow+relX]
+	}
+}
+
+// TestObservationV1 checks the original six channels keep their layout
+func TestObservationV1(t *testing.T) {
+	g := observationGame()
+	g.Fireballs = []*Fireball{{Pos: Point{X: 14, Y: 12}, Dir: Point{X: 1}}}
+	obs := g.GetFeatureGrid(0)
+	if len(obs) != ObservationV1.Size() || ObservationV1.Size() != 6*25*25 {
+		t.Fatalf("expected a 6x25x25 grid, got %d values", len(obs))
+	}
+
+	head := Point{X: 12, Y: 12}
+	at := func(channel string) func(x, y int) float64 { return plane(t, ObservationV1, obs, channel, head) }
+	checks := []struct {
+		channel string
+		x, y    int
+		want    float64
+	}{
+		{"head", 12, 12, 1},
+		{"body", 11, 12, 1},
+		{"body", 12, 12, 0},
+		{"enemy_head", 5, 5, 1},
+		{"enemy_body", 5, 6, 1},
+		{"food", 1, 1, 1},
+		{"hazard", 0, 3, 1},   // Wall
+		{"hazard", 14, 12, 1}, // Fireball
+		{"hazard", 13, 12, 0},
+	}
+	for _, c := range checks {
+		if got := at(c.channel)(c.x, c.y); got != c.want {
+			t.Errorf("%s at (%d,%d) = %v, want %v", c.channel, c.x, c.y, got, c.want)
+		}
+	}
+}
+
+// TestObservationV2 checks the extended channels
+func TestObservationV2(t *testing.T) {
+	g := observationGame()
+	g.Props = []Prop{{Pos: Point{X: 8, Y: 8}, Type: PropChestSmall}, {Pos: Point{X: 9, Y: 9}, Type: PropMagnet}}
+	g.Fireballs = []*Fireball{{Pos: Point{X: 14, Y: 12}, Dir: Point{X: -1}}}
+	g.Players[0].Effects = []*ActiveEffect{{Type: EffectShield}}
+	g.Players[1].Stunned = true
+
+	obs := g.BuildObservation(ObservationV2, 0)
+	if len(obs) != ObservationV2.Size() {
+		t.Fatalf("expected %d values, got %d", ObservationV2.Size(), len(obs))
+	}
+
+	head := Point{X: 12, Y: 12}
+	at := func(channel string) func(x, y int) float64 { return plane(t, ObservationV2, obs, channel, head) }
+	checks := []struct {
+		channel string
+		x, y    int
+		want    float64
+	}{
+		{"food_value", 1, 1, 1}, // Red corner food: 40 + 100
+		{"prop_chest", 8, 8, 1},
+		{"prop_magnet", 9, 9, 1},
+		{"prop_magnet", 8, 8, 0},
+		{"fireball_dx", 14, 12, -1},
+		{"fireball_dy", 14, 12, 0},
+		{"effect_shield", 3, 20, 1},
+		{"effect_magnet", 3, 20, 0},
+		{"time_left", 7, 7, 1},
+		{"stunned", 7, 7, 0},
+		{"enemy_stunned", 5, 6, 1},
+	}
+	for _, c := range checks {
+		if got := at(c.channel)(c.x, c.y); got != c.want {
+			t.Errorf("%s at (%d,%d) = %v, want %v", c.channel, c.x, c.y, got, c.want)
+		}
+	}
+
+	// The first six planes are the V1 observation
+	v1 := g.BuildObservation(ObservationV1, 0)
+	for i := range v1 {
+		if obs[i] != v1[i] {
+			t.Fatalf("V2 differs from V1 at %d", i)
+		}
+	}
+}
+
+// TestLoadObservationSpec checks the spec file next to a model
+func TestLoadObservationSpec(t *testing.T) {
+	dir := t.TempDir()
+	model := filepath.Join(dir, "policy.onnx")
+
+	spec, err := LoadObservationSpec(model)
+	if err != nil || spec.Version != 1 || len(spec.Channels) != 6 {
+		t.Errorf("expected V1 without a spec file, got %+v (%v)", spec, err)
+	}
+
+	specPath := filepath.Join(dir, "policy.spec.json")
+	os.WriteFile(specPath, []byte(`{"version": 2}`), 0644)
+	if spec, err := LoadObservationSpec(model); err != nil || len(spec.Channels) != len(ObservationV2.Channels) {
+		t.Errorf("expected the V2 channels for a version-only spec, got %+v (%v)", spec, err)
+	}
+
+	os.WriteFile(specPath, []byte(`{"version": 3, "channels": ["head", "food", "time_left"]}`), 0644)
+	if spec, err := LoadObservationSpec(model); err != nil || spec.Size() != 3*25*25 {
+		t.Errorf("expected a custom three-channel spec, got %+v (%v)", spec, err)
+	}
+
+	os.WriteFile(specPath, []byte(`{"version": 3, "channels": ["head", "x-ray"]}`), 0644)
+	if _, err := LoadObservationSpec(model); err == nil || !strings.Contains(err.Error(), "x-ray") {
+		t.Errorf("expected an unknown channel error, got %v", err)
+	}
+}
+
+// TestModelSpecMismatch checks a model is rejected when its weights do not fit its spec
+func TestModelSpecMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeWeights(t, filepath.Join(dir, "alpha.bin"), tinyCNNTensors())
+	os.WriteFile(filepath.Join(dir, "alpha.spec.json"), []byte(`{"version": 2}`), 0644)
+
+	r := NewModelRegistry(dir, "alpha")
+	if _, err := r.Reload(); err == nil {
+		t.Fatal("expected a six-channel network to be rejected for spec v2")
+	}
+
+	os.WriteFile(filepath.Join(dir, "alpha.spec.json"), []byte(`{"version": 1}`), 0644)
+	if _, err := r.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if mv, ok := r.Get(""); !ok || mv.Spec.Version != 1 {
+		t.Errorf("expected alpha with spec v1, got %+v", mv)
+	}
+}
@@ -2,7 +2,6 @@ package game
 
 import (
 	"fmt"
-	"math/rand"
 	"time"
 
 	"github.com/trytobebee/snake_go/pkg/config"
@@ -74,18 +73,26 @@ func (p *Prop) GetEffectType() EffectType {
 
 // IsExpired checks if the prop on board has expired
 func (p *Prop) IsExpired(currentTotalPaused time.Duration) bool {
+	return p.isExpiredAt(time.Now(), currentTotalPaused)
+}
+
+// isExpiredAt is IsExpired measured against the given clock reading (see Game.Now)
+func (p *Prop) isExpiredAt(now time.Time, currentTotalPaused time.Duration) bool {
 	pausedSinceSpawn := currentTotalPaused - p.PausedTimeAtSpawn
-	elapsed := time.Since(p.SpawnTime) - pausedSinceSpawn
+	elapsed := now.Sub(p.SpawnTime) - pausedSinceSpawn
 	return elapsed > config.PropDuration
 }
 
 // TrySpawnProp attempts to spawn a random prop
 func (g *Game) TrySpawnProp() {
-	if time.Since(g.LastPropSpawn) < config.PropSpawnInterval {
+	if g.NoProps {
+		return
+	}
+	if g.Now().Sub(g.LastPropSpawn) < config.PropSpawnInterval {
 		return
 	}
 
-	if rand.Intn(100) >= config.PropSpawnChance {
+	if g.rng().Intn(100) >= config.PropSpawnChance {
 		return
 	}
 
@@ -95,8 +102,8 @@ func (g *Game) TrySpawnProp() {
 
 	for attempts := 0; attempts < 50; attempts++ {
 		pos := Point{
-			X: rand.Intn(23) + 1,
-			Y: rand.Intn(23) + 1,
+			X: g.rng().Intn(g.Width-2) + 1,
+			Y: g.rng().Intn(g.Height-2) + 1,
 		}
 
 		if !g.isCellEmpty(pos) {
@@ -106,7 +113,7 @@ func (g *Game) TrySpawnProp() {
 		// Weighted selection:
 		// Shield: 1, TimeWarp: 0.5, Trimmer: 0.5, Magnet: 0.5, RapidFire: 1, ScatterShot: 1, BigChest: 2, SmallChest: 4
 		// Scaled by 2: Shield: 2, TimeWarp: 1, Trimmer: 1, Magnet: 1, RapidFire: 2, ScatterShot: 2, BigChest: 4, SmallChest: 8 (Total=21)
-		randVal := rand.Intn(21)
+		randVal := g.rng().Intn(21)
 		var t PropType
 		switch {
 		case randVal < 2:
@@ -130,11 +137,11 @@ func (g *Game) TrySpawnProp() {
 		newProp := Prop{
 			Pos:               pos,
 			Type:              t,
-			SpawnTime:         time.Now(),
+			SpawnTime:         g.Now(),
 			PausedTimeAtSpawn: g.GetTotalPausedTime(),
 		}
 		g.Props = append(g.Props, newProp)
-		g.LastPropSpawn = time.Now()
+		g.LastPropSpawn = g.Now()
 		g.SetMessage(fmt.Sprintf("%s A mysterious item appeared!", newProp.GetEmoji()))
 		break
 	}
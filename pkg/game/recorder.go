@@ -18,6 +18,14 @@ type GameRecorder struct {
 	wg         sync.WaitGroup
 	mu         sync.Mutex
 	closed     bool
+	steps      []StepRecord // Kept in memory instead when there is no file
+
+	// Per-player bookkeeping between records (see Record)
+	stepID     int
+	lastTally  []RewardTally
+	lastMoves  []int
+	lastRecord time.Time
+	done       bool
 }
 
 // NewRecorder creates a new recorder that writes to records/ directory
@@ -51,6 +59,19 @@ func NewRecorder(sessionID string) (*GameRecorder, error) {
 	return r, nil
 }
 
+// NewMemoryRecorder creates a recorder that keeps every step in memory,
+// for games generated offline (see Steps)
+func NewMemoryRecorder() *GameRecorder {
+	return &GameRecorder{}
+}
+
+// Steps returns what a memory recorder has recorded so far
+func (r *GameRecorder) Steps() []StepRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]StepRecord(nil), r.steps...)
+}
+
 // RecordStep queues a record to be written. Non-blocking (drops if full).
 func (r *GameRecorder) RecordStep(rec StepRecord) {
 	r.mu.Lock()
@@ -58,6 +79,11 @@ func (r *GameRecorder) RecordStep(rec StepRecord) {
 		r.mu.Unlock()
 		return
 	}
+	if r.recordChan == nil {
+		r.steps = append(r.steps, rec)
+		r.mu.Unlock()
+		return
+	}
 	r.mu.Unlock()
 
 	select {
@@ -78,6 +104,9 @@ func (r *GameRecorder) Close() {
 	}
 	r.closed = true
 	r.mu.Unlock()
+	if r.recordChan == nil {
+		return
+	}
 
 	close(r.recordChan)
 	r.wg.Wait() // Wait for writeLoop to finish
@@ -96,3 +125,72 @@ func (r *GameRecorder) writeLoop() {
 	}
 	r.writer.Flush()
 }
+
+// Record queues one step for every player: the action each took since the
+// previous record, its reward events, one reward per preset, its controller,
+// model and AI reasoning. The first terminal step ends the recording; later
+// calls are ignored, so a game over is never counted twice.
+func (r *GameRecorder) Record(g *Game, state GameState) {
+	r.mu.Lock()
+	if r.done {
+		r.mu.Unlock()
+		return
+	}
+	r.done = g.GameOver
+	state.AIDebug = nil // Players carry their own AIContext below
+	for len(r.lastTally) < len(g.Players) {
+		r.lastTally = append(r.lastTally, RewardTally{})
+		r.lastMoves = append(r.lastMoves, 0)
+	}
+
+	players := make([]PlayerStep, len(g.Players))
+	for i, p := range g.Players {
+		events := g.RewardEvents(i, r.lastTally[i])
+		rewards := RewardChannels(events)
+		step := PlayerStep{
+			Index:      i,
+			Name:       p.Name,
+			Controller: p.Controller,
+			Action: ActionData{
+				Direction: p.LastMoveDir,
+				Boost:     p.Boosting,
+				Fire:      p.LastFireTime.After(r.lastRecord),
+			},
+			Reward:  rewards["default"],
+			Rewards: rewards,
+			Events:  &events,
+			Moved:   p.Moves != r.lastMoves[i],
+			Model:   p.LastModel,
+		}
+		if p.hasAIContext() {
+			ctx := p.LastContext
+			step.AIContext = &ctx
+		}
+		players[i] = step
+		r.lastTally[i] = p.Tally()
+		r.lastMoves[i] = p.Moves
+	}
+	r.lastRecord = g.Now()
+
+	rec := StepRecord{
+		StepID:    r.stepID,
+		Timestamp: time.Now().UnixMilli(),
+		State:     state,
+		AIContext: g.CurrentAIContext,
+		Done:      g.GameOver,
+		Players:   players,
+	}
+	if len(players) > 0 {
+		rec.Action = players[0].Action
+		rec.Model = players[0].Model
+		rec.Reward = players[0].Reward
+		rec.Rewards = players[0].Rewards
+	}
+	if len(players) > 1 {
+		rec.AIModel = players[1].Model
+	}
+	r.stepID++
+	r.mu.Unlock()
+
+	r.RecordStep(rec)
+}
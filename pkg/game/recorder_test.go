@@ -0,0 +1,58 @@
+package game
+
+import "testing"
+
+// TestRecordAllPlayers checks each player gets its own action, reward and
+// controller, and that nothing is recorded after the game ends
+func TestRecordAllPlayers(t *testing.T) {
+	g := observationGame()
+	g.Players[0].Controller = "manual"
+	g.Players[1].Controller = "heuristic"
+	g.Players[1].LastContext = AIContext{Intent: IntentHunt}
+	r := &GameRecorder{recordChan: make(chan StepRecord, 4)}
+
+	// P1 moves and scores, the opponent does not move
+	g.Players[0].LastMoveDir, g.Players[0].Moves, g.Players[0].Score = Point{X: 1}, 1, 10
+	r.Record(g, g.GetGameStateSnapshot(true, false, "mid"))
+	rec := <-r.recordChan
+	if len(rec.Players) != 2 {
+		t.Fatalf("expected 2 player steps, got %d", len(rec.Players))
+	}
+	p1, ai := rec.Players[0], rec.Players[1]
+	if !p1.Moved || p1.Reward != 10.1 || p1.AIContext != nil || rec.Action != p1.Action {
+		t.Errorf("unexpected P1 step %+v", p1)
+	}
+	if ai.Moved || ai.Controller != "heuristic" || ai.AIContext == nil || ai.AIContext.Intent != IntentHunt {
+		t.Errorf("unexpected opponent step %+v", ai)
+	}
+
+	// The opponent wins: only P1 is penalised
+	g.Players[1].LastMoveDir, g.Players[1].Moves = Point{Y: -1}, 1
+	g.GameOver, g.Winner = true, "ai"
+	r.Record(g, g.GetGameStateSnapshot(true, false, "mid"))
+	rec = <-r.recordChan
+	if rec.StepID != 1 || !rec.Done || rec.Players[0].Reward != -100 || rec.Players[1].Reward != 0 || !rec.Players[1].Moved {
+		t.Errorf("unexpected final record %+v", rec.Players)
+	}
+
+	r.Record(g, g.GetGameStateSnapshot(true, false, "mid"))
+	if len(r.recordChan) != 0 {
+		t.Error("a finished game should not be recorded twice")
+	}
+}
+
+// TestMemoryRecorder checks steps are kept in order and Close is safe
+func TestMemoryRecorder(t *testing.T) {
+	g := observationGame()
+	r := NewMemoryRecorder()
+	r.Record(g, g.GetGameStateSnapshot(true, false, ""))
+	g.GameOver, g.Winner = true, "player"
+	r.Record(g, g.GetGameStateSnapshot(true, false, ""))
+	r.Close()
+	r.Record(g, g.GetGameStateSnapshot(true, false, ""))
+
+	steps := r.Steps()
+	if len(steps) != 2 || steps[0].StepID != 0 || !steps[1].Done {
+		t.Errorf("expected two steps ending the game, got %+v", steps)
+	}
+}
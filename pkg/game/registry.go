@@ -0,0 +1,97 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ControllerFactory creates a fresh controller. Controllers may keep
+// per-player state, so every player needs its own instance.
+type ControllerFactory func() Controller
+
+var (
+	registryMu         sync.RWMutex
+	controllerRegistry = map[string]ControllerFactory{
+		"heuristic": func() Controller { return &HeuristicController{} },
+		"neural":    func() Controller { return &NeuralController{} },
+		"mcts":      func() Controller { return NewMCTSController() },
+		"berserker": func() Controller { return &BerserkerController{} },
+	}
+)
+
+// RegisterController makes a controller available by name to tools such as
+// the arena. Registering an existing name replaces it.
+func RegisterController(name string, factory ControllerFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	controllerRegistry[name] = factory
+}
+
+// NewController creates a registered controller by name.
+// "neural:<model>" plays a specific model from the model registry.
+func NewController(name string) (Controller, error) {
+	if model, ok := strings.CutPrefix(name, "neural:"); ok {
+		return &NeuralController{Model: model}, nil
+	}
+
+	registryMu.RLock()
+	factory, ok := controllerRegistry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown controller %q (available: %v)", name, ControllerNames())
+	}
+	return factory(), nil
+}
+
+// ReleaseController frees what a controller holds once its game is over, for
+// controllers that hold anything (such as WASM instances)
+func ReleaseController(c Controller) {
+	if r, ok := c.(interface{ Release() }); ok {
+		r.Release()
+	}
+}
+
+// ReleaseControllers releases the brain of every player. Call it when the
+// game is over or replaced.
+func (g *Game) ReleaseControllers() {
+	for _, p := range g.Players {
+		if p.Brain != nil {
+			ReleaseController(p.Brain)
+		}
+	}
+}
+
+// ControllerNames lists the registered controllers in alphabetical order
+func ControllerNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(controllerRegistry))
+	for name := range controllerRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// --- Implementation: Berserker Controller ---
+
+// BerserkerController plays the heuristic policy as if Berserker Mode were
+// on for this player only: it boosts to close gaps and dares head-on contests.
+type BerserkerController struct {
+	policy HeuristicController
+}
+
+func (c *BerserkerController) GetAction(g *Game, playerIdx int) ActionData {
+	if playerIdx < len(g.Players) {
+		g.Players[playerIdx].Berserker = true
+	}
+	return c.policy.GetAction(g, playerIdx)
+}
+
+// isBerserker reports whether a player plays aggressively, either through
+// the game-wide Berserker Mode or its own berserker brain
+func (g *Game) isBerserker(idx int) bool {
+	return g.BerserkerMode || (idx < len(g.Players) && g.Players[idx].Berserker)
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// TestControllerRegistry checks built-in names resolve and unknown ones fail
+func TestControllerRegistry(t *testing.T) {
+	for _, name := range []string{"heuristic", "neural", "mcts", "berserker"} {
+		c, err := NewController(name)
+		if err != nil || c == nil {
+			t.Errorf("expected controller %q to be registered: %v", name, err)
+		}
+	}
+	if _, err := NewController("nope"); err == nil {
+		t.Error("expected an error for an unknown controller")
+	}
+
+	RegisterController("test-idle", func() Controller { return &ManualController{} })
+	if _, err := NewController("test-idle"); err != nil {
+		t.Errorf("expected registered controller to resolve: %v", err)
+	}
+}
+
+// TestSeededGameIsReproducible plays the same seeded duel twice on a virtual clock
+func TestSeededGameIsReproducible(t *testing.T) {
+	play := func() (int, int, string) {
+		g := NewSeededGame(config.StandardWidth, config.StandardHeight, 42)
+		g.Headless = true
+		g.SetupPVP("a", "b")
+		g.Players[0].Brain = &HeuristicController{}
+		g.Players[1].Brain = &BerserkerController{}
+		sim := NewSimulator(g, "mid")
+		for ticks := 0; !g.GameOver && ticks < 5000; ticks++ {
+			sim.Tick()
+		}
+		return g.Players[0].Score, g.Players[1].Score, g.Winner
+	}
+
+	a1, b1, w1 := play()
+	a2, b2, w2 := play()
+	if a1 != a2 || b1 != b2 || w1 != w2 {
+		t.Errorf("seeded games diverged: (%d, %d, %s) vs (%d, %d, %s)", a1, b1, w1, a2, b2, w2)
+	}
+}
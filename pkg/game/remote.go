@@ -0,0 +1,135 @@
+package game
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// BotRequest asks a remote bot for the next move of player You. State is the
+// full board as the web client sees it: "snake" is player 0, "aiSnake"
+// player 1 and "opponents" the rest.
+type BotRequest struct {
+	Tick       int       `json:"tick"`
+	You        int       `json:"you"`
+	DeadlineMs int       `json:"deadline_ms"` // Time until the move the reply is for, after which the fallback move is used
+	State      GameState `json:"state"`
+}
+
+// BotReply is a remote bot's answer to the request with the same tick
+type BotReply struct {
+	Tick   int        `json:"tick"`
+	Action ActionData `json:"action"`
+}
+
+// RemoteController forwards move decisions to a program outside the server,
+// such as a bot connected over the bot API. Every move sends the current
+// state and plays the bot's reply to the previous move's request, so the bot
+// has one move interval to answer and the game never waits for it. A move
+// whose request got no reply by then, or could not be sent, uses the
+// Fallback controller's move. Send must not block.
+type RemoteController struct {
+	Send     func(BotRequest) error
+	Timeout  time.Duration // Reply time offered before the move interval is known
+	Fallback Controller
+
+	replies  chan BotReply
+	tick     int  // Tick of the last request sent
+	asked    bool // The last request was sent in the current game
+	lastMove time.Time
+	answered atomic.Int64
+	missed   atomic.Int64
+}
+
+// NewRemoteController creates a controller that sends its requests with send
+// and falls back to the heuristic AI
+func NewRemoteController(send func(BotRequest) error) *RemoteController {
+	return &RemoteController{
+		Send:     send,
+		Timeout:  config.BotMoveTimeout,
+		Fallback: &HeuristicController{},
+		replies:  make(chan BotReply, 8),
+	}
+}
+
+// Reply delivers a bot's answer. It never blocks; a bot flooding replies only
+// loses the extra ones.
+func (c *RemoteController) Reply(r BotReply) {
+	select {
+	case c.replies <- r:
+	default:
+	}
+}
+
+// Stats returns how many moves the bot answered in time and how many fell back
+func (c *RemoteController) Stats() (answered, missed int) {
+	return int(c.answered.Load()), int(c.missed.Load())
+}
+
+func (c *RemoteController) GetAction(g *Game, playerIdx int) ActionData {
+	action, ok := c.latest()
+	switch {
+	case ok && c.asked:
+		c.answered.Add(1)
+		if !isActionDirection(action.Direction) {
+			action.Direction = Point{} // Keep heading rather than trust an arbitrary vector
+		}
+	case c.asked:
+		c.missed.Add(1)
+		action = c.Fallback.GetAction(g, playerIdx)
+	default:
+		// First move of a game: the bot has not seen the board yet
+		action = c.Fallback.GetAction(g, playerIdx)
+	}
+
+	// The bot has until the next move, as far as the last interval tells
+	now := time.Now()
+	deadline := c.Timeout
+	if !c.lastMove.IsZero() {
+		deadline = now.Sub(c.lastMove)
+	}
+	c.lastMove = now
+	c.tick++
+	c.asked = true
+	c.Send(BotRequest{
+		Tick:       c.tick,
+		You:        playerIdx,
+		DeadlineMs: int(deadline / time.Millisecond),
+		State:      g.GetGameStateSnapshot(true, g.Players[playerIdx].Boosting, ""),
+	})
+	return action
+}
+
+// NewGame starts over for the next game, where a reply to the last request
+// of the previous one is not played
+func (c *RemoteController) NewGame() {
+	c.asked = false
+	c.lastMove = time.Time{}
+}
+
+// latest returns the reply to the last request if it has arrived, dropping
+// late replies to earlier ones. It never waits.
+func (c *RemoteController) latest() (ActionData, bool) {
+	var action ActionData
+	found := false
+	for {
+		select {
+		case r := <-c.replies:
+			if r.Tick == c.tick {
+				action, found = r.Action, true
+			}
+		default:
+			return action, found
+		}
+	}
+}
+
+func isActionDirection(d Point) bool {
+	for _, a := range ActionDirections {
+		if d == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRemoteController checks each move plays the reply to the previous
+// request without waiting, and that a silent, broken or misbehaving bot
+// falls back to a safe move
+func TestRemoteController(t *testing.T) {
+	g := observationGame()
+	var reply func(BotRequest)
+	var sent []BotRequest
+	c := NewRemoteController(func(req BotRequest) error {
+		if req.You != 0 || len(req.State.Snake) != 3 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		sent = append(sent, req)
+		if reply != nil {
+			reply(req)
+		}
+		return nil
+	})
+	c.Timeout = time.Second
+	c.Fallback = &ManualController{PendingAction: ActionData{Direction: Point{Y: 1}}}
+
+	// The first move has nothing to play yet and asks for the next one.
+	// A late reply to an earlier tick is ignored in favour of the current one.
+	reply = func(req BotRequest) {
+		c.Reply(BotReply{Tick: req.Tick - 1, Action: ActionData{Direction: Point{X: -1}}})
+		c.Reply(BotReply{Tick: req.Tick, Action: ActionData{Direction: Point{Y: -1}, Fire: true}})
+	}
+	if a := c.GetAction(g, 0); a.Direction != (Point{Y: 1}) || len(sent) != 1 || sent[0].DeadlineMs != 1000 {
+		t.Fatalf("expected the fallback and one request on the first move, got %+v and %+v", a, sent)
+	}
+	if a := c.GetAction(g, 0); a.Direction != (Point{Y: -1}) || !a.Fire {
+		t.Errorf("expected the bot's move, got %+v", a)
+	}
+
+	// Anything but a unit direction keeps the current heading
+	reply = func(req BotRequest) { c.Reply(BotReply{Tick: req.Tick, Action: ActionData{Direction: Point{X: 5}}}) }
+	c.GetAction(g, 0) // Plays the reply above and asks again
+	reply = nil
+	if a := c.GetAction(g, 0); a.Direction != (Point{}) {
+		t.Errorf("expected an invalid direction to be dropped, got %+v", a)
+	}
+
+	// A silent bot costs the game nothing
+	start := time.Now()
+	if a := c.GetAction(g, 0); a.Direction != (Point{Y: 1}) {
+		t.Errorf("expected the fallback move without a reply, got %+v", a)
+	}
+	if time.Since(start) > c.Timeout/2 {
+		t.Errorf("a move should never wait for the bot")
+	}
+	c.Send = func(BotRequest) error { return errors.New("connection closed") }
+	c.GetAction(g, 0)
+	if a := c.GetAction(g, 0); a.Direction != (Point{Y: 1}) {
+		t.Errorf("expected the fallback move when sending fails, got %+v", a)
+	}
+
+	if answered, missed := c.Stats(); answered != 3 || missed != 3 {
+		t.Errorf("expected 3 answered and 3 missed, got %d and %d", answered, missed)
+	}
+
+	// A reply to the last move of a game is not played in the next one
+	c.Send = func(req BotRequest) error {
+		c.Reply(BotReply{Tick: req.Tick, Action: ActionData{Direction: Point{X: -1}}})
+		return nil
+	}
+	c.GetAction(g, 0)
+	c.NewGame()
+	if a := c.GetAction(g, 0); a.Direction != (Point{Y: 1}) {
+		t.Errorf("expected the fallback on the first move of a new game, got %+v", a)
+	}
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// RewardEvents is what happened to one player between two steps. Recordings
+// store them with every step, so any RewardWeights can be applied later
+// without re-recording games.
+type RewardEvents struct {
+	Score      int  `json:"score"`       // Points gained
+	HitsLanded int  `json:"hits_landed"` // Fireballs that hit another snake
+	HitsTaken  int  `json:"hits_taken"`  // Fireballs of other snakes that hit this one
+	Stunned    int  `json:"stunned"`     // Headshots taken
+	Props      int  `json:"props"`       // Props picked up
+	Alive      bool `json:"alive"`       // The game is still running
+	Won        bool `json:"won"`
+	Lost       bool `json:"lost"` // Game over without winning, draws included
+}
+
+// RewardWeights turns RewardEvents into a scalar reward
+type RewardWeights struct {
+	Score     float64 `json:"score"`      // Per point gained
+	Survival  float64 `json:"survival"`   // Per step while the game runs
+	Death     float64 `json:"death"`      // Once, when the game is lost
+	Win       float64 `json:"win"`        // Once, when the game is won
+	HitLanded float64 `json:"hit_landed"` // Per fireball hit on another snake
+	HitTaken  float64 `json:"hit_taken"`  // Per fireball hit taken
+	Stunned   float64 `json:"stunned"`    // Per headshot taken
+	Prop      float64 `json:"prop"`       // Per prop picked up
+}
+
+// Reward weighs the events of one step
+func (w RewardWeights) Reward(e RewardEvents) float64 {
+	r := w.Score*float64(e.Score) +
+		w.HitLanded*float64(e.HitsLanded) +
+		w.HitTaken*float64(e.HitsTaken) +
+		w.Stunned*float64(e.Stunned) +
+		w.Prop*float64(e.Props)
+	switch {
+	case e.Alive:
+		r += w.Survival
+	case e.Won:
+		r += w.Win
+	case e.Lost:
+		r += w.Death
+	}
+	return r
+}
+
+// DefaultReward is the original shaping: points gained, -100 for losing and
+// a small survival bonus
+var DefaultReward = RewardWeights{Score: 1, Survival: 0.1, Death: -100}
+
+// RewardPresets holds the named reward functions. Every recorded step
+// carries one reward channel per preset.
+var RewardPresets = map[string]RewardWeights{
+	"default": DefaultReward,
+	"combat": {
+		Score: 1, Survival: 0.1, Death: -100, Win: 50,
+		HitLanded: 5, HitTaken: -5, Stunned: -20,
+	},
+	"collector": {
+		Score: 1, Survival: 0.1, Death: -100,
+		Prop: 10, HitTaken: -2,
+	},
+	"survival": {
+		Score: 0.2, Survival: 1, Death: -200,
+		HitTaken: -5, Stunned: -10,
+	},
+	"sparse": {Death: -1, Win: 1},
+}
+
+// RewardPresetNames returns the preset names in a stable order
+func RewardPresetNames() []string {
+	names := make([]string, 0, len(RewardPresets))
+	for name := range RewardPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RewardPreset returns a preset by name; "" means default
+func RewardPreset(name string) (RewardWeights, error) {
+	if name == "" {
+		name = "default"
+	}
+	w, ok := RewardPresets[name]
+	if !ok {
+		return RewardWeights{}, fmt.Errorf("unknown reward preset %q", name)
+	}
+	return w, nil
+}
+
+// LoadRewardPresets reads {"name": {weights}} from a JSON file and adds the
+// presets, replacing built-in ones of the same name. Omitted weights are 0.
+func LoadRewardPresets(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	presets := map[string]RewardWeights{}
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for name, w := range presets {
+		RewardPresets[name] = w
+	}
+	return nil
+}
+
+// RewardTally is a player's running totals that RewardEvents are measured against
+type RewardTally struct {
+	Score      int
+	HitsLanded int
+	HitsTaken  int
+	Stunned    int
+	Props      int
+}
+
+// Tally returns the player's current totals
+func (p *Player) Tally() RewardTally {
+	return RewardTally{
+		Score:      p.Score,
+		HitsLanded: p.HitsLanded,
+		HitsTaken:  p.HitsTaken,
+		Stunned:    p.TimesStunned,
+		Props:      p.PropsCollected,
+	}
+}
+
+// RewardEvents returns what happened to player idx since the tally was taken
+func (g *Game) RewardEvents(idx int, since RewardTally) RewardEvents {
+	if idx >= len(g.Players) {
+		return RewardEvents{}
+	}
+	now := g.Players[idx].Tally()
+	won := g.GameOver && g.wonBy(idx)
+	return RewardEvents{
+		Score:      now.Score - since.Score,
+		HitsLanded: now.HitsLanded - since.HitsLanded,
+		HitsTaken:  now.HitsTaken - since.HitsTaken,
+		Stunned:    now.Stunned - since.Stunned,
+		Props:      now.Props - since.Props,
+		Alive:      !g.GameOver,
+		Won:        won,
+		Lost:       g.GameOver && !won,
+	}
+}
+
+// RewardChannels applies every preset to the events of one step
+func RewardChannels(e RewardEvents) map[string]float64 {
+	out := make(map[string]float64, len(RewardPresets))
+	for name, w := range RewardPresets {
+		out[name] = w.Reward(e)
+	}
+	return out
+}
+
+// wonBy reports whether player idx won a finished game. Winner only names
+// "player" (P1) or "ai" (anyone else), so with several AI snakes the one
+// placed first takes it.
+func (g *Game) wonBy(idx int) bool {
+	switch {
+	case idx == 0:
+		return g.Winner == "player"
+	case g.Winner != "ai":
+		return false
+	case len(g.Players) <= 2:
+		return true
+	default:
+		return g.Placements()[idx] == 1
+	}
+}
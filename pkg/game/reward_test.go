@@ -0,0 +1,61 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRewardEvents checks a headshot is counted for both sides and weighed per preset
+func TestRewardEvents(t *testing.T) {
+	g := observationGame()
+	shooter, target := g.Players[0].Tally(), g.Players[1].Tally()
+	g.Fireballs = []*Fireball{{Pos: Point{X: 5, Y: 4}, Dir: Point{Y: 1}, Owner: "player", OwnerIdx: 0}}
+	g.UpdateFireballs()
+
+	got := g.RewardEvents(0, shooter)
+	if got != (RewardEvents{Score: 50, HitsLanded: 1, Alive: true}) {
+		t.Errorf("unexpected shooter events %+v", got)
+	}
+	hit := g.RewardEvents(1, target)
+	if hit != (RewardEvents{HitsTaken: 1, Stunned: 1, Alive: true}) {
+		t.Errorf("unexpected target events %+v", hit)
+	}
+
+	if r := DefaultReward.Reward(got); r != 50.1 {
+		t.Errorf("default reward %v, want 50.1", r)
+	}
+	combat := RewardPresets["combat"]
+	if r := combat.Reward(hit); r != -24.9 {
+		t.Errorf("combat reward for the target %v, want -24.9", r)
+	}
+
+	g.GameOver, g.Winner = true, "player"
+	if r := RewardPresets["sparse"].Reward(g.RewardEvents(1, g.Players[1].Tally())); r != -1 {
+		t.Errorf("sparse reward for the loser %v, want -1", r)
+	}
+}
+
+// TestLoadRewardPresets checks presets from a config file are added and override built-ins
+func TestLoadRewardPresets(t *testing.T) {
+	saved := make(map[string]RewardWeights, len(RewardPresets))
+	for k, v := range RewardPresets {
+		saved[k] = v
+	}
+	defer func() { RewardPresets = saved }()
+
+	path := filepath.Join(t.TempDir(), "rewards.json")
+	os.WriteFile(path, []byte(`{"greedy": {"score": 2, "prop": 3}, "sparse": {"win": 10}}`), 0644)
+	if err := LoadRewardPresets(path); err != nil {
+		t.Fatal(err)
+	}
+	if w, err := RewardPreset("greedy"); err != nil || w != (RewardWeights{Score: 2, Prop: 3}) {
+		t.Errorf("unexpected greedy preset %+v (%v)", w, err)
+	}
+	if RewardPresets["sparse"].Win != 10 {
+		t.Error("a preset from the file should replace the built-in one")
+	}
+	if _, err := RewardPreset("missing"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource makes a rand.Source safe for concurrent use, so that games
+// without their own generator can share one across goroutines
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+var sharedRand = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano()).(rand.Source64)})
+
+// rng returns the game's random generator: its own when seeded, the shared one otherwise
+func (g *Game) rng() *rand.Rand {
+	if g.Rand != nil {
+		return g.Rand
+	}
+	return sharedRand
+}
+
+// NewSeededGame creates a game whose food, props, obstacles and AI choices
+// all come from one seeded generator. Together with a SimClock this makes
+// headless games reproducible.
+func NewSeededGame(width, height int, seed int64) *Game {
+	return newGame(width, height, rand.New(rand.NewSource(seed)))
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// Room limits
+const (
+	MaxRoomSeats    = 8
+	MinRoomDuration = 30 * time.Second
+	MaxRoomDuration = 5 * time.Minute
+)
+
+// RoomSettings are the rules a room host picks for its matches
+type RoomSettings struct {
+	Size     string        `json:"size"`     // "standard" or "large"
+	Duration time.Duration `json:"duration"` // Match length
+	Mode     string        `json:"mode"`     // "battle" (crashed snakes respawn, highest score wins) or "pvp" (first crash loses)
+	Props    bool          `json:"props"`
+	Seats    int           `json:"seats"`  // Snakes on the board, 2..MaxRoomSeats
+	AIFill   bool          `json:"aiFill"` // Seats no one took are played by the AI
+}
+
+// DefaultRoomSettings returns the settings a new room starts with
+func DefaultRoomSettings() RoomSettings {
+	return RoomSettings{
+		Size:     "standard",
+		Duration: 90 * time.Second,
+		Mode:     "battle",
+		Props:    true,
+		Seats:    4,
+		AIFill:   true,
+	}
+}
+
+// Validate reports the first setting a room cannot be played with
+func (s RoomSettings) Validate() error {
+	switch {
+	case s.Size != "standard" && s.Size != "large":
+		return fmt.Errorf("unknown board size %q", s.Size)
+	case s.Mode != "battle" && s.Mode != "pvp":
+		return fmt.Errorf("unknown room mode %q", s.Mode)
+	case s.Seats < 2 || s.Seats > MaxRoomSeats:
+		return fmt.Errorf("a room has 2 to %d seats", MaxRoomSeats)
+	case s.Mode == "pvp" && s.Seats != 2:
+		return fmt.Errorf("pvp rooms are one on one")
+	case s.Duration < MinRoomDuration || s.Duration > MaxRoomDuration:
+		return fmt.Errorf("matches last %v to %v", MinRoomDuration, MaxRoomDuration)
+	}
+	return nil
+}
+
+// Dimensions returns the board size of the room's matches
+func (s RoomSettings) Dimensions() (int, int) {
+	if s.Size == "large" {
+		return config.LargeWidth, config.LargeHeight
+	}
+	return config.StandardWidth, config.StandardHeight
+}
+
+// roomSpawn returns the spawn point and heading of room seat n. The first
+// two match SetupPVP so one on one rooms start like matchmaking games.
+func (g *Game) roomSpawn(n int) (Point, Point) {
+	up, down, left, right := Point{X: 0, Y: -1}, Point{X: 0, Y: 1}, Point{X: -1, Y: 0}, Point{X: 1, Y: 0}
+	w, h := g.Width, g.Height
+	spawns := []struct{ pos, dir Point }{
+		{Point{X: w / 4, Y: h / 3}, right},
+		{Point{X: (w * 3) / 4, Y: (h * 2) / 3}, left},
+		{Point{X: (w * 3) / 4, Y: h / 3}, down},
+		{Point{X: w / 4, Y: (h * 2) / 3}, up},
+		{Point{X: w / 2, Y: 2}, down},
+		{Point{X: w / 2, Y: h - 3}, up},
+		{Point{X: 2, Y: h / 2}, right},
+		{Point{X: w - 3, Y: h / 2}, left},
+	}
+	s := spawns[n%len(spawns)]
+	return s.pos, s.dir
+}
+
+// SetupRoom seats the named players, then fills the free seats with AI
+// snakes if the settings ask for it. Every seat is a full PVP participant.
+func (g *Game) SetupRoom(s RoomSettings, names []string) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if len(names) > s.Seats {
+		return fmt.Errorf("%d players do not fit in %d seats", len(names), s.Seats)
+	}
+	seats := len(names)
+	if s.AIFill {
+		seats = s.Seats
+	}
+	if seats < 2 {
+		return fmt.Errorf("a match needs at least 2 snakes")
+	}
+
+	g.Mode = s.Mode
+	g.IsPVP = true
+	g.Respawn = s.Mode == "battle"
+	g.NoProps = !s.Props
+	g.Duration = s.Duration
+	g.ReleaseControllers()
+	g.Players = nil
+	for i := 0; i < seats; i++ {
+		pos, dir := g.roomSpawn(i)
+		p := &Player{
+			Snake:       []Point{pos},
+			Direction:   dir,
+			LastMoveDir: dir,
+			Spawn:       pos,
+			SpawnDir:    dir,
+		}
+		if i < len(names) {
+			p.Name = names[i]
+			p.Brain, p.Controller = &ManualController{}, "manual"
+		} else {
+			p.Name = fmt.Sprintf("AI %d", i-len(names)+1)
+			p.Brain, p.Controller = g.defaultAIBrain()
+			if i%2 == 1 {
+				p.Brain, p.Controller = &HeuristicController{}, "heuristic"
+			}
+			p.Profile = ProfileNormal
+		}
+		g.Players = append(g.Players, p)
+	}
+	return nil
+}
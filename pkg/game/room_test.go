@@ -0,0 +1,100 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRoomSettings checks the limits a room host has to stay within
+func TestRoomSettings(t *testing.T) {
+	if err := DefaultRoomSettings().Validate(); err != nil {
+		t.Fatalf("default settings rejected: %v", err)
+	}
+	for name, change := range map[string]func(*RoomSettings){
+		"size":     func(s *RoomSettings) { s.Size = "huge" },
+		"mode":     func(s *RoomSettings) { s.Mode = "zen" },
+		"seats":    func(s *RoomSettings) { s.Seats = MaxRoomSeats + 1 },
+		"pvp":      func(s *RoomSettings) { s.Mode = "pvp"; s.Seats = 3 },
+		"duration": func(s *RoomSettings) { s.Duration = time.Second },
+	} {
+		s := DefaultRoomSettings()
+		change(&s)
+		if s.Validate() == nil {
+			t.Errorf("expected bad %s to be rejected", name)
+		}
+	}
+}
+
+// TestSetupRoom seats players, fills with AI and plays by the room's rules
+func TestSetupRoom(t *testing.T) {
+	s := DefaultRoomSettings()
+	s.Seats = MaxRoomSeats
+	s.Props = false
+	g := NewSeededGame(25, 25, 1)
+	if err := g.SetupRoom(s, []string{"ann", "bob", "cat"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Players) != MaxRoomSeats || g.Players[2].Name != "cat" || g.Players[2].Controller != "manual" || g.Players[3].Controller == "manual" {
+		t.Fatalf("unexpected seating %+v", g.Players)
+	}
+	heads := map[Point]bool{}
+	for _, p := range g.Players {
+		h := p.Snake[0]
+		if heads[h] || h.X <= 0 || h.Y <= 0 || h.X >= g.Width-1 || h.Y >= g.Height-1 {
+			t.Errorf("%s spawns on a taken tile %v", p.Name, h)
+		}
+		heads[p.Snake[0]] = true
+	}
+	if g.GameDuration() != s.Duration || g.GetGameConfig().GameDuration != 90 {
+		t.Errorf("expected a %v match, got %v", s.Duration, g.GameDuration())
+	}
+
+	// Props are off
+	g.LastPropSpawn = time.Time{}
+	for i := 0; i < 200; i++ {
+		g.TrySpawnProp()
+	}
+	if len(g.Props) != 0 {
+		t.Errorf("expected no props, got %d", len(g.Props))
+	}
+
+	// Crashing in a battle room respawns instead of ending the match
+	g.Players[0].Direction = Point{X: 0, Y: -1}
+	g.Players[0].LastMoveDir = g.Players[0].Direction
+	g.Players[0].Snake = []Point{{X: 5, Y: 1}}
+	g.UpdatePlayer(0)
+	if g.GameOver || g.Players[0].Deaths != 1 || g.Players[0].Snake[0] != g.Players[0].Spawn {
+		t.Errorf("expected a respawn, got game over %v at %v", g.GameOver, g.Players[0].Snake)
+	}
+
+	// Without AI fill only the players get seats
+	s.AIFill = false
+	if err := g.SetupRoom(s, []string{"ann"}); err == nil {
+		t.Error("expected a lone player without AI fill to be rejected")
+	}
+}
+
+// TestResult ranks finished one on one and room games
+func TestResult(t *testing.T) {
+	g := NewSeededGame(25, 25, 1)
+	g.SetupPVP("a", "b")
+	g.Players[0].Score = 50
+	g.Winner = "ai" // P1 crashed despite leading
+	if g.Result(0) != "lost" || g.Result(1) != "won" {
+		t.Errorf("expected the crash to decide, got %s/%s", g.Result(0), g.Result(1))
+	}
+
+	if err := g.SetupRoom(DefaultRoomSettings(), []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	for i, score := range []int{10, 30, 30, 5} {
+		g.Players[i].Score = score
+	}
+	g.Winner = "ai"
+	want := []string{"lost", "draw", "draw", "lost"}
+	for i, w := range want {
+		if got := g.Result(i); got != w {
+			t.Errorf("player %d: expected %s, got %s", i, w, got)
+		}
+	}
+}
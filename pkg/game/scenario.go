@@ -0,0 +1,471 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// Curriculum scenarios are generated starting positions for the situations
+// natural games rarely produce. Each kind is drawn in a canonical layout and
+// then mirrored (and on square boards transposed) onto the board, with a
+// difficulty from 0 (easy) to 1 (hard) that scales its parameters. Player 0
+// is always the one being tested; player 1 is the opponent, and further
+// players are removed.
+
+// Scenario identifies the starting position a game was set up with
+type Scenario struct {
+	Name       string  `json:"name"`
+	Difficulty float64 `json:"difficulty"` // 0 (easy) to 1 (hard)
+}
+
+// ScenarioKind is one family of generated situations
+type ScenarioKind struct {
+	Name        string
+	Description string
+	build       func(b *scenarioBuilder) error
+}
+
+// ScenarioKinds lists the scenarios in a stable order
+var ScenarioKinds = []ScenarioKind{
+	{"wall_hug", "Long coiled snake heading into a wall; the coil blocks one way out", buildWallHug},
+	{"food_behind_obstacle", "Food behind a wall, cupped on three sides at high difficulty", buildFoodBehindObstacle},
+	{"head_on", "Enemy approaching head-on along a wall, food between the heads", buildHeadOn},
+	{"incoming_fireball", "Fireball flying at the snake's head", buildIncomingFireball},
+	{"corridor", "Narrow corridor with food baited in a dead-end side pocket", buildCorridor},
+}
+
+// ScenarioNames lists the scenario kinds
+func ScenarioNames() []string {
+	names := make([]string, len(ScenarioKinds))
+	for i, k := range ScenarioKinds {
+		names[i] = k.Name
+	}
+	return names
+}
+
+func scenarioKind(name string) (ScenarioKind, bool) {
+	for _, k := range ScenarioKinds {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return ScenarioKind{}, false
+}
+
+// Curriculum is the set of scenarios and difficulties episodes are drawn from
+type Curriculum struct {
+	Scenarios     []string // Kinds drawn uniformly
+	MinDifficulty float64
+	MaxDifficulty float64
+}
+
+// ParseCurriculum reads "all" or comma-separated scenario names, optionally
+// followed by a difficulty range: "head_on,corridor@0.5-1". An empty spec
+// means no curriculum and returns nil.
+func ParseCurriculum(spec string) (*Curriculum, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	c := &Curriculum{MaxDifficulty: 1}
+	names, levels, ranged := strings.Cut(spec, "@")
+	if ranged {
+		lo, hi, ok := strings.Cut(levels, "-")
+		min, err1 := strconv.ParseFloat(lo, 64)
+		max, err2 := strconv.ParseFloat(hi, 64)
+		if !ok || err1 != nil || err2 != nil || min < 0 || max > 1 || min > max {
+			return nil, fmt.Errorf("invalid difficulty range %q (want min-max within 0-1)", levels)
+		}
+		c.MinDifficulty, c.MaxDifficulty = min, max
+	}
+	if names == "all" {
+		c.Scenarios = ScenarioNames()
+		return c, nil
+	}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := scenarioKind(name); !ok {
+			return nil, fmt.Errorf("unknown scenario %q (available: %s)", name, strings.Join(ScenarioNames(), ", "))
+		}
+		c.Scenarios = append(c.Scenarios, name)
+	}
+	return c, nil
+}
+
+// String returns the curriculum in ParseCurriculum's format
+func (c *Curriculum) String() string {
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s@%g-%g", strings.Join(c.Scenarios, ","), c.MinDifficulty, c.MaxDifficulty)
+}
+
+// Apply draws a scenario and difficulty from seed and sets g up with it
+func (c *Curriculum) Apply(g *Game, seed int64) (Scenario, error) {
+	rng := rand.New(rand.NewSource(seed))
+	name := c.Scenarios[rng.Intn(len(c.Scenarios))]
+	difficulty := c.MinDifficulty + rng.Float64()*(c.MaxDifficulty-c.MinDifficulty)
+	if err := g.ApplyScenario(name, difficulty, rng.Int63()); err != nil {
+		return Scenario{}, err
+	}
+	return *g.Scenario, nil
+}
+
+// ApplyScenario clears the board and arranges it as the named scenario.
+// The same name, difficulty and seed always give the same position. If the
+// scenario does not fit the board, g is left as it was.
+func (g *Game) ApplyScenario(name string, difficulty float64, seed int64) error {
+	kind, ok := scenarioKind(name)
+	if !ok {
+		return fmt.Errorf("unknown scenario %q", name)
+	}
+	if len(g.Players) < 2 {
+		return fmt.Errorf("scenario %s needs two players", name)
+	}
+	difficulty = math.Max(0, math.Min(1, difficulty))
+
+	saved := g.saveScenarioState()
+	g.Players = g.Players[:2]
+	for _, p := range g.Players {
+		p.Snake = nil
+		p.Boosting = false
+		p.Stunned = false
+		p.StunnedUntil = time.Time{}
+		p.Effects = nil
+	}
+	now := g.Now()
+	g.Foods = nil
+	g.Props = nil
+	g.Obstacles = nil
+	g.Fireballs = nil
+	g.LastFoodSpawn, g.LastPropSpawn, g.LastObstacleSpawn = now, now, now
+
+	rng := rand.New(rand.NewSource(seed))
+	b := &scenarioBuilder{g: g, rng: rng, level: difficulty, o: orientation{
+		w: g.Width, h: g.Height,
+		transpose: g.Width == g.Height && rng.Intn(2) == 0,
+		flipX:     rng.Intn(2) == 0,
+		flipY:     rng.Intn(2) == 0,
+	}}
+	if err := kind.build(b); err != nil {
+		saved.restore()
+		return err
+	}
+	if len(g.Players[1].Snake) == 0 && !b.parkOpponent() {
+		saved.restore()
+		return fmt.Errorf("no room for the opponent in %s", name)
+	}
+	for _, p := range saved.players[2:] {
+		ReleaseController(p.Brain)
+	}
+	if len(g.Foods) == 0 {
+		g.spawnOneFood()
+	}
+	g.Scenario = &Scenario{Name: name, Difficulty: difficulty}
+	return nil
+}
+
+// scenarioState is what ApplyScenario changes, kept to undo a failed build
+type scenarioState struct {
+	g         *Game
+	players   []*Player
+	saved     []Player
+	foods     []Food
+	props     []Prop
+	obstacles []Obstacle
+	fireballs []*Fireball
+	spawns    [3]time.Time
+}
+
+func (g *Game) saveScenarioState() *scenarioState {
+	s := &scenarioState{
+		g:         g,
+		players:   g.Players,
+		saved:     make([]Player, len(g.Players)),
+		foods:     g.Foods,
+		props:     g.Props,
+		obstacles: g.Obstacles,
+		fireballs: g.Fireballs,
+		spawns:    [3]time.Time{g.LastFoodSpawn, g.LastPropSpawn, g.LastObstacleSpawn},
+	}
+	for i, p := range g.Players {
+		s.saved[i] = *p
+	}
+	return s
+}
+
+func (s *scenarioState) restore() {
+	g := s.g
+	g.Players = s.players
+	for i, p := range g.Players {
+		*p = s.saved[i]
+	}
+	g.Foods, g.Props, g.Obstacles, g.Fireballs = s.foods, s.props, s.obstacles, s.fireballs
+	g.LastFoodSpawn, g.LastPropSpawn, g.LastObstacleSpawn = s.spawns[0], s.spawns[1], s.spawns[2]
+}
+
+// orientation maps canonical scenario coordinates onto the board: an
+// optional transpose (square boards only) followed by mirrors
+type orientation struct {
+	w, h                    int
+	transpose, flipX, flipY bool
+}
+
+func (o orientation) point(p Point) Point {
+	if o.transpose {
+		p.X, p.Y = p.Y, p.X
+	}
+	if o.flipX {
+		p.X = o.w - 1 - p.X
+	}
+	if o.flipY {
+		p.Y = o.h - 1 - p.Y
+	}
+	return p
+}
+
+func (o orientation) dir(d Point) Point {
+	if o.transpose {
+		d.X, d.Y = d.Y, d.X
+	}
+	if o.flipX {
+		d.X = -d.X
+	}
+	if o.flipY {
+		d.Y = -d.Y
+	}
+	return d
+}
+
+// scenarioBuilder places objects given in canonical coordinates
+type scenarioBuilder struct {
+	g     *Game
+	rng   *rand.Rand
+	o     orientation
+	level float64
+}
+
+// lerp scales a parameter from its easy to its hard value
+func (b *scenarioBuilder) lerp(easy, hard int) int {
+	return easy + int(math.Round(float64(hard-easy)*b.level))
+}
+
+// between returns a random value in [lo, hi], or false if the range is empty
+func (b *scenarioBuilder) between(lo, hi int) (int, bool) {
+	if hi < lo {
+		return 0, false
+	}
+	return lo + b.rng.Intn(hi-lo+1), true
+}
+
+func (b *scenarioBuilder) snake(idx int, cells []Point, dir Point) {
+	p := b.g.Players[idx]
+	p.Snake = make([]Point, len(cells))
+	for i, c := range cells {
+		p.Snake[i] = b.o.point(c)
+	}
+	p.Direction = b.o.dir(dir)
+	p.LastMoveDir = p.Direction
+}
+
+func (b *scenarioBuilder) wall(cells ...Point) {
+	points := make([]Point, len(cells))
+	for i, c := range cells {
+		points[i] = b.o.point(c)
+	}
+	b.g.Obstacles = append(b.g.Obstacles, Obstacle{
+		Points: points, SpawnTime: b.g.Now(), Duration: config.ObstacleDuration.Seconds(), PausedTimeAtSpawn: b.g.GetTotalPausedTime(),
+	})
+}
+
+func (b *scenarioBuilder) food(pos Point, t FoodType) {
+	b.g.Foods = append(b.g.Foods, Food{Pos: b.o.point(pos), FoodType: t, SpawnTime: b.g.Now(), PausedTimeAtSpawn: b.g.GetTotalPausedTime()})
+}
+
+// bait is the food used as a lure: the best kind at high difficulty
+func (b *scenarioBuilder) bait() FoodType {
+	if b.level >= 0.5 {
+		return FoodRed
+	}
+	return FoodOrange
+}
+
+// parkOpponent puts a short opponent somewhere free, away from player 0
+func (b *scenarioBuilder) parkOpponent() bool {
+	g := b.g
+	head := g.Players[0].Snake[0]
+	for attempts := 0; attempts < 200; attempts++ {
+		dir := ActionDirections[b.rng.Intn(len(ActionDirections))]
+		pos := Point{X: 1 + b.rng.Intn(g.Width-2), Y: 1 + b.rng.Intn(g.Height-2)}
+		cells := straight(pos, dir, 3)
+		free := manhattan(pos, head) >= 8
+		for _, c := range append(cells, Point{X: pos.X + dir.X, Y: pos.Y + dir.Y}) {
+			free = free && g.isCellEmpty(c)
+		}
+		if free {
+			p := g.Players[1]
+			p.Snake = cells
+			p.Direction, p.LastMoveDir = dir, dir
+			return true
+		}
+	}
+	return false
+}
+
+// straight returns a snake of n cells with its head at head, moving in dir
+func straight(head, dir Point, n int) []Point {
+	cells := make([]Point, n)
+	for i := range cells {
+		cells[i] = Point{X: head.X - dir.X*i, Y: head.Y - dir.Y*i}
+	}
+	return cells
+}
+
+func errNoRoom(name string) error {
+	return fmt.Errorf("board too small for scenario %s", name)
+}
+
+// buildWallHug: a long snake coiled column by column beside its head, which
+// faces the top wall. The coil closes one side, the wall is closer the
+// harder it gets.
+func buildWallHug(b *scenarioBuilder) error {
+	g := b.g
+	length := b.lerp(6, 30)
+	gap := b.lerp(3, 0) // Free rows between head and wall
+	height := 4 + b.rng.Intn(3)
+	top := 1 + gap
+	if top+height > g.Height-1 {
+		return errNoRoom("wall_hug")
+	}
+	cols := (length + height - 1) / height
+	x0, ok := b.between(2, g.Width-2-cols)
+	if !ok {
+		return errNoRoom("wall_hug")
+	}
+
+	var cells []Point
+	for c := 0; len(cells) < length; c++ {
+		for r := 0; r < height && len(cells) < length; r++ {
+			y := top + r
+			if c%2 == 1 {
+				y = top + height - 1 - r
+			}
+			cells = append(cells, Point{X: x0 + c, Y: y})
+		}
+	}
+	b.snake(0, cells, Point{Y: -1})
+	return nil
+}
+
+// buildFoodBehindObstacle: food just above a wall the snake is heading up
+// into. Harder means a wider wall, side walls forming a cup that opens away
+// from the snake, a longer snake and less room to turn.
+func buildFoodBehindObstacle(b *scenarioBuilder) error {
+	g := b.g
+	half := b.lerp(1, 5)
+	sides := b.lerp(0, 3)
+	length := b.lerp(3, 10)
+	dist := b.lerp(4, 2) // Rows between wall and head
+	foodY := sides + 2
+	wallY := foodY + 1
+	headY := wallY + dist
+	cx, ok := b.between(half+2, g.Width-3-half)
+	if !ok || headY+length-1 > g.Height-2 {
+		return errNoRoom("food_behind_obstacle")
+	}
+
+	var cells []Point
+	for x := cx - half; x <= cx+half; x++ {
+		cells = append(cells, Point{X: x, Y: wallY})
+	}
+	for j := 1; j <= sides; j++ {
+		cells = append(cells, Point{X: cx - half, Y: wallY - j}, Point{X: cx + half, Y: wallY - j})
+	}
+	b.wall(cells...)
+	b.food(Point{X: cx, Y: foodY}, b.bait())
+	b.snake(0, straight(Point{X: cx, Y: headY}, Point{Y: -1}, length), Point{Y: -1})
+	return nil
+}
+
+// buildHeadOn: both snakes on the same row heading at each other with food
+// between them. Harder means closer, longer, and pressed against the wall
+// so there is only one way to swerve.
+func buildHeadOn(b *scenarioBuilder) error {
+	g := b.g
+	gap := b.lerp(10, 2) // Free cells between the heads
+	mine := b.lerp(3, 8)
+	theirs := b.lerp(3, 8)
+	row := 1 + b.lerp((g.Height-2)/2, 0)
+	x, ok := b.between(mine, g.Width-2-gap-theirs)
+	if !ok {
+		return errNoRoom("head_on")
+	}
+
+	b.snake(0, straight(Point{X: x, Y: row}, Point{X: 1}, mine), Point{X: 1})
+	b.snake(1, straight(Point{X: x + gap + 1, Y: row}, Point{X: -1}, theirs), Point{X: -1})
+	b.food(Point{X: x + 1 + gap/2, Y: row}, b.bait())
+	return nil
+}
+
+// buildIncomingFireball: an opponent's fireball flies straight at the head.
+// Harder means closer, a longer snake and a wall on one side.
+func buildIncomingFireball(b *scenarioBuilder) error {
+	g := b.g
+	dist := b.lerp(10, 4)
+	length := b.lerp(3, 10)
+	row := 1 + b.lerp((g.Height-2)/2, 0)
+	x, ok := b.between(length, g.Width-2-dist)
+	if !ok {
+		return errNoRoom("incoming_fireball")
+	}
+
+	b.snake(0, straight(Point{X: x, Y: row}, Point{X: 1}, length), Point{X: 1})
+	b.g.Fireballs = append(b.g.Fireballs, &Fireball{
+		Pos:       b.o.point(Point{X: x + dist, Y: row}),
+		Dir:       b.o.dir(Point{X: -1}),
+		SpawnTime: g.Now(),
+		Owner:     "ai",
+		OwnerIdx:  1,
+	})
+	return nil
+}
+
+// buildCorridor: the snake is inside a one-wide corridor with food in a
+// dead-end pocket off its side; entering the pocket is fatal. Harder means
+// a longer snake, the pocket closer ahead and richer bait.
+func buildCorridor(b *scenarioBuilder) error {
+	g := b.g
+	const depth = 3
+	length := b.lerp(4, 10)
+	ahead := b.lerp(4, 1) // Cells between the head and the pocket
+	row, ok1 := b.between(depth+3, g.Height-4)
+	x0, ok2 := b.between(1, g.Width-2-(length+ahead+3))
+	if !ok1 || !ok2 {
+		return errNoRoom("corridor")
+	}
+	head := Point{X: x0 + length - 1, Y: row}
+	pocket := head.X + ahead + 1
+	end := pocket + 3
+
+	var cells []Point
+	for x := x0; x <= end; x++ {
+		if x != pocket {
+			cells = append(cells, Point{X: x, Y: row - 1})
+		}
+		cells = append(cells, Point{X: x, Y: row + 1})
+	}
+	for y := row - depth; y <= row-2; y++ {
+		cells = append(cells, Point{X: pocket - 1, Y: y}, Point{X: pocket + 1, Y: y})
+	}
+	cells = append(cells, Point{X: pocket, Y: row - depth - 1})
+	b.wall(cells...)
+	b.food(Point{X: pocket, Y: row - depth}, b.bait())
+	b.snake(0, straight(head, Point{X: 1}, length), Point{X: 1})
+	return nil
+}
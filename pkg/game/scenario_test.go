@@ -0,0 +1,137 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestScenarioBoards builds every scenario across difficulties and
+// orientations and checks the board is consistent and survivable
+func TestScenarioBoards(t *testing.T) {
+	for _, kind := range ScenarioNames() {
+		for _, level := range []float64{0, 0.5, 1} {
+			for seed := int64(1); seed <= 8; seed++ {
+				g := NewSeededGame(25, 25, seed)
+				g.SetupPVP("a", "b")
+				if err := g.ApplyScenario(kind, level, seed); err != nil {
+					t.Fatalf("%s@%v seed %d: %v", kind, level, seed, err)
+				}
+
+				taken := map[Point]string{}
+				claim := func(p Point, what string) {
+					if p.X <= 0 || p.Y <= 0 || p.X >= g.Width-1 || p.Y >= g.Height-1 {
+						t.Errorf("%s@%v seed %d: %s at %v is outside the board", kind, level, seed, what, p)
+					}
+					if other, ok := taken[p]; ok {
+						t.Errorf("%s@%v seed %d: %s overlaps %s at %v", kind, level, seed, what, other, p)
+					}
+					taken[p] = what
+				}
+				for i, p := range g.Players {
+					for j, c := range p.Snake {
+						claim(c, "snake")
+						if j > 0 && manhattan(c, p.Snake[j-1]) != 1 {
+							t.Errorf("%s@%v seed %d: snake %d is not contiguous", kind, level, seed, i)
+						}
+					}
+					if len(p.Snake) > 1 && p.Snake[1] == (Point{X: p.Snake[0].X + p.Direction.X, Y: p.Snake[0].Y + p.Direction.Y}) {
+						t.Errorf("%s@%v seed %d: snake %d faces its own body", kind, level, seed, i)
+					}
+				}
+				for _, o := range g.Obstacles {
+					for _, c := range o.Points {
+						claim(c, "obstacle")
+					}
+				}
+				for _, f := range g.Foods {
+					claim(f.Pos, "food")
+				}
+
+				safe := 0
+				head := g.Players[0].Snake[0]
+				for _, d := range ActionDirections {
+					next := Point{X: head.X + d.X, Y: head.Y + d.Y}
+					if _, ok := taken[next]; !ok && next.X > 0 && next.Y > 0 && next.X < g.Width-1 && next.Y < g.Height-1 {
+						safe++
+					}
+				}
+				if safe == 0 {
+					t.Errorf("%s@%v seed %d: the snake starts trapped", kind, level, seed)
+				}
+				if g.Scenario == nil || g.Scenario.Name != kind || g.Scenario.Difficulty != level {
+					t.Errorf("%s@%v seed %d: scenario recorded as %+v", kind, level, seed, g.Scenario)
+				}
+			}
+		}
+	}
+}
+
+// TestScenarioTooSmall leaves the game untouched when a scenario does not fit
+func TestScenarioTooSmall(t *testing.T) {
+	g := NewSeededGame(10, 10, 1)
+	g.SetupFFA(3)
+	before := g.Clone()
+	if err := g.ApplyScenario("food_behind_obstacle", 0, 1); err == nil {
+		t.Fatal("expected food_behind_obstacle not to fit a 10x10 board")
+	}
+	if len(g.Players) != len(before.Players) || g.Scenario != nil {
+		t.Fatalf("expected the game unchanged, got %d players and scenario %v", len(g.Players), g.Scenario)
+	}
+	for i, p := range g.Players {
+		if !reflect.DeepEqual(p.Snake, before.Players[i].Snake) {
+			t.Errorf("snake %d changed from %v to %v", i, before.Players[i].Snake, p.Snake)
+		}
+	}
+	if !reflect.DeepEqual(g.Foods, before.Foods) {
+		t.Error("expected the food to stay")
+	}
+}
+
+// TestCurriculum checks parsing and that the same seed gives the same start
+func TestCurriculum(t *testing.T) {
+	c, err := ParseCurriculum("head_on, corridor@0.5-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Scenarios, []string{"head_on", "corridor"}) || c.MinDifficulty != 0.5 || c.MaxDifficulty != 1 {
+		t.Errorf("unexpected curriculum %+v", c)
+	}
+	if all, _ := ParseCurriculum("all"); len(all.Scenarios) != len(ScenarioKinds) {
+		t.Errorf("expected every scenario, got %v", all.Scenarios)
+	}
+	if none, err := ParseCurriculum(""); none != nil || err != nil {
+		t.Errorf("expected no curriculum, got %+v (%v)", none, err)
+	}
+	for _, bad := range []string{"nope", "all@1-0", "all@0.5", "head_on@0-2"} {
+		if _, err := ParseCurriculum(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+
+	board := func(seed int64) (*Game, Scenario) {
+		g := NewSeededGame(25, 25, seed)
+		g.SetupPVP("a", "b")
+		s, err := c.Apply(g, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g, s
+	}
+	g1, s1 := board(7)
+	g2, s2 := board(7)
+	if s1 != s2 || !reflect.DeepEqual(g1.Players[0].Snake, g2.Players[0].Snake) || !reflect.DeepEqual(g1.Players[1].Snake, g2.Players[1].Snake) {
+		t.Errorf("expected seed 7 to give the same start twice, got %+v and %+v", s1, s2)
+	}
+	if s1.Difficulty < 0.5 {
+		t.Errorf("difficulty %v is outside the range", s1.Difficulty)
+	}
+
+	env := &Env{}
+	res, err := env.Reset(3, EnvConfig{Mode: "pvp", Curriculum: "incoming_fireball"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Info.Scenario != "incoming_fireball" || len(env.Game.Fireballs) != 1 {
+		t.Errorf("expected an incoming fireball episode, got %+v", res.Info)
+	}
+}
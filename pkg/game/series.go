@@ -0,0 +1,105 @@
+package game
+
+import "time"
+
+// SeriesLengths are the best-of-N series players can agree on (1 is a single rematch)
+var SeriesLengths = []int{1, 3, 5}
+
+// Series is a best-of-N run of one on one games between the same two
+// players, who keep their seats while their spawn sides alternate. A drawn
+// game is replayed, up to twice the series length in games.
+type Series struct {
+	BestOf  int
+	Players [2]string // Seat 0 and seat 1
+	Wins    [2]int
+	Draws   int
+	Games   int // Games finished
+	Start   time.Time
+
+	forfeit  int   // Seat + 1 of a player who left the series, 0 if nobody did
+	recorded *Game // Last game counted, which is only counted once
+}
+
+// NewSeries starts a series between the players in seats 0 and 1
+func NewSeries(bestOf int, p1, p2 string, start time.Time) *Series {
+	return &Series{BestOf: bestOf, Players: [2]string{p1, p2}, Start: start}
+}
+
+// Setup attaches the series to the next game and puts the players on the
+// other sides of the board every second game
+func (s *Series) Setup(g *Game) {
+	g.Series = s
+	if s.Games%2 == 1 {
+		g.SwapSides()
+	}
+}
+
+// Record counts a finished game of the series, once
+func (s *Series) Record(g *Game) {
+	if s.Over() || s.recorded == g {
+		return
+	}
+	s.recorded = g
+	s.Games++
+	switch g.Result(0) {
+	case "won":
+		s.Wins[0]++
+	case "lost":
+		s.Wins[1]++
+	default:
+		s.Draws++
+	}
+}
+
+// Forfeit ends the series as lost by the player in seat, who left it
+func (s *Series) Forfeit(seat int) {
+	if !s.Over() {
+		s.forfeit = seat + 1
+	}
+}
+
+// Over reports whether the series is decided, or ran out of games
+func (s *Series) Over() bool {
+	need := s.BestOf/2 + 1
+	return s.forfeit > 0 || s.Wins[0] >= need || s.Wins[1] >= need || s.Games >= 2*s.BestOf
+}
+
+// Winner returns the seat that won the series, or -1 while it runs or if it ended even
+func (s *Series) Winner() int {
+	switch {
+	case s.forfeit > 0:
+		return 2 - s.forfeit
+	case !s.Over() || s.Wins[0] == s.Wins[1]:
+		return -1
+	case s.Wins[0] > s.Wins[1]:
+		return 0
+	}
+	return 1
+}
+
+// Score is the series as shown with every game state
+func (s *Series) Score() SeriesScore {
+	game := s.Games + 1
+	if s.Over() {
+		game = s.Games
+	}
+	score := SeriesScore{BestOf: s.BestOf, Game: game, P1Wins: s.Wins[0], P2Wins: s.Wins[1], Draws: s.Draws, Over: s.Over()}
+	if w := s.Winner(); w >= 0 {
+		score.Winner = s.Players[w]
+	}
+	return score
+}
+
+// SwapSides exchanges the snakes of the two players of a one on one game
+// before it starts, so each begins where the other would have
+func (g *Game) SwapSides() {
+	if len(g.Players) != 2 {
+		return
+	}
+	a, b := g.Players[0], g.Players[1]
+	a.Snake, b.Snake = b.Snake, a.Snake
+	a.Direction, b.Direction = b.Direction, a.Direction
+	a.LastMoveDir, b.LastMoveDir = b.LastMoveDir, a.LastMoveDir
+	a.Spawn, b.Spawn = b.Spawn, a.Spawn
+	a.SpawnDir, b.SpawnDir = b.SpawnDir, a.SpawnDir
+}
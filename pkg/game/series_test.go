@@ -0,0 +1,86 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// finishedGame returns a finished one on one game with the given winner
+func finishedGame(winner string) *Game {
+	g := NewSeededGame(25, 25, 1)
+	g.SetupPVP("a", "b")
+	g.GameOver = true
+	g.Winner = winner
+	return g
+}
+
+// TestSeries plays a best-of-3 with a replayed draw to its end
+func TestSeries(t *testing.T) {
+	s := NewSeries(3, "a", "b", time.Unix(1000, 0))
+
+	g := finishedGame("player")
+	s.Record(g)
+	s.Record(g) // Counted once
+	s.Record(finishedGame("draw"))
+	if s.Wins != [2]int{1, 0} || s.Draws != 1 || s.Over() || s.Winner() != -1 {
+		t.Fatalf("expected 1-0 with a draw and the series going on, got %+v", s)
+	}
+	if sc := s.Score(); sc.Game != 3 || sc.P1Wins != 1 || sc.Over {
+		t.Errorf("expected game 3 to be next, got %+v", sc)
+	}
+
+	s.Record(finishedGame("ai"))
+	s.Record(finishedGame("ai"))
+	if !s.Over() || s.Winner() != 1 || s.Games != 4 {
+		t.Fatalf("expected b to win 2-1 after 4 games, got %+v", s)
+	}
+	if sc := s.Score(); sc.Game != 4 || !sc.Over || sc.Winner != "b" {
+		t.Errorf("expected the final score to show game 4, got %+v", sc)
+	}
+	s.Record(finishedGame("player"))
+	if s.Wins != [2]int{1, 2} {
+		t.Errorf("expected no games counted after the end, got %+v", s.Wins)
+	}
+}
+
+// TestSeriesForfeit hands the series to the player who stayed
+func TestSeriesForfeit(t *testing.T) {
+	s := NewSeries(5, "a", "b", time.Now())
+	s.Record(finishedGame("ai"))
+	s.Forfeit(1)
+	if !s.Over() || s.Winner() != 0 {
+		t.Errorf("expected a to win by forfeit, got %+v", s)
+	}
+
+	// Draws only go on for so long
+	s = NewSeries(1, "a", "b", time.Now())
+	s.Record(finishedGame("draw"))
+	s.Record(finishedGame("draw"))
+	if !s.Over() || s.Winner() != -1 {
+		t.Errorf("expected a drawn series after two draws, got %+v", s)
+	}
+}
+
+// TestSeriesSides alternates the spawn sides and shows the score
+func TestSeriesSides(t *testing.T) {
+	s := NewSeries(3, "a", "b", time.Now())
+	first := NewSeededGame(25, 25, 1)
+	first.SetupPVP("a", "b")
+	s.Setup(first)
+	home := first.Players[0].Snake[0]
+	s.Record(finishedGame("player"))
+
+	g := NewSeededGame(25, 25, 1)
+	g.SetupPVP("a", "b")
+	s.Setup(g)
+	if g.Players[0].Name != "a" || g.Players[1].Snake[0] != home {
+		t.Errorf("expected a to keep seat 0 and start from b's side, got %+v", g.Players[0].Snake)
+	}
+	if g.Players[0].Direction != (Point{X: -1, Y: 0}) {
+		t.Errorf("expected a to head left from the right side, got %+v", g.Players[0].Direction)
+	}
+	st := g.GetGameStateSnapshot(true, false, "mid")
+	if st.Series == nil || st.Series.Game != 2 || st.Series.P1Wins != 1 {
+		t.Errorf("expected game 2 at 1-0 in the state, got %+v", st.Series)
+	}
+}
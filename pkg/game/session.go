@@ -5,11 +5,13 @@ import (
 	"time"
 )
 
-func RecordGameSession(username string, startTime, endTime time.Time, score int, winner, mode, difficulty string) {
+// RecordGameSession logs one finished game; adaptiveLevel is the dynamic difficulty level reached (0 = off)
+// and vsBot marks a PVP game whose opponent was a bot filling in for a human
+func RecordGameSession(username string, startTime, endTime time.Time, score int, winner, mode, difficulty string, adaptiveLevel int, vsBot bool) {
 	_, err := DB.Exec(`
-		INSERT INTO game_sessions (username, start_time, end_time, score, winner, mode, difficulty)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		username, startTime, endTime, score, winner, mode, difficulty,
+		INSERT INTO game_sessions (username, start_time, end_time, score, winner, mode, difficulty, adaptive_level, vs_bot)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		username, startTime, endTime, score, winner, mode, difficulty, adaptiveLevel, vsBot,
 	)
 	if err != nil {
 		log.Printf("❌ Error recording game session: %v\n", err)
@@ -17,3 +19,22 @@ func RecordGameSession(username string, startTime, endTime time.Time, score int,
 		log.Printf("📝 Detailed session recorded for %s: Result=%s, Score=%d\n", username, winner, score)
 	}
 }
+
+// RecordSeries stores a finished best-of-N series as one record; winner is
+// the winning player's name, or "draw"
+func RecordSeries(s *Series, endTime time.Time) {
+	winner := "draw"
+	if w := s.Winner(); w >= 0 {
+		winner = s.Players[w]
+	}
+	_, err := DB.Exec(`
+		INSERT INTO pvp_series (player1, player2, best_of, wins1, wins2, draws, games, winner, forfeit, start_time, end_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.Players[0], s.Players[1], s.BestOf, s.Wins[0], s.Wins[1], s.Draws, s.Games, winner, s.forfeit > 0, s.Start, endTime,
+	)
+	if err != nil {
+		log.Printf("❌ Error recording series: %v\n", err)
+	} else {
+		log.Printf("📝 Series recorded: %s %d-%d %s (best of %d), winner %s\n", s.Players[0], s.Wins[0], s.Wins[1], s.Players[1], s.BestOf, winner)
+	}
+}
@@ -0,0 +1,195 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// SimClock is a manually advanced clock for headless simulations.
+// Games with a SimClock never read the wall clock, so they can be stepped
+// as fast as the CPU allows.
+type SimClock struct {
+	now time.Time
+}
+
+// NewSimClock creates a clock frozen at start
+func NewSimClock(start time.Time) *SimClock {
+	return &SimClock{now: start}
+}
+
+// Now returns the current virtual time
+func (c *SimClock) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the virtual time forward
+func (c *SimClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Now returns the game's current time: the virtual clock if one is attached,
+// the wall clock otherwise. All timers (food, props, stuns, cooldowns) use it.
+func (g *Game) Now() time.Time {
+	if g.Clock != nil {
+		return g.Clock.Now()
+	}
+	return time.Now()
+}
+
+// Clone returns a deep copy of the game suitable for simulation.
+// The copy runs on its own SimClock starting at the original's current time,
+// is Headless (no messages or logs), and has no recorder. Brains are NOT
+// copied: callers must install controllers before stepping the clone.
+func (g *Game) Clone() *Game {
+	c := *g
+
+	c.Players = make([]*Player, len(g.Players))
+	for i, p := range g.Players {
+		cp := *p
+		cp.Snake = append([]Point(nil), p.Snake...)
+		cp.Effects = make([]*ActiveEffect, len(p.Effects))
+		for j, e := range p.Effects {
+			ce := *e
+			cp.Effects[j] = &ce
+		}
+		cp.Brain = nil
+		c.Players[i] = &cp
+	}
+
+	c.Foods = append([]Food(nil), g.Foods...)
+	c.Props = append([]Prop(nil), g.Props...)
+	c.Obstacles = make([]Obstacle, len(g.Obstacles))
+	for i, obs := range g.Obstacles {
+		co := obs
+		co.Points = append([]Point(nil), obs.Points...)
+		c.Obstacles[i] = co
+	}
+	c.Fireballs = make([]*Fireball, len(g.Fireballs))
+	for i, fb := range g.Fireballs {
+		cf := *fb
+		c.Fireballs[i] = &cf
+	}
+
+	c.HitPoints = nil
+	c.ScoreEvents = nil
+	c.Message = ""
+	c.MessageType = ""
+	c.CurrentAIContext = AIContext{}
+	c.Recorder = nil
+	c.Clock = NewSimClock(g.Now())
+	c.Headless = true
+	c.Adaptive = nil
+	// Each clone gets its own stream, derived from the original's generator
+	c.Rand = rand.New(rand.NewSource(g.rng().Int63()))
+	return &c
+}
+
+// Simulator steps a Game one BaseTick at a time using the same scheduling
+// rules as the web server loop: per-player move timers (difficulty, boost,
+// time warp), fireballs at FireballSpeed and periodic world updates.
+type Simulator struct {
+	Game       *Game
+	Difficulty string // Speed tier for snakes without an AI profile
+
+	moveTicks []int
+	moves     []int
+	fbTicks   int
+}
+
+// NewSimulator wraps g, attaching a SimClock if it does not have one yet.
+// A game without a speed tier takes difficulty as its own.
+func NewSimulator(g *Game, difficulty string) *Simulator {
+	if g.Clock == nil {
+		g.Clock = NewSimClock(g.Now())
+	}
+	if g.SpeedTier == "" {
+		g.SpeedTier = difficulty
+	}
+	return &Simulator{Game: g, Difficulty: difficulty}
+}
+
+// Moves returns how many times the player has moved in this simulation
+func (s *Simulator) Moves(idx int) int {
+	if idx >= len(s.moves) {
+		return 0
+	}
+	return s.moves[idx]
+}
+
+// Tick advances the simulation by one BaseTick and reports whether anything moved
+func (s *Simulator) Tick() bool {
+	g := s.Game
+	if g.GameOver || g.Paused {
+		return false
+	}
+	g.Clock.Advance(config.BaseTick)
+	g.HitPoints = nil
+	g.ScoreEvents = nil
+
+	for len(s.moveTicks) < len(g.Players) {
+		s.moveTicks = append(s.moveTicks, 0)
+		s.moves = append(s.moves, 0)
+	}
+
+	changed := false
+	for i := range g.Players {
+		s.moveTicks[i]++
+		if s.moveTicks[i] < s.ticksNeeded(i) {
+			continue
+		}
+		s.moveTicks[i] = 0
+		g.UpdatePlayer(i)
+		s.moves[i]++
+		changed = true
+		if g.GameOver {
+			return true
+		}
+	}
+
+	s.fbTicks++
+	if s.fbTicks >= int(config.FireballSpeed/config.BaseTick) {
+		s.fbTicks = 0
+		g.UpdateFireballs()
+		changed = true
+	}
+
+	g.TrySpawnFood()
+	g.TrySpawnProp()
+	g.TrySpawnObstacle()
+	g.CheckTimeLimit()
+	g.UpdateAdaptiveDifficulty()
+	g.updateActiveEffects()
+	return changed
+}
+
+// ticksNeeded returns how many BaseTicks the player waits between moves
+func (s *Simulator) ticksNeeded(idx int) int {
+	g := s.Game
+	p := g.Players[idx]
+	tier := s.Difficulty
+	if p.Profile != nil {
+		tier = p.Profile.SpeedTier
+	}
+	ticks := int(g.GetMoveIntervalExt(tier, p.Boosting) / config.BaseTick)
+	if g.IsTimeWarpedFor(idx) {
+		ticks *= 2
+	}
+	return ticks
+}
+
+// IsTimeWarpedFor reports whether another player's TIMEWARP slows this player down
+func (g *Game) IsTimeWarpedFor(idx int) bool {
+	for i, p := range g.Players {
+		if i == idx {
+			continue
+		}
+		for _, e := range p.Effects {
+			if e.Type == EffectTimeWarp {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"time"
+
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// GameFromState rebuilds a headless game from a recorded GameState so tools
+// can replay decisions on it. Only what the snapshot carries is restored:
+// both snakes, free-for-all opponents, food, props, obstacles, fireballs,
+// effects, stun state, scores, the outcome and the time left. Headings are taken from
+// the first two segments of each snake.
+func GameFromState(state GameState, width, height int) *Game {
+	g := NewSeededGame(width, height, 0)
+	g.Headless = true
+	now := g.Now()
+
+	g.Mode = state.Mode
+	g.GameOver = state.GameOver
+	g.Winner = state.Winner
+	g.IsPVP = state.IsPVP
+	g.BerserkerMode = state.Berserker
+	g.Obstacles = state.Obstacles
+	g.Props = state.Props
+	g.Fireballs = state.Fireballs
+	// Half a second into the recorded second, so GetTimeRemaining rounds back to it
+	remaining := time.Duration(state.TimeRemaining)*time.Second + time.Second/2
+	g.StartTime = now.Add(remaining - config.GameDuration)
+	g.Foods = make([]Food, len(state.Foods))
+	for i, f := range state.Foods {
+		g.Foods[i] = Food{Pos: f.Pos, FoodType: FoodType(f.FoodType), SpawnTime: now}
+	}
+
+	restore := func(p *Player, snake []Point, score int, stunned bool, effects []*ActiveEffect) {
+		p.Snake = snake
+		p.Score = score
+		p.Effects = effects
+		p.Stunned = stunned
+		if stunned {
+			p.StunnedUntil = now.Add(time.Second)
+		}
+		if len(snake) > 1 {
+			dir := Point{X: snake[0].X - snake[1].X, Y: snake[0].Y - snake[1].Y}
+			p.Direction, p.LastMoveDir = dir, dir
+		}
+	}
+	restore(g.Players[0], state.Snake, state.Score, state.PlayerStunned, state.P1Effects)
+	restore(g.Players[1], state.AISnake, state.AIScore, state.AIStunned, state.P2Effects)
+	for _, o := range state.Opponents {
+		p := &Player{Name: o.Name, Controller: o.Controller, Brain: &HeuristicController{}}
+		restore(p, o.Snake, o.Score, o.Stunned, nil)
+		g.Players = append(g.Players, p)
+	}
+	return g
+}
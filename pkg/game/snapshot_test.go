@@ -0,0 +1,71 @@
+package game
+
+import "testing"
+
+// TestGameFromState checks a rebuilt game gives the same observation and heading as the original
+func TestGameFromState(t *testing.T) {
+	g := observationGame()
+	g.Players[0].Direction = Point{X: 1}
+	g.Players[0].LastMoveDir = Point{X: 1}
+	g.Props = []Prop{{Pos: Point{X: 8, Y: 8}, Type: PropShield}}
+	g.Fireballs = []*Fireball{{Pos: Point{X: 14, Y: 12}, Dir: Point{Y: 1}}}
+	g.Players[0].Effects = []*ActiveEffect{{Type: EffectMagnet}}
+	g.TimerStarted = true
+
+	state := g.GetGameStateSnapshot(true, false, "mid")
+	restored := GameFromState(state, g.Width, g.Height)
+
+	want := g.BuildObservation(ObservationV2, 0)
+	got := restored.BuildObservation(ObservationV2, 0)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("observation differs at %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	if restored.Players[0].LastMoveDir != (Point{X: 1}) {
+		t.Errorf("expected the heading to come from the snake, got %v", restored.Players[0].LastMoveDir)
+	}
+	if restored.IsSafeMove(0, Point{X: -1}) {
+		t.Error("moving back into the neck should be unsafe")
+	}
+}
+
+// TestAIDebugSnapshot checks the overlay streams the AI's candidates, in a
+// stable order, only while it is switched on
+func TestAIDebugSnapshot(t *testing.T) {
+	g := observationGame()
+	g.Players[0].Controller = "manual"
+	ai := g.Players[1]
+	ai.Controller = "heuristic"
+	ai.Snake = []Point{{X: 1, Y: 5}, {X: 1, Y: 6}} // Against the left wall
+	ai.LastMoveDir = Point{Y: -1}
+
+	(&HeuristicController{}).GetAction(g, 1)
+	if state := g.GetGameStateSnapshot(true, false, "mid"); state.AIDebug != nil {
+		t.Fatalf("expected no overlay data while it is off, got %+v", state.AIDebug)
+	}
+
+	g.ShowAIDebug = true
+	decisions := g.GetGameStateSnapshot(true, false, "mid").AIDebug
+	if len(decisions) != 1 || decisions[0].Player != 1 || decisions[0].Controller != "heuristic" {
+		t.Fatalf("expected one decision for the AI, got %+v", decisions)
+	}
+	d := decisions[0]
+	if d.Intent != IntentHunt || d.TargetPos == nil || *d.TargetPos != (Point{X: 1, Y: 1}) {
+		t.Errorf("expected the AI to hunt the food at (1,1), got %s %v", d.Intent, d.TargetPos)
+	}
+	// Down reverses into the neck and is never weighed
+	want := []struct {
+		dir  Point
+		safe bool
+	}{{Point{Y: -1}, true}, {Point{X: -1}, false}, {Point{X: 1}, true}}
+	if len(d.Candidates) != len(want) {
+		t.Fatalf("expected %d candidates, got %+v", len(want), d.Candidates)
+	}
+	for i, w := range want {
+		c := d.Candidates[i]
+		if c.Dir != w.dir || c.Safe != w.safe || (c.Safe && c.Space == 0) {
+			t.Errorf("candidate %d: got %+v, want dir %v safe %v with space", i, c, w.dir, w.safe)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package game
 
-import "time"
+import (
+	"math/rand"
+	"time"
+)
 
 // Point represents a coordinate on the game board
 type Point struct {
@@ -80,7 +83,17 @@ type Fireball struct {
 	Pos       Point     `json:"pos"`
 	Dir       Point     `json:"dir"`
 	SpawnTime time.Time `json:"-"`
-	Owner     string    `json:"owner"` // "player" or "ai"
+	Owner     string    `json:"owner"`    // "player" or "ai"
+	OwnerIdx  int       `json:"ownerIdx"` // Index of the shooter in Game.Players
+}
+
+// ownerIndex returns the shooter's player index, falling back to the
+// "player"/"ai" label for fireballs created without one
+func (fb *Fireball) ownerIndex() int {
+	if fb.OwnerIdx > 0 || fb.Owner != "ai" {
+		return fb.OwnerIdx
+	}
+	return 1
 }
 
 // ScoreEvent represents a point-earning event for visual feedback
@@ -103,8 +116,23 @@ type Player struct {
 	LastFireTime time.Time       `json:"-"`
 	Name         string          `json:"name"`
 	Brain        Controller      `json:"-"`
-	Controller   string          `json:"controllerType"` // "manual", "heuristic", "neural"
+	Controller   string          `json:"controllerType"` // "manual", "heuristic", "neural", "mcts", "bot"
 	Effects      []*ActiveEffect `json:"effects"`        // Status effects
+	Deaths       int             `json:"deaths"`         // Crashes this game (AI competitors respawn)
+	Profile      *AIProfile      `json:"-"`              // AI skill profile (nil = full strength)
+	Personality  *AIPersonality  `json:"-"`              // AI personality preset (nil = balanced)
+	Spawn        Point           `json:"-"`              // Respawn point for AI competitors
+	SpawnDir     Point           `json:"-"`              // Heading after a respawn
+	Berserker    bool            `json:"-"`              // Aggressive play regardless of Game.BerserkerMode
+	LastModel    string          `json:"-"`              // Model version behind the last neural move ("" = not neural)
+	LastContext  AIContext       `json:"-"`              // Reasoning behind the last AI move
+	Moves        int             `json:"-"`              // Move decisions taken this game (for recording)
+
+	// Running totals for reward shaping (see RewardTally)
+	HitsLanded     int `json:"-"` // Fireballs that hit another snake
+	HitsTaken      int `json:"-"` // Fireballs of other snakes that hit this one
+	TimesStunned   int `json:"-"` // Headshots taken
+	PropsCollected int `json:"-"`
 }
 
 // Game represents the main game state
@@ -113,7 +141,6 @@ type Game struct {
 	Height            int
 	Players           []*Player
 	Foods             []Food       // Multiple food items
-	LastScore         int          `json:"-"`           // Total score from previous frame (for reward calc - primarily for P1)
 	ScoreEvents       []ScoreEvent `json:"scoreEvents"` // Recent scoring events
 	GameOver          bool
 	Paused            bool          // Pause state
@@ -142,11 +169,35 @@ type Game struct {
 	Winner    string      `json:"winner"`    // "player", "ai", or "draw"
 	Mode      string      `json:"mode"`      // "zen", "battle", or "pvp"
 	IsPVP     bool        `json:"isPVP"`
+	SpeedTier string      `json:"-"` // Move speed of snakes without an AI profile ("" = mid)
 
 	// Recording support
-	CurrentAIContext AIContext     `json:"-"` // Last calculated AI context
-	Recorder         *GameRecorder `json:"-"` // Active recorder
-	NeuralNet        *ONNXModel    `json:"-"` // Loaded AI Model (ONNX Runtime)
+	CurrentAIContext AIContext      `json:"-"` // Last calculated AI context
+	Recorder         *GameRecorder  `json:"-"` // Active recorder
+	NeuralNet        *ModelRegistry `json:"-"` // Loaded AI Models (nil when neural inference is unavailable)
+	Model            string         `json:"-"` // Model this game's neural brains use ("" = default)
+
+	// Headless simulation support (MCTS rollouts, offline tools)
+	Clock    *SimClock  `json:"-"` // Virtual clock; nil means wall-clock time
+	Headless bool       `json:"-"` // Suppresses messages and logging
+	Rand     *rand.Rand `json:"-"` // Per-game generator for reproducible runs; nil uses the shared one
+
+	// Dynamic difficulty state (solo only, nil when off)
+	Adaptive *AdaptiveDifficulty `json:"-"`
+
+	// Debug overlay: snapshots carry every AI player's latest decision
+	ShowAIDebug bool `json:"-"`
+
+	// Curriculum scenario the game was set up with, nil for a normal start
+	Scenario *Scenario `json:"-"`
+
+	// Room rules (see SetupRoom)
+	Duration time.Duration `json:"-"` // Match length; 0 means config.GameDuration
+	NoProps  bool          `json:"-"` // No props spawn on the board
+	Respawn  bool          `json:"-"` // Crashed snakes respawn and the clock ends the game, even in PVP
+
+	// Best-of-N series this PVP game belongs to, nil for a single game
+	Series *Series `json:"-"`
 
 	// Legacy support / Internal
 	BerserkerMode bool `json:"berserker"` // Whether AI (if any) is in aggressive mode
@@ -193,6 +244,33 @@ type GameState struct {
 	P2Effects     []*ActiveEffect `json:"p2Effects"`
 	P1Name        string          `json:"p1Name"`
 	P2Name        string          `json:"p2Name"`
+	AIPersonality string          `json:"aiPersonality"`
+	Opponents     []OpponentInfo  `json:"opponents,omitempty"` // Extra AI snakes in free-for-all (Players[2:])
+	Placement     int             `json:"placement"`           // P1's final place (set once the game is over)
+	AdaptiveLevel int             `json:"adaptiveLevel"`       // Dynamic difficulty level 1-10 (0 = off)
+	AIDebug       []AIDecision    `json:"aiDebug,omitempty"`   // AI decisions, only with Game.ShowAIDebug
+	Series        *SeriesScore    `json:"series,omitempty"`    // Best-of-N series score, nil for a single game
+}
+
+// SeriesScore is the state of a best-of-N series; P1 and P2 are seats 0 and 1
+type SeriesScore struct {
+	BestOf int  `json:"bestOf"`
+	Game   int  `json:"game"` // Game being played, or the last one once the series is over
+	P1Wins int  `json:"p1Wins"`
+	P2Wins int  `json:"p2Wins"`
+	Draws  int  `json:"draws"`
+	Over   bool `json:"over"`
+
+	Winner string `json:"winner,omitempty"` // Name of the series winner, empty while it runs or if it ended even
+}
+
+// OpponentInfo describes an additional AI snake in free-for-all
+type OpponentInfo struct {
+	Name       string  `json:"name"`
+	Snake      []Point `json:"snake"`
+	Score      int     `json:"score"`
+	Stunned    bool    `json:"stunned"`
+	Controller string  `json:"controllerType"`
 }
 
 // GameConfig is a DTO for game settings sent to client on connect
@@ -212,14 +290,33 @@ const (
 	IntentHunt    AIIntent = "HUNT"    // Actively seeking food
 	IntentSurvive AIIntent = "SURVIVE" // Avoiding dead ends or threats
 	IntentAttack  AIIntent = "ATTACK"  // Engaging enemy
+	IntentHarass  AIIntent = "HARASS"  // Cutting off the enemy head
 	IntentIdle    AIIntent = "IDLE"    // No specific target
 )
 
 // AIContext records the internal state/decision of the AI
 type AIContext struct {
-	Intent    AIIntent `json:"intent"`
-	TargetPos *Point   `json:"target_pos,omitempty"`
-	Urgency   float64  `json:"urgency"` // 0.0 - 1.0
+	Intent      AIIntent `json:"intent"`
+	TargetPos   *Point   `json:"target_pos,omitempty"`
+	Urgency     float64  `json:"urgency"`               // 0.0 - 1.0
+	Personality string   `json:"personality,omitempty"` // AI personality preset that made the decision
+
+	Candidates []MoveCandidate `json:"candidates,omitempty"` // Directions weighed, in ActionDirections order
+}
+
+// MoveCandidate is one direction the heuristic AI weighed
+type MoveCandidate struct {
+	Dir   Point   `json:"dir"`
+	Safe  bool    `json:"safe"`            // The next tile is free; unsafe moves are not scored
+	Space int     `json:"space,omitempty"` // Tiles reachable from the next tile (flood fill, capped by the lookahead)
+	Score float64 `json:"score,omitempty"`
+}
+
+// AIDecision is the latest AIContext of an AI-driven player, streamed for the debug overlay
+type AIDecision struct {
+	Player     int    `json:"player"`
+	Controller string `json:"controller"`
+	AIContext
 }
 
 // ActionData represents the discrete action taken in a step
@@ -231,13 +328,33 @@ type ActionData struct {
 
 // StepRecord represents a single frame of game data for training
 type StepRecord struct {
-	StepID    int        `json:"step_id"`
-	Timestamp int64      `json:"ts"` // Unix Milli
-	State     GameState  `json:"state"`
-	Action    ActionData `json:"action"`
-	AIContext AIContext  `json:"ai_context"`
-	Reward    float64    `json:"reward"`
-	Done      bool       `json:"done"`
+	StepID    int                `json:"step_id"`
+	Timestamp int64              `json:"ts"` // Unix Milli
+	State     GameState          `json:"state"`
+	Action    ActionData         `json:"action"`
+	AIContext AIContext          `json:"ai_context"`
+	Model     string             `json:"model,omitempty"`    // Model version that chose P1's move
+	AIModel   string             `json:"ai_model,omitempty"` // Model version that chose the first AI's move
+	Reward    float64            `json:"reward"`             // P1's reward under the "default" preset
+	Rewards   map[string]float64 `json:"rewards,omitempty"`  // P1's reward under every preset
+	Done      bool               `json:"done"`
+	Players   []PlayerStep       `json:"players,omitempty"` // Every player's side of this step (P1's duplicates the fields above)
+}
+
+// PlayerStep is one player's action and reward in a recorded step.
+// State indexes players like Game.Players: 0 is snake, 1 is aiSnake and
+// 2+ are opponents.
+type PlayerStep struct {
+	Index      int                `json:"idx"`
+	Name       string             `json:"name"`
+	Controller string             `json:"controller"` // "manual", "heuristic", "neural", ...
+	Action     ActionData         `json:"action"`
+	Reward     float64            `json:"reward"`            // Under the "default" preset
+	Rewards    map[string]float64 `json:"rewards,omitempty"` // Under every preset
+	Events     *RewardEvents      `json:"events,omitempty"`  // What the rewards were computed from
+	Moved      bool               `json:"moved"`             // False when the player has not moved since the previous record
+	Model      string             `json:"model,omitempty"`
+	AIContext  *AIContext         `json:"ai_context,omitempty"` // Only for the server's own AI controllers
 }
 
 // LeaderboardEntry represents a single entry in the global leaderboard
@@ -247,4 +364,5 @@ type LeaderboardEntry struct {
 	Date       time.Time `json:"date"`
 	Difficulty string    `json:"difficulty"`
 	Mode       string    `json:"mode"`
+	Opponents  int       `json:"opponents"` // AI snakes faced (more than 1 = free-for-all)
 }
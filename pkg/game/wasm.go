@@ -0,0 +1,333 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/trytobebee/snake_go/pkg/config"
+)
+
+// WASM bots are user-uploaded WebAssembly modules run by a pure-Go runtime,
+// so they never execute natively. The ABI (see docs/WASM_BOTS.md):
+//
+//	alloc(size i32) -> ptr i32          Reserve size bytes for the observation
+//	get_action(ptr i32, len i32) -> i32 Pick a move for the JSON BotRequest at ptr
+//	dealloc(ptr i32, size i32)          Optional, called after get_action
+//
+// The result packs the move: bits 0-2 hold the direction (0 keeps the
+// heading, 1-4 index ActionDirections), bit 3 boosts and bit 4 fires.
+// Negative results mean "no move" and use the fallback.
+
+// WasmBotPrefix names WASM bots in the controller registry ("wasm:<name>")
+const WasmBotPrefix = "wasm:"
+
+const (
+	wasmActionBoost = 1 << 3
+	wasmActionFire  = 1 << 4
+)
+
+var wasmBotName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// WasmBot is a compiled WASM bot. Every player it controls gets its own
+// instance, so bots can keep state between moves of one game. The runtime is
+// closed once its owner and every controller let go of it.
+type WasmBot struct {
+	Name string
+
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	mu      sync.Mutex
+	refs    int // The owner (see Close) plus controllers not yet released
+}
+
+// CompileWasmBot checks and compiles a module under the memory cap
+func CompileWasmBot(name string, wasm []byte) (*WasmBot, error) {
+	if !wasmBotName.MatchString(name) {
+		return nil, fmt.Errorf("invalid bot name %q (letters, digits, _ and -, up to 32)", name)
+	}
+	if len(wasm) > config.WasmMaxModuleSize {
+		return nil, fmt.Errorf("module is %d bytes, limit is %d", len(wasm), config.WasmMaxModuleSize)
+	}
+
+	ctx := context.Background()
+	rt := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(config.WasmMemoryLimit).
+		WithCloseOnContextDone(true))
+	// WASI without file system, environment or arguments, for toolchains
+	// that always import it
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		rt.Close(ctx)
+		return nil, err
+	}
+	mod, err := rt.CompileModule(ctx, wasm)
+	if err != nil {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("compiling %s: %w", name, err)
+	}
+
+	exports := mod.ExportedFunctions()
+	for fn, sig := range map[string]string{"alloc": "i32->i32", "get_action": "i32,i32->i32"} {
+		def, ok := exports[fn]
+		if !ok {
+			rt.Close(ctx)
+			return nil, fmt.Errorf("%s does not export %s", name, fn)
+		}
+		if got := wasmSignature(def); got != sig {
+			rt.Close(ctx)
+			return nil, fmt.Errorf("%s exports %s as %s, want %s", name, fn, got, sig)
+		}
+	}
+	if len(mod.ExportedMemories()) == 0 {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("%s does not export its memory", name)
+	}
+	return &WasmBot{Name: name, runtime: rt, module: mod, refs: 1}, nil
+}
+
+func wasmSignature(def api.FunctionDefinition) string {
+	names := func(types []api.ValueType) string {
+		out := make([]string, len(types))
+		for i, t := range types {
+			out[i] = api.ValueTypeName(t)
+		}
+		return strings.Join(out, ",")
+	}
+	return names(def.ParamTypes()) + "->" + names(def.ResultTypes())
+}
+
+// Close drops the owner's hold on the bot. Controllers still playing keep it
+// until they are released.
+func (b *WasmBot) Close() {
+	b.release()
+}
+
+func (b *WasmBot) acquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.refs == 0 {
+		return false
+	}
+	b.refs++
+	return true
+}
+
+func (b *WasmBot) release() {
+	b.mu.Lock()
+	b.refs--
+	last := b.refs == 0
+	b.mu.Unlock()
+	if last {
+		b.runtime.Close(context.Background())
+	}
+}
+
+// NewController creates a controller backed by a fresh instance of the bot.
+// Release it when its game is over (see ReleaseController).
+func (b *WasmBot) NewController() Controller {
+	c := &WasmController{
+		Bot:      b,
+		Timeout:  config.WasmMoveTimeout,
+		Fallback: &HeuristicController{},
+	}
+	c.released = !b.acquire() // A closed bot only plays the fallback
+	return c
+}
+
+// WasmController plays a WASM bot. A move that traps, runs out of time or
+// returns no move uses the Fallback controller; the instance is then
+// replaced on the next move.
+type WasmController struct {
+	Bot      *WasmBot
+	Timeout  time.Duration
+	Fallback Controller
+
+	mu       sync.Mutex // Serializes moves with Release
+	released bool
+	mod      api.Module
+	tick     int
+	answered atomic.Int64
+	missed   atomic.Int64
+}
+
+// Stats returns how many moves the bot made and how many fell back
+func (c *WasmController) Stats() (answered, missed int) {
+	return int(c.answered.Load()), int(c.missed.Load())
+}
+
+func (c *WasmController) GetAction(g *Game, playerIdx int) ActionData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.released {
+		return c.Fallback.GetAction(g, playerIdx)
+	}
+	c.tick++
+	action, err := c.call(BotRequest{
+		Tick:       c.tick,
+		You:        playerIdx,
+		DeadlineMs: int(c.Timeout / time.Millisecond),
+		State:      g.GetGameStateSnapshot(true, g.Players[playerIdx].Boosting, ""),
+	})
+	if err != nil {
+		// The instance may be left in any state; start over next move
+		c.reset()
+		if c.missed.Add(1) == 1 {
+			log.Printf("🧩 WASM bot %s missed a move, using the fallback: %v", c.Bot.Name, err)
+		}
+		return c.Fallback.GetAction(g, playerIdx)
+	}
+	c.answered.Add(1)
+	return action
+}
+
+func (c *WasmController) call(req BotRequest) (ActionData, error) {
+	if c.mod == nil {
+		if err := c.instantiate(); err != nil {
+			return ActionData{}, err
+		}
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return ActionData{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	res, err := c.mod.ExportedFunction("alloc").Call(ctx, uint64(len(data)))
+	if err != nil {
+		return ActionData{}, err
+	}
+	ptr := uint32(res[0])
+	if !c.mod.Memory().Write(ptr, data) {
+		return ActionData{}, errors.New("alloc returned memory out of range")
+	}
+	res, err = c.mod.ExportedFunction("get_action").Call(ctx, uint64(ptr), uint64(len(data)))
+	if err != nil {
+		return ActionData{}, err
+	}
+	if free := c.mod.ExportedFunction("dealloc"); free != nil {
+		free.Call(ctx, uint64(ptr), uint64(len(data)))
+	}
+	return decodeWasmAction(int32(res[0]))
+}
+
+// instantiate starts a fresh instance; reactor modules get their _initialize
+func (c *WasmController) instantiate() error {
+	ctx, cancel := context.WithTimeout(context.Background(), config.WasmInitTimeout)
+	defer cancel()
+	mod, err := c.Bot.runtime.InstantiateModule(ctx, c.Bot.module,
+		wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return err
+	}
+	c.mod = mod
+	return nil
+}
+
+// Release closes the controller's instance and lets go of the bot; later
+// moves use the fallback
+func (c *WasmController) Release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.released {
+		return
+	}
+	c.released = true
+	c.reset()
+	c.Bot.release()
+}
+
+// reset drops the current instance
+func (c *WasmController) reset() {
+	if c.mod != nil {
+		c.mod.Close(context.Background())
+		c.mod = nil
+	}
+}
+
+func decodeWasmAction(v int32) (ActionData, error) {
+	if v < 0 {
+		return ActionData{}, errors.New("no move")
+	}
+	dir := int(v & 7)
+	if dir > len(ActionDirections) {
+		return ActionData{}, fmt.Errorf("invalid direction %d", dir)
+	}
+	action := ActionData{Boost: v&wasmActionBoost != 0, Fire: v&wasmActionFire != 0}
+	if dir > 0 {
+		action.Direction = ActionDirections[dir-1]
+	}
+	return action, nil
+}
+
+var (
+	wasmMu   sync.Mutex
+	wasmBots = map[string]*WasmBot{}
+)
+
+// RegisterWasmBot compiles a module and registers it as "wasm:<name>",
+// replacing any earlier bot of that name. Games already running keep the
+// instances they have; the old bot is closed when they release them.
+func RegisterWasmBot(name string, wasm []byte) error {
+	bot, err := CompileWasmBot(name, wasm)
+	if err != nil {
+		return err
+	}
+	wasmMu.Lock()
+	old := wasmBots[name]
+	wasmBots[name] = bot
+	wasmMu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	RegisterController(WasmBotPrefix+name, bot.NewController)
+	return nil
+}
+
+// LoadWasmBots registers every <name>.wasm in dir. A module that does not
+// compile is reported and skipped.
+func LoadWasmBots(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.wasm"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	var errs []error
+	for _, path := range files {
+		wasm, err := os.ReadFile(path)
+		if err == nil {
+			err = RegisterWasmBot(strings.TrimSuffix(filepath.Base(path), ".wasm"), wasm)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, WasmBotPrefix+strings.TrimSuffix(filepath.Base(path), ".wasm"))
+	}
+	return names, errors.Join(errs...)
+}
+
+// WasmBotNames lists the registered WASM bots as controller names
+func WasmBotNames() []string {
+	wasmMu.Lock()
+	defer wasmMu.Unlock()
+	names := make([]string, 0, len(wasmBots))
+	for name := range wasmBots {
+		names = append(names, WasmBotPrefix+name)
+	}
+	sort.Strings(names)
+	return names
+}
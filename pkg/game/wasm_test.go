@@ -0,0 +1,108 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// wasmModule assembles a minimal bot: 1 page of exported memory, alloc
+// returning offset 1024 and get_action running body
+func wasmModule(body ...byte) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	getAction := append(append([]byte{0x00}, body...), 0x0b)
+
+	m := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	m = append(m, section(1, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f)...)
+	m = append(m, section(3, 0x02, 0x00, 0x01)...)
+	m = append(m, section(5, 0x01, 0x00, 0x01)...)
+	m = append(m, section(7, 0x03,
+		0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+		0x05, 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+		0x0a, 'g', 'e', 't', '_', 'a', 'c', 't', 'i', 'o', 'n', 0x00, 0x01)...)
+	code := []byte{0x02, 0x05, 0x00, 0x41, 0x80, 0x08, 0x0b, byte(len(getAction))}
+	return append(m, section(10, append(code, getAction...)...)...)
+}
+
+// TestWasmController runs hand-assembled bots: one that reads its
+// observation, one that never returns and one that traps
+func TestWasmController(t *testing.T) {
+	g := observationGame()
+	fallback := &ManualController{PendingAction: ActionData{Direction: Point{Y: 1}}}
+	controller := func(body ...byte) *WasmController {
+		t.Helper()
+		bot, err := CompileWasmBot("test", wasmModule(body...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(bot.Close)
+		c := bot.NewController().(*WasmController)
+		c.Timeout = 20 * time.Millisecond
+		c.Fallback = fallback
+		return c
+	}
+
+	// Up plus fire if the observation starts with '{', i.e. the JSON arrived
+	reader := controller(0x20, 0x00, 0x2d, 0x00, 0x00, 0x41, 0xfb, 0x00, 0x46, 0x41, 0x10, 0x72)
+	if a := reader.GetAction(g, 0); a.Direction != (Point{Y: -1}) || !a.Fire || a.Boost {
+		t.Errorf("expected up and fire, got %+v", a)
+	}
+
+	// An endless loop is cut off at the deadline
+	looper := controller(0x03, 0x40, 0x0c, 0x00, 0x0b, 0x41, 0x00)
+	start := time.Now()
+	if a := looper.GetAction(g, 0); a.Direction != (Point{Y: 1}) {
+		t.Errorf("expected the fallback move on timeout, got %+v", a)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("endless loop ran for %v", elapsed)
+	}
+
+	// A trap falls back, and the next move gets a fresh instance
+	trapper := controller(0x00)
+	for i := 0; i < 2; i++ {
+		if a := trapper.GetAction(g, 0); a.Direction != (Point{Y: 1}) {
+			t.Errorf("expected the fallback move on a trap, got %+v", a)
+		}
+	}
+	if answered, missed := trapper.Stats(); answered != 0 || missed != 2 {
+		t.Errorf("expected 0 answered and 2 missed, got %d and %d", answered, missed)
+	}
+
+	if _, err := CompileWasmBot("test", []byte("not wasm")); err == nil {
+		t.Error("expected an invalid module to be rejected")
+	}
+	if _, err := CompileWasmBot("../test", wasmModule(0x41, 0x00)); err == nil {
+		t.Error("expected an unsafe name to be rejected")
+	}
+}
+
+// TestWasmBotRelease keeps a closed bot running until its last controller
+// is released, which closes its runtime
+func TestWasmBotRelease(t *testing.T) {
+	g := observationGame()
+	bot, err := CompileWasmBot("test", wasmModule(0x20, 0x00, 0x2d, 0x00, 0x00, 0x41, 0xfb, 0x00, 0x46, 0x41, 0x10, 0x72))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := bot.NewController().(*WasmController)
+	c.Fallback = &ManualController{PendingAction: ActionData{Direction: Point{Y: 1}}}
+
+	bot.Close() // Replaced while c still plays
+	if a := c.GetAction(g, 0); a.Direction != (Point{Y: -1}) || !a.Fire {
+		t.Errorf("expected the bot to keep playing after Close, got %+v", a)
+	}
+
+	ReleaseController(c)
+	ReleaseController(c) // Twice is harmless
+	if bot.refs != 0 || c.mod != nil {
+		t.Errorf("expected the bot released, %d references and instance %v left", bot.refs, c.mod)
+	}
+	if a := c.GetAction(g, 0); a.Direction != (Point{Y: 1}) {
+		t.Errorf("expected the fallback move once released, got %+v", a)
+	}
+	if late := bot.NewController().(*WasmController); !late.released {
+		t.Error("expected a controller of a closed bot to start released")
+	}
+}
@@ -95,3 +95,8 @@ func IsRestart(input KeyInput) bool {
 func IsPause(input KeyInput) bool {
 	return input.Char == 'p' || input.Char == 'P' || input.Char == ' '
 }
+
+// IsAIDebug checks if the input toggles the AI decision overlay
+func IsAIDebug(input KeyInput) bool {
+	return input.Char == 'i' || input.Char == 'I'
+}
@@ -1,6 +1,7 @@
 package proto
 
 import (
+	"math"
 	"time"
 
 	"github.com/trytobebee/snake_go/pkg/game"
@@ -93,6 +94,39 @@ func ToProtoGameState(gs game.GameState) *GameStateSnapshot {
 		}
 	}
 
+	opponents := make([]*Opponent, len(gs.Opponents))
+	for i, o := range gs.Opponents {
+		body := make([]*Point, len(o.Snake))
+		for j, p := range o.Snake {
+			body[j] = ToProtoPoint(p)
+		}
+		opponents[i] = &Opponent{
+			Name:           o.Name,
+			Snake:          body,
+			Score:          int32(o.Score),
+			Stunned:        o.Stunned,
+			ControllerType: o.Controller,
+		}
+	}
+
+	var series *SeriesScore
+	if s := gs.Series; s != nil {
+		series = &SeriesScore{
+			BestOf: int32(s.BestOf),
+			Game:   int32(s.Game),
+			P1Wins: int32(s.P1Wins),
+			P2Wins: int32(s.P2Wins),
+			Draws:  int32(s.Draws),
+			Over:   s.Over,
+			Winner: s.Winner,
+		}
+	}
+
+	var aiDebug []*AIDecision
+	for _, d := range gs.AIDebug {
+		aiDebug = append(aiDebug, ToProtoAIDecision(d))
+	}
+
 	p2Effects := make([]*ActiveEffect, len(gs.P2Effects))
 	for i, e := range gs.P2Effects {
 		p2Effects[i] = &ActiveEffect{
@@ -134,6 +168,37 @@ func ToProtoGameState(gs game.GameState) *GameStateSnapshot {
 		Props:         props,
 		P1Effects:     p1Effects,
 		P2Effects:     p2Effects,
+		AiPersonality: gs.AIPersonality,
+		Opponents:     opponents,
+		Placement:     int32(gs.Placement),
+		AdaptiveLevel: int32(gs.AdaptiveLevel),
+		AiDebug:       aiDebug,
+		Series:        series,
+	}
+}
+
+func ToProtoAIDecision(d game.AIDecision) *AIDecision {
+	var target *Point
+	if d.TargetPos != nil {
+		target = ToProtoPoint(*d.TargetPos)
+	}
+	candidates := make([]*MoveCandidate, len(d.Candidates))
+	for i, c := range d.Candidates {
+		candidates[i] = &MoveCandidate{
+			Dir:   ToProtoPoint(c.Dir),
+			Safe:  c.Safe,
+			Space: int32(c.Space),
+			Score: c.Score,
+		}
+	}
+	return &AIDecision{
+		Player:      int32(d.Player),
+		Controller:  d.Controller,
+		Intent:      string(d.Intent),
+		Target:      target,
+		Urgency:     d.Urgency,
+		Personality: d.Personality,
+		Candidates:  candidates,
 	}
 }
 
@@ -158,6 +223,7 @@ func ToProtoLeaderboard(entries []game.LeaderboardEntry) []*LeaderboardEntry {
 			Date:       e.Date.Format(time.RFC3339),
 			Difficulty: e.Difficulty,
 			Mode:       e.Mode,
+			Opponents:  int32(e.Opponents),
 		}
 	}
 	return res
@@ -186,6 +252,7 @@ func ToProtoUser(u *game.User) *User {
 		TotalGames: int32(u.TotalGames),
 		TotalWins:  int32(u.TotalWins),
 		CreatedAt:  u.CreatedAt.Format(time.RFC3339),
+		Rating:     int32(math.Round(u.Rating)),
 	}
 }
 
@@ -207,3 +274,87 @@ func ToProtoServerMessage(typeStr string, config *game.GameConfig, state *game.G
 		SessionCount: int32(sessionCount),
 	}
 }
+
+func ToProtoRoomSettings(s game.RoomSettings) *RoomSettings {
+	return &RoomSettings{
+		Size:     s.Size,
+		Duration: int32(s.Duration.Seconds()),
+		Mode:     s.Mode,
+		Props:    s.Props,
+		Seats:    int32(s.Seats),
+		AiFill:   s.AIFill,
+	}
+}
+
+func FromProtoRoomSettings(s *RoomSettings) game.RoomSettings {
+	if s == nil {
+		return game.DefaultRoomSettings()
+	}
+	return game.RoomSettings{
+		Size:     s.Size,
+		Duration: time.Duration(s.Duration) * time.Second,
+		Mode:     s.Mode,
+		Props:    s.Props,
+		Seats:    int(s.Seats),
+		AIFill:   s.AiFill,
+	}
+}
+
+func FromProtoEnvConfig(c *EnvConfig) game.EnvConfig {
+	if c == nil {
+		return game.EnvConfig{}
+	}
+	return game.EnvConfig{
+		Width:       int(c.Width),
+		Height:      int(c.Height),
+		Mode:        c.Mode,
+		Opponent:    c.Opponent,
+		Opponents:   int(c.Opponents),
+		Speed:       c.Speed,
+		Observation: int(c.Observation),
+		Reward:      c.Reward,
+		Curriculum:  c.Curriculum,
+	}
+}
+
+func FromProtoEnvActions(actions []*EnvAction) []game.EnvAction {
+	out := make([]game.EnvAction, len(actions))
+	for i, a := range actions {
+		out[i] = game.EnvAction{
+			Direction: int(a.GetDirection()),
+			Boost:     a.GetBoost(),
+			Fire:      a.GetFire(),
+		}
+	}
+	return out
+}
+
+func ToProtoEnvSteps(steps []game.EnvStep) []*EnvStep {
+	out := make([]*EnvStep, len(steps))
+	for i, s := range steps {
+		grid := make([]float32, len(s.Observation.Grid))
+		for j, v := range s.Observation.Grid {
+			grid[j] = float32(v)
+		}
+		scalars := make([]float32, len(s.Observation.Scalars))
+		for j, v := range s.Observation.Scalars {
+			scalars[j] = float32(v)
+		}
+		out[i] = &EnvStep{
+			Grid:    grid,
+			Scalars: scalars,
+			Reward:  s.Reward,
+			Done:    s.Done,
+			Info: &EnvInfo{
+				Score:         int32(s.Info.Score),
+				OpponentScore: int32(s.Info.OpponentScore),
+				Winner:        s.Info.Winner,
+				Steps:         int32(s.Info.Steps),
+				Ticks:         int32(s.Info.Ticks),
+				Scenario:      s.Info.Scenario,
+				Difficulty:    s.Info.Difficulty,
+			},
+		}
+	}
+	return out
+}
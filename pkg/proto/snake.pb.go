@@ -357,6 +357,244 @@ func (x *Prop) GetType() int32 {
 	return 0
 }
 
+type Opponent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Snake          []*Point               `protobuf:"bytes,2,rep,name=snake,proto3" json:"snake,omitempty"`
+	Score          int32                  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	Stunned        bool                   `protobuf:"varint,4,opt,name=stunned,proto3" json:"stunned,omitempty"`
+	ControllerType string                 `protobuf:"bytes,5,opt,name=controllerType,proto3" json:"controllerType,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Opponent) Reset() {
+	*x = Opponent{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Opponent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Opponent) ProtoMessage() {}
+
+func (x *Opponent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Opponent.ProtoReflect.Descriptor instead.
+func (*Opponent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Opponent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Opponent) GetSnake() []*Point {
+	if x != nil {
+		return x.Snake
+	}
+	return nil
+}
+
+func (x *Opponent) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Opponent) GetStunned() bool {
+	if x != nil {
+		return x.Stunned
+	}
+	return false
+}
+
+func (x *Opponent) GetControllerType() string {
+	if x != nil {
+		return x.ControllerType
+	}
+	return ""
+}
+
+// One direction the heuristic AI weighed
+type MoveCandidate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dir           *Point                 `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	Safe          bool                   `protobuf:"varint,2,opt,name=safe,proto3" json:"safe,omitempty"`   // Unsafe moves are not scored
+	Space         int32                  `protobuf:"varint,3,opt,name=space,proto3" json:"space,omitempty"` // Flood-fill size from the next tile
+	Score         float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveCandidate) Reset() {
+	*x = MoveCandidate{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCandidate) ProtoMessage() {}
+
+func (x *MoveCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCandidate.ProtoReflect.Descriptor instead.
+func (*MoveCandidate) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MoveCandidate) GetDir() *Point {
+	if x != nil {
+		return x.Dir
+	}
+	return nil
+}
+
+func (x *MoveCandidate) GetSafe() bool {
+	if x != nil {
+		return x.Safe
+	}
+	return false
+}
+
+func (x *MoveCandidate) GetSpace() int32 {
+	if x != nil {
+		return x.Space
+	}
+	return 0
+}
+
+func (x *MoveCandidate) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// Latest decision of an AI-driven player, for the debug overlay
+type AIDecision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Player        int32                  `protobuf:"varint,1,opt,name=player,proto3" json:"player,omitempty"`
+	Controller    string                 `protobuf:"bytes,2,opt,name=controller,proto3" json:"controller,omitempty"`
+	Intent        string                 `protobuf:"bytes,3,opt,name=intent,proto3" json:"intent,omitempty"`
+	Target        *Point                 `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Urgency       float64                `protobuf:"fixed64,5,opt,name=urgency,proto3" json:"urgency,omitempty"`
+	Personality   string                 `protobuf:"bytes,6,opt,name=personality,proto3" json:"personality,omitempty"`
+	Candidates    []*MoveCandidate       `protobuf:"bytes,7,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AIDecision) Reset() {
+	*x = AIDecision{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AIDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AIDecision) ProtoMessage() {}
+
+func (x *AIDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AIDecision.ProtoReflect.Descriptor instead.
+func (*AIDecision) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AIDecision) GetPlayer() int32 {
+	if x != nil {
+		return x.Player
+	}
+	return 0
+}
+
+func (x *AIDecision) GetController() string {
+	if x != nil {
+		return x.Controller
+	}
+	return ""
+}
+
+func (x *AIDecision) GetIntent() string {
+	if x != nil {
+		return x.Intent
+	}
+	return ""
+}
+
+func (x *AIDecision) GetTarget() *Point {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *AIDecision) GetUrgency() float64 {
+	if x != nil {
+		return x.Urgency
+	}
+	return 0
+}
+
+func (x *AIDecision) GetPersonality() string {
+	if x != nil {
+		return x.Personality
+	}
+	return ""
+}
+
+func (x *AIDecision) GetCandidates() []*MoveCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
 type ActiveEffect struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // EffectType
@@ -367,7 +605,7 @@ type ActiveEffect struct {
 
 func (x *ActiveEffect) Reset() {
 	*x = ActiveEffect{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[6]
+	mi := &file_pkg_proto_snake_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -379,7 +617,7 @@ func (x *ActiveEffect) String() string {
 func (*ActiveEffect) ProtoMessage() {}
 
 func (x *ActiveEffect) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[6]
+	mi := &file_pkg_proto_snake_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -392,7 +630,7 @@ func (x *ActiveEffect) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActiveEffect.ProtoReflect.Descriptor instead.
 func (*ActiveEffect) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{6}
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ActiveEffect) GetType() string {
@@ -416,13 +654,14 @@ type LeaderboardEntry struct {
 	Date          string                 `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"` // ISO string
 	Difficulty    string                 `protobuf:"bytes,4,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
 	Mode          string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	Opponents     int32                  `protobuf:"varint,6,opt,name=opponents,proto3" json:"opponents,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LeaderboardEntry) Reset() {
 	*x = LeaderboardEntry{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[7]
+	mi := &file_pkg_proto_snake_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -434,7 +673,7 @@ func (x *LeaderboardEntry) String() string {
 func (*LeaderboardEntry) ProtoMessage() {}
 
 func (x *LeaderboardEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[7]
+	mi := &file_pkg_proto_snake_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -447,7 +686,7 @@ func (x *LeaderboardEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LeaderboardEntry.ProtoReflect.Descriptor instead.
 func (*LeaderboardEntry) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{7}
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *LeaderboardEntry) GetName() string {
@@ -485,6 +724,13 @@ func (x *LeaderboardEntry) GetMode() string {
 	return ""
 }
 
+func (x *LeaderboardEntry) GetOpponents() int32 {
+	if x != nil {
+		return x.Opponents
+	}
+	return 0
+}
+
 type WinRateEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -497,7 +743,7 @@ type WinRateEntry struct {
 
 func (x *WinRateEntry) Reset() {
 	*x = WinRateEntry{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[8]
+	mi := &file_pkg_proto_snake_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -509,7 +755,7 @@ func (x *WinRateEntry) String() string {
 func (*WinRateEntry) ProtoMessage() {}
 
 func (x *WinRateEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[8]
+	mi := &file_pkg_proto_snake_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -522,7 +768,7 @@ func (x *WinRateEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WinRateEntry.ProtoReflect.Descriptor instead.
 func (*WinRateEntry) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{8}
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *WinRateEntry) GetName() string {
@@ -560,13 +806,14 @@ type User struct {
 	TotalGames    int32                  `protobuf:"varint,3,opt,name=total_games,json=totalGames,proto3" json:"total_games,omitempty"`
 	TotalWins     int32                  `protobuf:"varint,4,opt,name=total_wins,json=totalWins,proto3" json:"total_wins,omitempty"`
 	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO string
+	Rating        int32                  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`                       // Matchmaking rating
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[9]
+	mi := &file_pkg_proto_snake_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -578,7 +825,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[9]
+	mi := &file_pkg_proto_snake_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -591,7 +838,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{9}
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *User) GetUsername() string {
@@ -629,6 +876,13 @@ func (x *User) GetCreatedAt() string {
 	return ""
 }
 
+func (x *User) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
 type GameStateSnapshot struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Snake         []*Point               `protobuf:"bytes,1,rep,name=snake,proto3" json:"snake,omitempty"`
@@ -663,13 +917,20 @@ type GameStateSnapshot struct {
 	Props         []*Prop                `protobuf:"bytes,30,rep,name=props,proto3" json:"props,omitempty"`
 	P1Effects     []*ActiveEffect        `protobuf:"bytes,31,rep,name=p1Effects,proto3" json:"p1Effects,omitempty"`
 	P2Effects     []*ActiveEffect        `protobuf:"bytes,32,rep,name=p2Effects,proto3" json:"p2Effects,omitempty"`
+	AiPersonality string                 `protobuf:"bytes,33,opt,name=aiPersonality,proto3" json:"aiPersonality,omitempty"`
+	Opponents     []*Opponent            `protobuf:"bytes,34,rep,name=opponents,proto3" json:"opponents,omitempty"` // Extra AI snakes in free-for-all
+	Placement     int32                  `protobuf:"varint,35,opt,name=placement,proto3" json:"placement,omitempty"`
+	AdaptiveLevel int32                  `protobuf:"varint,36,opt,name=adaptiveLevel,proto3" json:"adaptiveLevel,omitempty"` // Dynamic difficulty level 1-10 (0 = off)
+	AiDebug       []*AIDecision          `protobuf:"bytes,37,rep,name=aiDebug,proto3" json:"aiDebug,omitempty"`              // Only while the AI debug overlay is on
+	Spectators    int32                  `protobuf:"varint,38,opt,name=spectators,proto3" json:"spectators,omitempty"`       // People watching the match (cmd/webserver)
+	Series        *SeriesScore           `protobuf:"bytes,39,opt,name=series,proto3" json:"series,omitempty"`                // Best-of-N series, unset for a single game
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GameStateSnapshot) Reset() {
 	*x = GameStateSnapshot{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[10]
+	mi := &file_pkg_proto_snake_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -681,7 +942,7 @@ func (x *GameStateSnapshot) String() string {
 func (*GameStateSnapshot) ProtoMessage() {}
 
 func (x *GameStateSnapshot) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[10]
+	mi := &file_pkg_proto_snake_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -694,7 +955,7 @@ func (x *GameStateSnapshot) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameStateSnapshot.ProtoReflect.Descriptor instead.
 func (*GameStateSnapshot) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{10}
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GameStateSnapshot) GetSnake() []*Point {
@@ -921,75 +1182,599 @@ func (x *GameStateSnapshot) GetP2Effects() []*ActiveEffect {
 	return nil
 }
 
-type GameConfig struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Width            int32                  `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
-	Height           int32                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
-	GameDuration     int32                  `protobuf:"varint,3,opt,name=gameDuration,proto3" json:"gameDuration,omitempty"`
-	FireballCooldown int32                  `protobuf:"varint,4,opt,name=fireballCooldown,proto3" json:"fireballCooldown,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
-}
-
-func (x *GameConfig) Reset() {
-	*x = GameConfig{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *GameConfig) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GameStateSnapshot) GetAiPersonality() string {
+	if x != nil {
+		return x.AiPersonality
+	}
+	return ""
 }
 
-func (*GameConfig) ProtoMessage() {}
-
-func (x *GameConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[11]
+func (x *GameStateSnapshot) GetOpponents() []*Opponent {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Opponents
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use GameConfig.ProtoReflect.Descriptor instead.
-func (*GameConfig) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{11}
+func (x *GameStateSnapshot) GetPlacement() int32 {
+	if x != nil {
+		return x.Placement
+	}
+	return 0
 }
 
-func (x *GameConfig) GetWidth() int32 {
+func (x *GameStateSnapshot) GetAdaptiveLevel() int32 {
 	if x != nil {
-		return x.Width
+		return x.AdaptiveLevel
 	}
 	return 0
 }
 
-func (x *GameConfig) GetHeight() int32 {
+func (x *GameStateSnapshot) GetAiDebug() []*AIDecision {
 	if x != nil {
-		return x.Height
+		return x.AiDebug
 	}
-	return 0
+	return nil
 }
 
-func (x *GameConfig) GetGameDuration() int32 {
+func (x *GameStateSnapshot) GetSpectators() int32 {
 	if x != nil {
-		return x.GameDuration
+		return x.Spectators
 	}
 	return 0
 }
 
-func (x *GameConfig) GetFireballCooldown() int32 {
+func (x *GameStateSnapshot) GetSeries() *SeriesScore {
 	if x != nil {
-		return x.FireballCooldown
+		return x.Series
 	}
-	return 0
+	return nil
 }
 
-type ServerMessage struct {
+type SeriesScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BestOf        int32                  `protobuf:"varint,1,opt,name=bestOf,proto3" json:"bestOf,omitempty"`
+	Game          int32                  `protobuf:"varint,2,opt,name=game,proto3" json:"game,omitempty"` // Game being played, or the last one once the series is over
+	P1Wins        int32                  `protobuf:"varint,3,opt,name=p1Wins,proto3" json:"p1Wins,omitempty"`
+	P2Wins        int32                  `protobuf:"varint,4,opt,name=p2Wins,proto3" json:"p2Wins,omitempty"`
+	Draws         int32                  `protobuf:"varint,5,opt,name=draws,proto3" json:"draws,omitempty"`
+	Over          bool                   `protobuf:"varint,6,opt,name=over,proto3" json:"over,omitempty"`
+	Winner        string                 `protobuf:"bytes,7,opt,name=winner,proto3" json:"winner,omitempty"` // Series winner, empty while it runs or if it ended even
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeriesScore) Reset() {
+	*x = SeriesScore{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeriesScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeriesScore) ProtoMessage() {}
+
+func (x *SeriesScore) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeriesScore.ProtoReflect.Descriptor instead.
+func (*SeriesScore) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SeriesScore) GetBestOf() int32 {
+	if x != nil {
+		return x.BestOf
+	}
+	return 0
+}
+
+func (x *SeriesScore) GetGame() int32 {
+	if x != nil {
+		return x.Game
+	}
+	return 0
+}
+
+func (x *SeriesScore) GetP1Wins() int32 {
+	if x != nil {
+		return x.P1Wins
+	}
+	return 0
+}
+
+func (x *SeriesScore) GetP2Wins() int32 {
+	if x != nil {
+		return x.P2Wins
+	}
+	return 0
+}
+
+func (x *SeriesScore) GetDraws() int32 {
+	if x != nil {
+		return x.Draws
+	}
+	return 0
+}
+
+func (x *SeriesScore) GetOver() bool {
+	if x != nil {
+		return x.Over
+	}
+	return false
+}
+
+func (x *SeriesScore) GetWinner() string {
+	if x != nil {
+		return x.Winner
+	}
+	return ""
+}
+
+type GameConfig struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Width            int32                  `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height           int32                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	GameDuration     int32                  `protobuf:"varint,3,opt,name=gameDuration,proto3" json:"gameDuration,omitempty"`
+	FireballCooldown int32                  `protobuf:"varint,4,opt,name=fireballCooldown,proto3" json:"fireballCooldown,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GameConfig) Reset() {
+	*x = GameConfig{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameConfig) ProtoMessage() {}
+
+func (x *GameConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameConfig.ProtoReflect.Descriptor instead.
+func (*GameConfig) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GameConfig) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *GameConfig) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *GameConfig) GetGameDuration() int32 {
+	if x != nil {
+		return x.GameDuration
+	}
+	return 0
+}
+
+func (x *GameConfig) GetFireballCooldown() int32 {
+	if x != nil {
+		return x.FireballCooldown
+	}
+	return 0
+}
+
+// Rules a room host picks (cmd/webserver rooms)
+type RoomSettings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          string                 `protobuf:"bytes,1,opt,name=size,proto3" json:"size,omitempty"`          // "standard" or "large"
+	Duration      int32                  `protobuf:"varint,2,opt,name=duration,proto3" json:"duration,omitempty"` // Seconds
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`          // "battle" or "pvp"
+	Props         bool                   `protobuf:"varint,4,opt,name=props,proto3" json:"props,omitempty"`
+	Seats         int32                  `protobuf:"varint,5,opt,name=seats,proto3" json:"seats,omitempty"`   // Snakes on the board, 2-8
+	AiFill        bool                   `protobuf:"varint,6,opt,name=aiFill,proto3" json:"aiFill,omitempty"` // Free seats are played by the AI
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomSettings) Reset() {
+	*x = RoomSettings{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomSettings) ProtoMessage() {}
+
+func (x *RoomSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomSettings.ProtoReflect.Descriptor instead.
+func (*RoomSettings) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RoomSettings) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+func (x *RoomSettings) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RoomSettings) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *RoomSettings) GetProps() bool {
+	if x != nil {
+		return x.Props
+	}
+	return false
+}
+
+func (x *RoomSettings) GetSeats() int32 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
+func (x *RoomSettings) GetAiFill() bool {
+	if x != nil {
+		return x.AiFill
+	}
+	return false
+}
+
+type RoomMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ready         bool                   `protobuf:"varint,2,opt,name=ready,proto3" json:"ready,omitempty"`
+	Host          bool                   `protobuf:"varint,3,opt,name=host,proto3" json:"host,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomMember) Reset() {
+	*x = RoomMember{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomMember) ProtoMessage() {}
+
+func (x *RoomMember) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomMember.ProtoReflect.Descriptor instead.
+func (*RoomMember) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RoomMember) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomMember) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *RoomMember) GetHost() bool {
+	if x != nil {
+		return x.Host
+	}
+	return false
+}
+
+type RoomState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // Empty once the player has left
+	Settings      *RoomSettings          `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	Members       []*RoomMember          `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	Playing       bool                   `protobuf:"varint,4,opt,name=playing,proto3" json:"playing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomState) Reset() {
+	*x = RoomState{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomState) ProtoMessage() {}
+
+func (x *RoomState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomState.ProtoReflect.Descriptor instead.
+func (*RoomState) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RoomState) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RoomState) GetSettings() *RoomSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *RoomState) GetMembers() []*RoomMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *RoomState) GetPlaying() bool {
+	if x != nil {
+		return x.Playing
+	}
+	return false
+}
+
+// Matchmaking progress of a waiting player (cmd/webserver)
+type QueueStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int32                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`         // Players searching
+	Waited        int32                  `protobuf:"varint,2,opt,name=waited,proto3" json:"waited,omitempty"`     // Seconds so far
+	Estimate      int32                  `protobuf:"varint,3,opt,name=estimate,proto3" json:"estimate,omitempty"` // Seconds still to wait, roughly
+	Rating        int32                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`     // The player's rating
+	Gap           int32                  `protobuf:"varint,5,opt,name=gap,proto3" json:"gap,omitempty"`           // Rating gap the player accepts by now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueStatus) Reset() {
+	*x = QueueStatus{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStatus) ProtoMessage() {}
+
+func (x *QueueStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStatus.ProtoReflect.Descriptor instead.
+func (*QueueStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *QueueStatus) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetWaited() int32 {
+	if x != nil {
+		return x.Waited
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetEstimate() int32 {
+	if x != nil {
+		return x.Estimate
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetGap() int32 {
+	if x != nil {
+		return x.Gap
+	}
+	return 0
+}
+
+// A match open to spectators (cmd/webserver)
+type LiveMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Players       []string               `protobuf:"bytes,2,rep,name=players,proto3" json:"players,omitempty"` // Human players
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	Room          string                 `protobuf:"bytes,4,opt,name=room,proto3" json:"room,omitempty"`    // Room code, empty for matchmaking
+	Seats         int32                  `protobuf:"varint,5,opt,name=seats,proto3" json:"seats,omitempty"` // Snakes on the board
+	Spectators    int32                  `protobuf:"varint,6,opt,name=spectators,proto3" json:"spectators,omitempty"`
+	TimeLeft      int32                  `protobuf:"varint,7,opt,name=timeLeft,proto3" json:"timeLeft,omitempty"` // Seconds, the full length while counting down
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LiveMatch) Reset() {
+	*x = LiveMatch{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LiveMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiveMatch) ProtoMessage() {}
+
+func (x *LiveMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiveMatch.ProtoReflect.Descriptor instead.
+func (*LiveMatch) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LiveMatch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LiveMatch) GetPlayers() []string {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *LiveMatch) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *LiveMatch) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *LiveMatch) GetSeats() int32 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
+func (x *LiveMatch) GetSpectators() int32 {
+	if x != nil {
+		return x.Spectators
+	}
+	return 0
+}
+
+func (x *LiveMatch) GetTimeLeft() int32 {
+	if x != nil {
+		return x.TimeLeft
+	}
+	return 0
+}
+
+type ServerMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
 	Config        *GameConfig            `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
@@ -1000,25 +1785,529 @@ type ServerMessage struct {
 	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
 	Success       string                 `protobuf:"bytes,8,opt,name=success,proto3" json:"success,omitempty"`
 	SessionCount  int32                  `protobuf:"varint,9,opt,name=sessionCount,proto3" json:"sessionCount,omitempty"`
+	Room          *RoomState             `protobuf:"bytes,10,opt,name=room,proto3" json:"room,omitempty"`
+	Matches       []*LiveMatch           `protobuf:"bytes,11,rep,name=matches,proto3" json:"matches,omitempty"`      // "matches"
+	Watching      *LiveMatch             `protobuf:"bytes,12,opt,name=watching,proto3" json:"watching,omitempty"`    // "spectate": the watched match, unset once watching stopped
+	QueueSize     int32                  `protobuf:"varint,13,opt,name=queueSize,proto3" json:"queueSize,omitempty"` // "update_counts": players searching for a match
+	Queue         *QueueStatus           `protobuf:"bytes,14,opt,name=queue,proto3" json:"queue,omitempty"`          // "queue"
+	Rematch       *Rematch               `protobuf:"bytes,15,opt,name=rematch,proto3" json:"rematch,omitempty"`      // "rematch"
+	Session       *Session               `protobuf:"bytes,16,opt,name=session,proto3" json:"session,omitempty"`      // "session"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerMessage) Reset() {
+	*x = ServerMessage{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerMessage) ProtoMessage() {}
+
+func (x *ServerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerMessage.ProtoReflect.Descriptor instead.
+func (*ServerMessage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ServerMessage) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetConfig() *GameConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetState() *GameStateSnapshot {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetLeaderboard() []*LeaderboardEntry {
+	if x != nil {
+		return x.Leaderboard
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetWinRates() []*WinRateEntry {
+	if x != nil {
+		return x.WinRates
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetSuccess() string {
+	if x != nil {
+		return x.Success
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetSessionCount() int32 {
+	if x != nil {
+		return x.SessionCount
+	}
+	return 0
+}
+
+func (x *ServerMessage) GetRoom() *RoomState {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetMatches() []*LiveMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetWatching() *LiveMatch {
+	if x != nil {
+		return x.Watching
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetQueueSize() int32 {
+	if x != nil {
+		return x.QueueSize
+	}
+	return 0
+}
+
+func (x *ServerMessage) GetQueue() *QueueStatus {
+	if x != nil {
+		return x.Queue
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetRematch() *Rematch {
+	if x != nil {
+		return x.Rematch
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+// Resume token of a web connection. The user is set on a resumed session.
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`      // Connect to /ws?resume=<token> to get this session back after the connection dropped
+	Grace         int32                  `protobuf:"varint,2,opt,name=grace,proto3" json:"grace,omitempty"`     // Seconds the session waits for that
+	Resumed       bool                   `protobuf:"varint,3,opt,name=resumed,proto3" json:"resumed,omitempty"` // This connection took over an existing session
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Session) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Session) GetGrace() int32 {
+	if x != nil {
+		return x.Grace
+	}
+	return 0
+}
+
+func (x *Session) GetResumed() bool {
+	if x != nil {
+		return x.Resumed
+	}
+	return false
+}
+
+// Rematch offers between the two players of the last matchmaking game
+type Rematch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`  // "offer" (from the opponent), "sent", "declined", "gone" or "unavailable"
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`      // Player the offer came from
+	BestOf        int32                  `protobuf:"varint,3,opt,name=bestOf,proto3" json:"bestOf,omitempty"` // Series length offered: 1 (single game), 3 or 5
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rematch) Reset() {
+	*x = Rematch{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rematch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rematch) ProtoMessage() {}
+
+func (x *Rematch) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rematch.ProtoReflect.Descriptor instead.
+func (*Rematch) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Rematch) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Rematch) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Rematch) GetBestOf() int32 {
+	if x != nil {
+		return x.BestOf
+	}
+	return 0
+}
+
+type ClientMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	Mode          string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	Feedback      string                 `protobuf:"bytes,6,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	RoomSettings  *RoomSettings          `protobuf:"bytes,7,opt,name=roomSettings,proto3" json:"roomSettings,omitempty"` // room_create and room_settings
+	Code          string                 `protobuf:"bytes,8,opt,name=code,proto3" json:"code,omitempty"`                 // room_join, spectate (match id)
+	Target        string                 `protobuf:"bytes,9,opt,name=target,proto3" json:"target,omitempty"`             // room_kick
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientMessage) Reset() {
+	*x = ClientMessage{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientMessage) ProtoMessage() {}
+
+func (x *ClientMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientMessage.ProtoReflect.Descriptor instead.
+func (*ClientMessage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ClientMessage) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ClientMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClientMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ClientMessage) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ClientMessage) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ClientMessage) GetFeedback() string {
+	if x != nil {
+		return x.Feedback
+	}
+	return ""
+}
+
+func (x *ClientMessage) GetRoomSettings() *RoomSettings {
+	if x != nil {
+		return x.RoomSettings
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ClientMessage) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type EnvConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Width         int32                  `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`         // "battle" or "pvp"
+	Opponent      string                 `protobuf:"bytes,4,opt,name=opponent,proto3" json:"opponent,omitempty"` // Registered controller name
+	Opponents     int32                  `protobuf:"varint,5,opt,name=opponents,proto3" json:"opponents,omitempty"`
+	Speed         string                 `protobuf:"bytes,6,opt,name=speed,proto3" json:"speed,omitempty"`
+	AutoReset     bool                   `protobuf:"varint,7,opt,name=autoReset,proto3" json:"autoReset,omitempty"`
+	Observation   int32                  `protobuf:"varint,8,opt,name=observation,proto3" json:"observation,omitempty"` // Observation spec version, 0 means 1
+	Reward        string                 `protobuf:"bytes,9,opt,name=reward,proto3" json:"reward,omitempty"`            // Reward preset, "" means default
+	Curriculum    string                 `protobuf:"bytes,10,opt,name=curriculum,proto3" json:"curriculum,omitempty"`   // Scenario curriculum such as "all@0-0.5", "" for normal starts
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvConfig) Reset() {
+	*x = EnvConfig{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvConfig) ProtoMessage() {}
+
+func (x *EnvConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvConfig.ProtoReflect.Descriptor instead.
+func (*EnvConfig) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *EnvConfig) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *EnvConfig) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *EnvConfig) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *EnvConfig) GetOpponent() string {
+	if x != nil {
+		return x.Opponent
+	}
+	return ""
+}
+
+func (x *EnvConfig) GetOpponents() int32 {
+	if x != nil {
+		return x.Opponents
+	}
+	return 0
+}
+
+func (x *EnvConfig) GetSpeed() string {
+	if x != nil {
+		return x.Speed
+	}
+	return ""
+}
+
+func (x *EnvConfig) GetAutoReset() bool {
+	if x != nil {
+		return x.AutoReset
+	}
+	return false
+}
+
+func (x *EnvConfig) GetObservation() int32 {
+	if x != nil {
+		return x.Observation
+	}
+	return 0
+}
+
+func (x *EnvConfig) GetReward() string {
+	if x != nil {
+		return x.Reward
+	}
+	return ""
+}
+
+func (x *EnvConfig) GetCurriculum() string {
+	if x != nil {
+		return x.Curriculum
+	}
+	return ""
+}
+
+type EnvAction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Direction     int32                  `protobuf:"varint,1,opt,name=direction,proto3" json:"direction,omitempty"` // 0 up, 1 down, 2 left, 3 right, -1 keep heading
+	Boost         bool                   `protobuf:"varint,2,opt,name=boost,proto3" json:"boost,omitempty"`
+	Fire          bool                   `protobuf:"varint,3,opt,name=fire,proto3" json:"fire,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ServerMessage) Reset() {
-	*x = ServerMessage{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[12]
+func (x *EnvAction) Reset() {
+	*x = EnvAction{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ServerMessage) String() string {
+func (x *EnvAction) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServerMessage) ProtoMessage() {}
+func (*EnvAction) ProtoMessage() {}
 
-func (x *ServerMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[12]
+func (x *EnvAction) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1029,101 +2318,150 @@ func (x *ServerMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServerMessage.ProtoReflect.Descriptor instead.
-func (*ServerMessage) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use EnvAction.ProtoReflect.Descriptor instead.
+func (*EnvAction) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *ServerMessage) GetType() string {
+func (x *EnvAction) GetDirection() int32 {
 	if x != nil {
-		return x.Type
+		return x.Direction
 	}
-	return ""
+	return 0
 }
 
-func (x *ServerMessage) GetConfig() *GameConfig {
+func (x *EnvAction) GetBoost() bool {
 	if x != nil {
-		return x.Config
+		return x.Boost
 	}
-	return nil
+	return false
 }
 
-func (x *ServerMessage) GetState() *GameStateSnapshot {
+func (x *EnvAction) GetFire() bool {
 	if x != nil {
-		return x.State
+		return x.Fire
 	}
-	return nil
+	return false
 }
 
-func (x *ServerMessage) GetLeaderboard() []*LeaderboardEntry {
+type EnvInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         int32                  `protobuf:"varint,1,opt,name=score,proto3" json:"score,omitempty"`
+	OpponentScore int32                  `protobuf:"varint,2,opt,name=opponentScore,proto3" json:"opponentScore,omitempty"`
+	Winner        string                 `protobuf:"bytes,3,opt,name=winner,proto3" json:"winner,omitempty"`
+	Steps         int32                  `protobuf:"varint,4,opt,name=steps,proto3" json:"steps,omitempty"`
+	Ticks         int32                  `protobuf:"varint,5,opt,name=ticks,proto3" json:"ticks,omitempty"`
+	Scenario      string                 `protobuf:"bytes,6,opt,name=scenario,proto3" json:"scenario,omitempty"` // Curriculum scenario of the episode, if any
+	Difficulty    float64                `protobuf:"fixed64,7,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvInfo) Reset() {
+	*x = EnvInfo{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvInfo) ProtoMessage() {}
+
+func (x *EnvInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[27]
 	if x != nil {
-		return x.Leaderboard
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ServerMessage) GetWinRates() []*WinRateEntry {
+// Deprecated: Use EnvInfo.ProtoReflect.Descriptor instead.
+func (*EnvInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *EnvInfo) GetScore() int32 {
 	if x != nil {
-		return x.WinRates
+		return x.Score
 	}
-	return nil
+	return 0
 }
 
-func (x *ServerMessage) GetUser() *User {
+func (x *EnvInfo) GetOpponentScore() int32 {
 	if x != nil {
-		return x.User
+		return x.OpponentScore
 	}
-	return nil
+	return 0
 }
 
-func (x *ServerMessage) GetError() string {
+func (x *EnvInfo) GetWinner() string {
 	if x != nil {
-		return x.Error
+		return x.Winner
 	}
 	return ""
 }
 
-func (x *ServerMessage) GetSuccess() string {
+func (x *EnvInfo) GetSteps() int32 {
 	if x != nil {
-		return x.Success
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *EnvInfo) GetTicks() int32 {
+	if x != nil {
+		return x.Ticks
+	}
+	return 0
+}
+
+func (x *EnvInfo) GetScenario() string {
+	if x != nil {
+		return x.Scenario
 	}
 	return ""
 }
 
-func (x *ServerMessage) GetSessionCount() int32 {
+func (x *EnvInfo) GetDifficulty() float64 {
 	if x != nil {
-		return x.SessionCount
+		return x.Difficulty
 	}
 	return 0
 }
 
-type ClientMessage struct {
+type EnvStep struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
-	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
-	Mode          string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
-	Feedback      string                 `protobuf:"bytes,6,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	Grid          []float32              `protobuf:"fixed32,1,rep,packed,name=grid,proto3" json:"grid,omitempty"`       // Cx25x25 observation, channel-major, C = len(EnvResponse.channelNames)
+	Scalars       []float32              `protobuf:"fixed32,2,rep,packed,name=scalars,proto3" json:"scalars,omitempty"` // Named by EnvResponse.scalarNames
+	Reward        float64                `protobuf:"fixed64,3,opt,name=reward,proto3" json:"reward,omitempty"`
+	Done          bool                   `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	Info          *EnvInfo               `protobuf:"bytes,5,opt,name=info,proto3" json:"info,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ClientMessage) Reset() {
-	*x = ClientMessage{}
-	mi := &file_pkg_proto_snake_proto_msgTypes[13]
+func (x *EnvStep) Reset() {
+	*x = EnvStep{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ClientMessage) String() string {
+func (x *EnvStep) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClientMessage) ProtoMessage() {}
+func (*EnvStep) ProtoMessage() {}
 
-func (x *ClientMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_snake_proto_msgTypes[13]
+func (x *EnvStep) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1134,53 +2472,190 @@ func (x *ClientMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClientMessage.ProtoReflect.Descriptor instead.
-func (*ClientMessage) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use EnvStep.ProtoReflect.Descriptor instead.
+func (*EnvStep) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *ClientMessage) GetAction() string {
+func (x *EnvStep) GetGrid() []float32 {
 	if x != nil {
-		return x.Action
+		return x.Grid
 	}
-	return ""
+	return nil
 }
 
-func (x *ClientMessage) GetName() string {
+func (x *EnvStep) GetScalars() []float32 {
 	if x != nil {
-		return x.Name
+		return x.Scalars
 	}
-	return ""
+	return nil
 }
 
-func (x *ClientMessage) GetUsername() string {
+func (x *EnvStep) GetReward() float64 {
 	if x != nil {
-		return x.Username
+		return x.Reward
 	}
-	return ""
+	return 0
 }
 
-func (x *ClientMessage) GetPassword() string {
+func (x *EnvStep) GetDone() bool {
 	if x != nil {
-		return x.Password
+		return x.Done
+	}
+	return false
+}
+
+func (x *EnvStep) GetInfo() *EnvInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type EnvRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "reset" or "step"
+	Seeds         []int64                `protobuf:"varint,2,rep,packed,name=seeds,proto3" json:"seeds,omitempty"`
+	Config        *EnvConfig             `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	Actions       []*EnvAction           `protobuf:"bytes,4,rep,name=actions,proto3" json:"actions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvRequest) Reset() {
+	*x = EnvRequest{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvRequest) ProtoMessage() {}
+
+func (x *EnvRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvRequest.ProtoReflect.Descriptor instead.
+func (*EnvRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *EnvRequest) GetType() string {
+	if x != nil {
+		return x.Type
 	}
 	return ""
 }
 
-func (x *ClientMessage) GetMode() string {
+func (x *EnvRequest) GetSeeds() []int64 {
 	if x != nil {
-		return x.Mode
+		return x.Seeds
+	}
+	return nil
+}
+
+func (x *EnvRequest) GetConfig() *EnvConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *EnvRequest) GetActions() []*EnvAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+type EnvResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Steps         []*EnvStep             `protobuf:"bytes,2,rep,name=steps,proto3" json:"steps,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	ScalarNames   []string               `protobuf:"bytes,4,rep,name=scalarNames,proto3" json:"scalarNames,omitempty"`
+	ChannelNames  []string               `protobuf:"bytes,5,rep,name=channelNames,proto3" json:"channelNames,omitempty"` // Grid planes in order, sent on reset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvResponse) Reset() {
+	*x = EnvResponse{}
+	mi := &file_pkg_proto_snake_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvResponse) ProtoMessage() {}
+
+func (x *EnvResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snake_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvResponse.ProtoReflect.Descriptor instead.
+func (*EnvResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snake_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *EnvResponse) GetType() string {
+	if x != nil {
+		return x.Type
 	}
 	return ""
 }
 
-func (x *ClientMessage) GetFeedback() string {
+func (x *EnvResponse) GetSteps() []*EnvStep {
 	if x != nil {
-		return x.Feedback
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *EnvResponse) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
+func (x *EnvResponse) GetScalarNames() []string {
+	if x != nil {
+		return x.ScalarNames
+	}
+	return nil
+}
+
+func (x *EnvResponse) GetChannelNames() []string {
+	if x != nil {
+		return x.ChannelNames
+	}
+	return nil
+}
+
 var File_pkg_proto_snake_proto protoreflect.FileDescriptor
 
 const file_pkg_proto_snake_proto_rawDesc = "" +
@@ -1207,10 +2682,34 @@ const file_pkg_proto_snake_proto_rawDesc = "" +
 	"\x05label\x18\x03 \x01(\tR\x05label\":\n" +
 	"\x04Prop\x12\x1e\n" +
 	"\x03pos\x18\x01 \x01(\v2\f.snake.PointR\x03pos\x12\x12\n" +
-	"\x04type\x18\x02 \x01(\x05R\x04type\">\n" +
+	"\x04type\x18\x02 \x01(\x05R\x04type\"\x9a\x01\n" +
+	"\bOpponent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\"\n" +
+	"\x05snake\x18\x02 \x03(\v2\f.snake.PointR\x05snake\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x05R\x05score\x12\x18\n" +
+	"\astunned\x18\x04 \x01(\bR\astunned\x12&\n" +
+	"\x0econtrollerType\x18\x05 \x01(\tR\x0econtrollerType\"o\n" +
+	"\rMoveCandidate\x12\x1e\n" +
+	"\x03dir\x18\x01 \x01(\v2\f.snake.PointR\x03dir\x12\x12\n" +
+	"\x04safe\x18\x02 \x01(\bR\x04safe\x12\x14\n" +
+	"\x05space\x18\x03 \x01(\x05R\x05space\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\"\xf4\x01\n" +
+	"\n" +
+	"AIDecision\x12\x16\n" +
+	"\x06player\x18\x01 \x01(\x05R\x06player\x12\x1e\n" +
+	"\n" +
+	"controller\x18\x02 \x01(\tR\n" +
+	"controller\x12\x16\n" +
+	"\x06intent\x18\x03 \x01(\tR\x06intent\x12$\n" +
+	"\x06target\x18\x04 \x01(\v2\f.snake.PointR\x06target\x12\x18\n" +
+	"\aurgency\x18\x05 \x01(\x01R\aurgency\x12 \n" +
+	"\vpersonality\x18\x06 \x01(\tR\vpersonality\x124\n" +
+	"\n" +
+	"candidates\x18\a \x03(\v2\x14.snake.MoveCandidateR\n" +
+	"candidates\">\n" +
 	"\fActiveEffect\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1a\n" +
-	"\bduration\x18\x02 \x01(\x01R\bduration\"\x84\x01\n" +
+	"\bduration\x18\x02 \x01(\x01R\bduration\"\xa2\x01\n" +
 	"\x10LeaderboardEntry\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05score\x18\x02 \x01(\x05R\x05score\x12\x12\n" +
@@ -1218,14 +2717,15 @@ const file_pkg_proto_snake_proto_rawDesc = "" +
 	"\n" +
 	"difficulty\x18\x04 \x01(\tR\n" +
 	"difficulty\x12\x12\n" +
-	"\x04mode\x18\x05 \x01(\tR\x04mode\"}\n" +
+	"\x04mode\x18\x05 \x01(\tR\x04mode\x12\x1c\n" +
+	"\topponents\x18\x06 \x01(\x05R\topponents\"}\n" +
 	"\fWinRateEntry\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
 	"\bwin_rate\x18\x02 \x01(\x01R\awinRate\x12\x1d\n" +
 	"\n" +
 	"total_wins\x18\x03 \x01(\x05R\ttotalWins\x12\x1f\n" +
 	"\vtotal_games\x18\x04 \x01(\x05R\n" +
-	"totalGames\"\xa0\x01\n" +
+	"totalGames\"\xb8\x01\n" +
 	"\x04User\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1d\n" +
 	"\n" +
@@ -1235,7 +2735,9 @@ const file_pkg_proto_snake_proto_rawDesc = "" +
 	"\n" +
 	"total_wins\x18\x04 \x01(\x05R\ttotalWins\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt\"\xc8\b\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x16\n" +
+	"\x06rating\x18\x06 \x01(\x05R\x06rating\"\xda\n" +
+	"\n" +
 	"\x11GameStateSnapshot\x12\"\n" +
 	"\x05snake\x18\x01 \x03(\v2\f.snake.PointR\x05snake\x12%\n" +
 	"\x05foods\x18\x02 \x03(\v2\x0f.snake.FoodInfoR\x05foods\x12\x14\n" +
@@ -1273,13 +2775,63 @@ const file_pkg_proto_snake_proto_rawDesc = "" +
 	"\x06p2Name\x18\x1d \x01(\tR\x06p2Name\x12!\n" +
 	"\x05props\x18\x1e \x03(\v2\v.snake.PropR\x05props\x121\n" +
 	"\tp1Effects\x18\x1f \x03(\v2\x13.snake.ActiveEffectR\tp1Effects\x121\n" +
-	"\tp2Effects\x18  \x03(\v2\x13.snake.ActiveEffectR\tp2Effects\"\x8a\x01\n" +
+	"\tp2Effects\x18  \x03(\v2\x13.snake.ActiveEffectR\tp2Effects\x12$\n" +
+	"\raiPersonality\x18! \x01(\tR\raiPersonality\x12-\n" +
+	"\topponents\x18\" \x03(\v2\x0f.snake.OpponentR\topponents\x12\x1c\n" +
+	"\tplacement\x18# \x01(\x05R\tplacement\x12$\n" +
+	"\radaptiveLevel\x18$ \x01(\x05R\radaptiveLevel\x12+\n" +
+	"\aaiDebug\x18% \x03(\v2\x11.snake.AIDecisionR\aaiDebug\x12\x1e\n" +
+	"\n" +
+	"spectators\x18& \x01(\x05R\n" +
+	"spectators\x12*\n" +
+	"\x06series\x18' \x01(\v2\x12.snake.SeriesScoreR\x06series\"\xab\x01\n" +
+	"\vSeriesScore\x12\x16\n" +
+	"\x06bestOf\x18\x01 \x01(\x05R\x06bestOf\x12\x12\n" +
+	"\x04game\x18\x02 \x01(\x05R\x04game\x12\x16\n" +
+	"\x06p1Wins\x18\x03 \x01(\x05R\x06p1Wins\x12\x16\n" +
+	"\x06p2Wins\x18\x04 \x01(\x05R\x06p2Wins\x12\x14\n" +
+	"\x05draws\x18\x05 \x01(\x05R\x05draws\x12\x12\n" +
+	"\x04over\x18\x06 \x01(\bR\x04over\x12\x16\n" +
+	"\x06winner\x18\a \x01(\tR\x06winner\"\x8a\x01\n" +
 	"\n" +
 	"GameConfig\x12\x14\n" +
 	"\x05width\x18\x01 \x01(\x05R\x05width\x12\x16\n" +
 	"\x06height\x18\x02 \x01(\x05R\x06height\x12\"\n" +
 	"\fgameDuration\x18\x03 \x01(\x05R\fgameDuration\x12*\n" +
-	"\x10fireballCooldown\x18\x04 \x01(\x05R\x10fireballCooldown\"\xe0\x02\n" +
+	"\x10fireballCooldown\x18\x04 \x01(\x05R\x10fireballCooldown\"\x96\x01\n" +
+	"\fRoomSettings\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\tR\x04size\x12\x1a\n" +
+	"\bduration\x18\x02 \x01(\x05R\bduration\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x14\n" +
+	"\x05props\x18\x04 \x01(\bR\x05props\x12\x14\n" +
+	"\x05seats\x18\x05 \x01(\x05R\x05seats\x12\x16\n" +
+	"\x06aiFill\x18\x06 \x01(\bR\x06aiFill\"J\n" +
+	"\n" +
+	"RoomMember\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05ready\x18\x02 \x01(\bR\x05ready\x12\x12\n" +
+	"\x04host\x18\x03 \x01(\bR\x04host\"\x97\x01\n" +
+	"\tRoomState\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12/\n" +
+	"\bsettings\x18\x02 \x01(\v2\x13.snake.RoomSettingsR\bsettings\x12+\n" +
+	"\amembers\x18\x03 \x03(\v2\x11.snake.RoomMemberR\amembers\x12\x18\n" +
+	"\aplaying\x18\x04 \x01(\bR\aplaying\"\x7f\n" +
+	"\vQueueStatus\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x05R\x04size\x12\x16\n" +
+	"\x06waited\x18\x02 \x01(\x05R\x06waited\x12\x1a\n" +
+	"\bestimate\x18\x03 \x01(\x05R\bestimate\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x05R\x06rating\x12\x10\n" +
+	"\x03gap\x18\x05 \x01(\x05R\x03gap\"\xaf\x01\n" +
+	"\tLiveMatch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aplayers\x18\x02 \x03(\tR\aplayers\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x12\n" +
+	"\x04room\x18\x04 \x01(\tR\x04room\x12\x14\n" +
+	"\x05seats\x18\x05 \x01(\x05R\x05seats\x12\x1e\n" +
+	"\n" +
+	"spectators\x18\x06 \x01(\x05R\n" +
+	"spectators\x12\x1a\n" +
+	"\btimeLeft\x18\a \x01(\x05R\btimeLeft\"\xfc\x04\n" +
 	"\rServerMessage\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12)\n" +
 	"\x06config\x18\x02 \x01(\v2\x11.snake.GameConfigR\x06config\x12.\n" +
@@ -1289,14 +2841,79 @@ const file_pkg_proto_snake_proto_rawDesc = "" +
 	"\x04user\x18\x06 \x01(\v2\v.snake.UserR\x04user\x12\x14\n" +
 	"\x05error\x18\a \x01(\tR\x05error\x12\x18\n" +
 	"\asuccess\x18\b \x01(\tR\asuccess\x12\"\n" +
-	"\fsessionCount\x18\t \x01(\x05R\fsessionCount\"\xa3\x01\n" +
+	"\fsessionCount\x18\t \x01(\x05R\fsessionCount\x12$\n" +
+	"\x04room\x18\n" +
+	" \x01(\v2\x10.snake.RoomStateR\x04room\x12*\n" +
+	"\amatches\x18\v \x03(\v2\x10.snake.LiveMatchR\amatches\x12,\n" +
+	"\bwatching\x18\f \x01(\v2\x10.snake.LiveMatchR\bwatching\x12\x1c\n" +
+	"\tqueueSize\x18\r \x01(\x05R\tqueueSize\x12(\n" +
+	"\x05queue\x18\x0e \x01(\v2\x12.snake.QueueStatusR\x05queue\x12(\n" +
+	"\arematch\x18\x0f \x01(\v2\x0e.snake.RematchR\arematch\x12(\n" +
+	"\asession\x18\x10 \x01(\v2\x0e.snake.SessionR\asession\"O\n" +
+	"\aSession\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x14\n" +
+	"\x05grace\x18\x02 \x01(\x05R\x05grace\x12\x18\n" +
+	"\aresumed\x18\x03 \x01(\bR\aresumed\"M\n" +
+	"\aRematch\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x16\n" +
+	"\x06bestOf\x18\x03 \x01(\x05R\x06bestOf\"\x88\x02\n" +
 	"\rClientMessage\x12\x16\n" +
 	"\x06action\x18\x01 \x01(\tR\x06action\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
 	"\busername\x18\x03 \x01(\tR\busername\x12\x1a\n" +
 	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x12\n" +
 	"\x04mode\x18\x05 \x01(\tR\x04mode\x12\x1a\n" +
-	"\bfeedback\x18\x06 \x01(\tR\bfeedbackB*Z(github.com/trytobebee/snake_go/pkg/protob\x06proto3"
+	"\bfeedback\x18\x06 \x01(\tR\bfeedback\x127\n" +
+	"\froomSettings\x18\a \x01(\v2\x13.snake.RoomSettingsR\froomSettings\x12\x12\n" +
+	"\x04code\x18\b \x01(\tR\x04code\x12\x16\n" +
+	"\x06target\x18\t \x01(\tR\x06target\"\x95\x02\n" +
+	"\tEnvConfig\x12\x14\n" +
+	"\x05width\x18\x01 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x05R\x06height\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x1a\n" +
+	"\bopponent\x18\x04 \x01(\tR\bopponent\x12\x1c\n" +
+	"\topponents\x18\x05 \x01(\x05R\topponents\x12\x14\n" +
+	"\x05speed\x18\x06 \x01(\tR\x05speed\x12\x1c\n" +
+	"\tautoReset\x18\a \x01(\bR\tautoReset\x12 \n" +
+	"\vobservation\x18\b \x01(\x05R\vobservation\x12\x16\n" +
+	"\x06reward\x18\t \x01(\tR\x06reward\x12\x1e\n" +
+	"\n" +
+	"curriculum\x18\n" +
+	" \x01(\tR\n" +
+	"curriculum\"S\n" +
+	"\tEnvAction\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\x05R\tdirection\x12\x14\n" +
+	"\x05boost\x18\x02 \x01(\bR\x05boost\x12\x12\n" +
+	"\x04fire\x18\x03 \x01(\bR\x04fire\"\xc5\x01\n" +
+	"\aEnvInfo\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x05R\x05score\x12$\n" +
+	"\ropponentScore\x18\x02 \x01(\x05R\ropponentScore\x12\x16\n" +
+	"\x06winner\x18\x03 \x01(\tR\x06winner\x12\x14\n" +
+	"\x05steps\x18\x04 \x01(\x05R\x05steps\x12\x14\n" +
+	"\x05ticks\x18\x05 \x01(\x05R\x05ticks\x12\x1a\n" +
+	"\bscenario\x18\x06 \x01(\tR\bscenario\x12\x1e\n" +
+	"\n" +
+	"difficulty\x18\a \x01(\x01R\n" +
+	"difficulty\"\x87\x01\n" +
+	"\aEnvStep\x12\x12\n" +
+	"\x04grid\x18\x01 \x03(\x02R\x04grid\x12\x18\n" +
+	"\ascalars\x18\x02 \x03(\x02R\ascalars\x12\x16\n" +
+	"\x06reward\x18\x03 \x01(\x01R\x06reward\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\bR\x04done\x12\"\n" +
+	"\x04info\x18\x05 \x01(\v2\x0e.snake.EnvInfoR\x04info\"\x8c\x01\n" +
+	"\n" +
+	"EnvRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05seeds\x18\x02 \x03(\x03R\x05seeds\x12(\n" +
+	"\x06config\x18\x03 \x01(\v2\x10.snake.EnvConfigR\x06config\x12*\n" +
+	"\aactions\x18\x04 \x03(\v2\x10.snake.EnvActionR\aactions\"\xa3\x01\n" +
+	"\vEnvResponse\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12$\n" +
+	"\x05steps\x18\x02 \x03(\v2\x0e.snake.EnvStepR\x05steps\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12 \n" +
+	"\vscalarNames\x18\x04 \x03(\tR\vscalarNames\x12\"\n" +
+	"\fchannelNames\x18\x05 \x03(\tR\fchannelNamesB*Z(github.com/trytobebee/snake_go/pkg/protob\x06proto3"
 
 var (
 	file_pkg_proto_snake_proto_rawDescOnce sync.Once
@@ -1310,7 +2927,7 @@ func file_pkg_proto_snake_proto_rawDescGZIP() []byte {
 	return file_pkg_proto_snake_proto_rawDescData
 }
 
-var file_pkg_proto_snake_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_pkg_proto_snake_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_pkg_proto_snake_proto_goTypes = []any{
 	(*Point)(nil),             // 0: snake.Point
 	(*FoodInfo)(nil),          // 1: snake.FoodInfo
@@ -1318,14 +2935,31 @@ var file_pkg_proto_snake_proto_goTypes = []any{
 	(*Fireball)(nil),          // 3: snake.Fireball
 	(*ScoreEvent)(nil),        // 4: snake.ScoreEvent
 	(*Prop)(nil),              // 5: snake.Prop
-	(*ActiveEffect)(nil),      // 6: snake.ActiveEffect
-	(*LeaderboardEntry)(nil),  // 7: snake.LeaderboardEntry
-	(*WinRateEntry)(nil),      // 8: snake.WinRateEntry
-	(*User)(nil),              // 9: snake.User
-	(*GameStateSnapshot)(nil), // 10: snake.GameStateSnapshot
-	(*GameConfig)(nil),        // 11: snake.GameConfig
-	(*ServerMessage)(nil),     // 12: snake.ServerMessage
-	(*ClientMessage)(nil),     // 13: snake.ClientMessage
+	(*Opponent)(nil),          // 6: snake.Opponent
+	(*MoveCandidate)(nil),     // 7: snake.MoveCandidate
+	(*AIDecision)(nil),        // 8: snake.AIDecision
+	(*ActiveEffect)(nil),      // 9: snake.ActiveEffect
+	(*LeaderboardEntry)(nil),  // 10: snake.LeaderboardEntry
+	(*WinRateEntry)(nil),      // 11: snake.WinRateEntry
+	(*User)(nil),              // 12: snake.User
+	(*GameStateSnapshot)(nil), // 13: snake.GameStateSnapshot
+	(*SeriesScore)(nil),       // 14: snake.SeriesScore
+	(*GameConfig)(nil),        // 15: snake.GameConfig
+	(*RoomSettings)(nil),      // 16: snake.RoomSettings
+	(*RoomMember)(nil),        // 17: snake.RoomMember
+	(*RoomState)(nil),         // 18: snake.RoomState
+	(*QueueStatus)(nil),       // 19: snake.QueueStatus
+	(*LiveMatch)(nil),         // 20: snake.LiveMatch
+	(*ServerMessage)(nil),     // 21: snake.ServerMessage
+	(*Session)(nil),           // 22: snake.Session
+	(*Rematch)(nil),           // 23: snake.Rematch
+	(*ClientMessage)(nil),     // 24: snake.ClientMessage
+	(*EnvConfig)(nil),         // 25: snake.EnvConfig
+	(*EnvAction)(nil),         // 26: snake.EnvAction
+	(*EnvInfo)(nil),           // 27: snake.EnvInfo
+	(*EnvStep)(nil),           // 28: snake.EnvStep
+	(*EnvRequest)(nil),        // 29: snake.EnvRequest
+	(*EnvResponse)(nil),       // 30: snake.EnvResponse
 }
 var file_pkg_proto_snake_proto_depIdxs = []int32{
 	0,  // 0: snake.FoodInfo.pos:type_name -> snake.Point
@@ -1334,27 +2968,47 @@ var file_pkg_proto_snake_proto_depIdxs = []int32{
 	0,  // 3: snake.Fireball.dir:type_name -> snake.Point
 	0,  // 4: snake.ScoreEvent.pos:type_name -> snake.Point
 	0,  // 5: snake.Prop.pos:type_name -> snake.Point
-	0,  // 6: snake.GameStateSnapshot.snake:type_name -> snake.Point
-	1,  // 7: snake.GameStateSnapshot.foods:type_name -> snake.FoodInfo
-	0,  // 8: snake.GameStateSnapshot.crashPoint:type_name -> snake.Point
-	2,  // 9: snake.GameStateSnapshot.obstacles:type_name -> snake.Obstacle
-	3,  // 10: snake.GameStateSnapshot.fireballs:type_name -> snake.Fireball
-	0,  // 11: snake.GameStateSnapshot.hitPoints:type_name -> snake.Point
-	0,  // 12: snake.GameStateSnapshot.aiSnake:type_name -> snake.Point
-	4,  // 13: snake.GameStateSnapshot.scoreEvents:type_name -> snake.ScoreEvent
-	5,  // 14: snake.GameStateSnapshot.props:type_name -> snake.Prop
-	6,  // 15: snake.GameStateSnapshot.p1Effects:type_name -> snake.ActiveEffect
-	6,  // 16: snake.GameStateSnapshot.p2Effects:type_name -> snake.ActiveEffect
-	11, // 17: snake.ServerMessage.config:type_name -> snake.GameConfig
-	10, // 18: snake.ServerMessage.state:type_name -> snake.GameStateSnapshot
-	7,  // 19: snake.ServerMessage.leaderboard:type_name -> snake.LeaderboardEntry
-	8,  // 20: snake.ServerMessage.win_rates:type_name -> snake.WinRateEntry
-	9,  // 21: snake.ServerMessage.user:type_name -> snake.User
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	0,  // 6: snake.Opponent.snake:type_name -> snake.Point
+	0,  // 7: snake.MoveCandidate.dir:type_name -> snake.Point
+	0,  // 8: snake.AIDecision.target:type_name -> snake.Point
+	7,  // 9: snake.AIDecision.candidates:type_name -> snake.MoveCandidate
+	0,  // 10: snake.GameStateSnapshot.snake:type_name -> snake.Point
+	1,  // 11: snake.GameStateSnapshot.foods:type_name -> snake.FoodInfo
+	0,  // 12: snake.GameStateSnapshot.crashPoint:type_name -> snake.Point
+	2,  // 13: snake.GameStateSnapshot.obstacles:type_name -> snake.Obstacle
+	3,  // 14: snake.GameStateSnapshot.fireballs:type_name -> snake.Fireball
+	0,  // 15: snake.GameStateSnapshot.hitPoints:type_name -> snake.Point
+	0,  // 16: snake.GameStateSnapshot.aiSnake:type_name -> snake.Point
+	4,  // 17: snake.GameStateSnapshot.scoreEvents:type_name -> snake.ScoreEvent
+	5,  // 18: snake.GameStateSnapshot.props:type_name -> snake.Prop
+	9,  // 19: snake.GameStateSnapshot.p1Effects:type_name -> snake.ActiveEffect
+	9,  // 20: snake.GameStateSnapshot.p2Effects:type_name -> snake.ActiveEffect
+	6,  // 21: snake.GameStateSnapshot.opponents:type_name -> snake.Opponent
+	8,  // 22: snake.GameStateSnapshot.aiDebug:type_name -> snake.AIDecision
+	14, // 23: snake.GameStateSnapshot.series:type_name -> snake.SeriesScore
+	16, // 24: snake.RoomState.settings:type_name -> snake.RoomSettings
+	17, // 25: snake.RoomState.members:type_name -> snake.RoomMember
+	15, // 26: snake.ServerMessage.config:type_name -> snake.GameConfig
+	13, // 27: snake.ServerMessage.state:type_name -> snake.GameStateSnapshot
+	10, // 28: snake.ServerMessage.leaderboard:type_name -> snake.LeaderboardEntry
+	11, // 29: snake.ServerMessage.win_rates:type_name -> snake.WinRateEntry
+	12, // 30: snake.ServerMessage.user:type_name -> snake.User
+	18, // 31: snake.ServerMessage.room:type_name -> snake.RoomState
+	20, // 32: snake.ServerMessage.matches:type_name -> snake.LiveMatch
+	20, // 33: snake.ServerMessage.watching:type_name -> snake.LiveMatch
+	19, // 34: snake.ServerMessage.queue:type_name -> snake.QueueStatus
+	23, // 35: snake.ServerMessage.rematch:type_name -> snake.Rematch
+	22, // 36: snake.ServerMessage.session:type_name -> snake.Session
+	16, // 37: snake.ClientMessage.roomSettings:type_name -> snake.RoomSettings
+	27, // 38: snake.EnvStep.info:type_name -> snake.EnvInfo
+	25, // 39: snake.EnvRequest.config:type_name -> snake.EnvConfig
+	26, // 40: snake.EnvRequest.actions:type_name -> snake.EnvAction
+	28, // 41: snake.EnvResponse.steps:type_name -> snake.EnvStep
+	42, // [42:42] is the sub-list for method output_type
+	42, // [42:42] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_snake_proto_init() }
@@ -1368,7 +3022,7 @@ func file_pkg_proto_snake_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_snake_proto_rawDesc), len(file_pkg_proto_snake_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
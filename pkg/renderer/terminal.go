@@ -23,6 +23,7 @@ const (
 	cellCrash
 	cellAIHead
 	cellAIBody
+	cellTarget
 )
 
 // NewTerminalRenderer creates a new terminal renderer
@@ -101,9 +102,9 @@ func (r *TerminalRenderer) Render(g *game.Game, boosting bool) {
 		}
 	}
 
-	// Draw AI/P2 snake
-	if len(g.Players) > 1 {
-		for i, p := range g.Players[1].Snake {
+	// Draw AI/P2 snakes (several in free-for-all)
+	for _, ai := range g.Players[min(1, len(g.Players)):] {
+		for i, p := range ai.Snake {
 			if i == 0 {
 				r.board[p.Y][p.X] = cellAIHead
 			} else {
@@ -112,6 +113,17 @@ func (r *TerminalRenderer) Render(g *game.Game, boosting bool) {
 		}
 	}
 
+	// AI targets for the debug overlay
+	var decisions []game.AIDecision
+	if g.ShowAIDebug {
+		decisions = g.AIDecisions()
+		for _, d := range decisions {
+			if t := d.TargetPos; t != nil && t.Y >= 0 && t.Y < g.Height && t.X >= 0 && t.X < g.Width && r.board[t.Y][t.X] == cellEmpty {
+				r.board[t.Y][t.X] = cellTarget
+			}
+		}
+	}
+
 	// Draw crash point if game over
 	if g.GameOver {
 		if g.CrashPoint.X >= 0 && g.CrashPoint.X < g.Width &&
@@ -154,7 +166,9 @@ func (r *TerminalRenderer) Render(g *game.Game, boosting bool) {
 		r.buffer.WriteString("  ")
 		for x, cell := range row {
 			pos := game.Point{X: x, Y: y}
-			if timer, hasTimer := timerEmojis[pos]; hasTimer && cell == cellEmpty {
+			if cell == cellTarget {
+				r.buffer.WriteString("🎯")
+			} else if timer, hasTimer := timerEmojis[pos]; hasTimer && cell == cellEmpty {
 				r.buffer.WriteString(timer)
 				r.buffer.WriteString(" ")
 			} else if emoji, hasFood := foodEmojis[pos]; hasFood && cell == cellEmpty {
@@ -194,8 +208,12 @@ func (r *TerminalRenderer) Render(g *game.Game, boosting bool) {
 		r.buffer.WriteString("\n")
 	}
 
+	for _, d := range decisions {
+		r.writeAIDecision(g, d)
+	}
+
 	r.buffer.WriteString("\n  Use WASD or Arrow keys to move, hold direction key to boost 🚀\n")
-	r.buffer.WriteString("  P to pause, Q to quit\n")
+	r.buffer.WriteString("  P to pause, I for the AI overlay, Q to quit\n")
 
 	if g.Paused {
 		r.buffer.WriteString("\n  ⏸️  PAUSED - Press P to continue\n")
@@ -207,3 +225,25 @@ func (r *TerminalRenderer) Render(g *game.Game, boosting bool) {
 
 	fmt.Print(r.buffer.String())
 }
+
+var directionArrows = map[game.Point]string{{X: 0, Y: -1}: "↑", {X: 0, Y: 1}: "↓", {X: -1, Y: 0}: "←", {X: 1, Y: 0}: "→"}
+
+// writeAIDecision prints one line of the AI overlay: intent, target and the
+// flood-fill space and score of every direction weighed
+func (r *TerminalRenderer) writeAIDecision(g *game.Game, d game.AIDecision) {
+	fmt.Fprintf(&r.buffer, "  🔍 %s [%s] %s", g.Players[d.Player].Name, d.Controller, d.Intent)
+	if d.TargetPos != nil {
+		fmt.Fprintf(&r.buffer, " 🎯(%d,%d)", d.TargetPos.X, d.TargetPos.Y)
+	}
+	if d.Urgency > 0 {
+		fmt.Fprintf(&r.buffer, " urgency %.0f%%", d.Urgency*100)
+	}
+	for _, c := range d.Candidates {
+		if c.Safe {
+			fmt.Fprintf(&r.buffer, "  %s %d/%.0f", directionArrows[c.Dir], c.Space, c.Score)
+		} else {
+			fmt.Fprintf(&r.buffer, "  %s ✕", directionArrows[c.Dir])
+		}
+	}
+	r.buffer.WriteString("\n")
+}
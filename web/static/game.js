@@ -1,5 +1,5 @@
 import { SoundManager } from './modules/audio.js';
-import { GameRenderer } from './modules/renderer.js?v=2.4';
+import { GameRenderer } from './modules/renderer.js?v=2.5';
 
 export class SnakeGameClient {
     constructor() {
@@ -60,8 +60,14 @@ export class SnakeGameClient {
 
         // Matchmaking State
         this.isMatching = false;
+        this.queueStatus = null; // Latest QueueStatus while searching
+        this.room = null; // RoomState of the room we are in, if any
+        this.watching = null; // LiveMatch we spectate, if any (ended: true once it is over)
+        this.rematch = null; // Latest Rematch status since our last matchmaking game ended
         this.shouldReconnect = true;
         this.kickReason = null;
+        this.resumeGrace = 0; // Seconds the server keeps our session after the connection dropped
+        this.resumeUntil = 0; // Time (ms) until which we try to get a dropped session back
 
         // Start systems
         this.protoRoot = null;
@@ -76,6 +82,9 @@ export class SnakeGameClient {
             this.setupDifficulty();
             this.setupAutoPlay();
             this.setupMode();
+            this.setupRoom();
+            this.setupSpectate();
+            this.setupRematch();
         }).catch(err => {
             console.error("❌ Failed to load Protobuf:", err);
             this.updateConnectionStatus('error');
@@ -242,6 +251,7 @@ export class SnakeGameClient {
         if (!confirm("Are you sure you want to logout?")) return;
 
         // Notify server before cleaning up local state
+        sessionStorage.removeItem('snake_resume'); // Nothing to get back after leaving
         this.sendMessage('logout');
 
         localStorage.removeItem('snake_auth');
@@ -352,30 +362,17 @@ export class SnakeGameClient {
     setupWebSocket() {
         this.updateConnectionStatus('connecting');
         const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-        this.ws = new WebSocket(`${protocol}//${window.location.host}/ws`);
+        // With a resume token the server hands us our session back, game and all
+        const token = sessionStorage.getItem('snake_resume');
+        const query = token ? `?resume=${encodeURIComponent(token)}` : '';
+        this.ws = new WebSocket(`${protocol}//${window.location.host}/ws${query}`);
         this.ws.binaryType = 'arraybuffer';
 
         this.ws.onopen = () => {
             this.updateConnectionStatus('connected');
             this.updateOverlay();
 
-            // Attempt auto-login if credentials exist
-            const saved = localStorage.getItem('snake_auth');
-            if (saved) {
-                try {
-                    const creds = JSON.parse(atob(saved));
-                    this.authMessage.textContent = "Auto-logging in...";
-                    this.authMessage.className = "success";
-                    this.sendMessage('login', {
-                        username: creds.username,
-                        password: creds.password
-                    });
-
-
-                } catch (e) {
-                    localStorage.removeItem('snake_auth');
-                }
-            }
+            // Auto-login waits for the session message: a resumed session is logged in already
             // Start pinging to measure latency
             this.sendPing();
             this.pingInterval = setInterval(() => this.sendPing(), 5000);
@@ -388,6 +385,7 @@ export class SnakeGameClient {
             if (msg.type === 'config') {
                 this.handleConfig(msg.config);
             } else if (msg.type === 'state') {
+                if (!msg.state.gameOver) this.rematch = null; // A new game: old offers are gone
                 this.gameState = msg.state;
                 if (msg.user) {
                     this.currentUser = msg.user;
@@ -409,11 +407,26 @@ export class SnakeGameClient {
             } else if (msg.type === 'auth_error') {
                 this.onAuthError(msg.error);
             } else if (msg.type === 'update_counts') {
-                this.updatePlayerCount(msg.sessionCount);
+                this.updatePlayerCount(msg.sessionCount, msg.queueSize || 0);
+            } else if (msg.type === 'queue') {
+                this.queueStatus = msg.queue;
+                if (this.isMatching) this.updateOverlay();
             } else if (msg.type === 'pong') {
                 this.handlePong();
+            } else if (msg.type === 'room') {
+                this.handleRoom(msg.room, msg.error);
+            } else if (msg.type === 'matches') {
+                this.renderLiveMatches(msg.matches || []);
+            } else if (msg.type === 'spectate') {
+                this.handleSpectate(msg);
+            } else if (msg.type === 'session') {
+                this.handleSession(msg);
+            } else if (msg.type === 'rematch') {
+                this.rematch = msg.rematch;
+                this.updateOverlay();
             } else if (msg.type === 'error') {
                 if (msg.error === 'Logged in from another location.') {
+                    sessionStorage.removeItem('snake_resume');
                     this.shouldReconnect = false;
                     this.kickReason = msg.error;
                     this.updateOverlay();
@@ -435,10 +448,61 @@ export class SnakeGameClient {
                 clearInterval(this.pingInterval);
                 this.pingInterval = null;
             }
-            // Automatic reconnection removed as per user request
+            // No automatic reconnection, except to get back a session the server still keeps
+            if (this.shouldReconnect && !this.kickReason && this.resumeGrace > 0 && sessionStorage.getItem('snake_resume')) {
+                if (!this.resumeUntil) this.resumeUntil = Date.now() + this.resumeGrace * 1000;
+                if (Date.now() < this.resumeUntil) {
+                    setTimeout(() => this.setupWebSocket(), 2000);
+                } else {
+                    this.resumeUntil = 0;
+                }
+                this.updateOverlay();
+            }
         };
     }
 
+    handleSession(msg) {
+        const session = msg.session || {};
+        if (session.token) {
+            sessionStorage.setItem('snake_resume', session.token);
+        } else {
+            sessionStorage.removeItem('snake_resume');
+        }
+        this.resumeGrace = session.grace || 0;
+        this.resumeUntil = 0;
+
+        if (!session.resumed) {
+            this.autoLogin();
+            return;
+        }
+        // Still logged in: keep the saved credentials as they are
+        if (msg.user) {
+            this.currentUser = msg.user;
+            this.authOverlay.classList.add('hidden');
+            this.updateUserStatsUI();
+        }
+        this.showTempMessage('Reconnected');
+        this.updateOverlay();
+    }
+
+    autoLogin() {
+        // Attempt auto-login if credentials exist
+        const saved = localStorage.getItem('snake_auth');
+        if (saved) {
+            try {
+                const creds = JSON.parse(atob(saved));
+                this.authMessage.textContent = "Auto-logging in...";
+                this.authMessage.className = "success";
+                this.sendMessage('login', {
+                    username: creds.username,
+                    password: creds.password
+                });
+            } catch (e) {
+                localStorage.removeItem('snake_auth');
+            }
+        }
+    }
+
     handleConfig(config) {
         if (!config) return;
         this.boardWidth = config.width;
@@ -497,9 +561,37 @@ export class SnakeGameClient {
         this.timeLeftEl.textContent = timeRemaining;
         this.timeLeftEl.parentElement.classList.toggle('low-time', timeRemaining <= 10);
 
+        // Spectators of the match, for players and spectators alike
+        const spectators = this.gameState.isPVP ? (this.gameState.spectators || 0) : 0;
+        document.getElementById('spectatorStat')?.classList.toggle('hidden', spectators === 0);
+        const spectatorCount = document.getElementById('spectatorCount');
+        if (spectatorCount) spectatorCount.textContent = spectators;
+
+        // Best-of-N series score, P1 first
+        const series = this.gameState.series;
+        document.getElementById('seriesStat')?.classList.toggle('hidden', !series);
+        if (series) {
+            document.getElementById('seriesLabel').textContent = `Bo${series.bestOf} · G${series.game}`;
+            document.getElementById('seriesScore').textContent = `${series.p1Wins || 0}-${series.p2Wins || 0}`;
+        }
+
         if (this.gameState.mode === 'zen') {
             this.aiStatEl.style.display = 'none';
             this.timerEl.style.display = 'none';
+        } else if (this.gameState.isPVP && this.gameState.mode === 'battle') {
+            // Room battle: my score against the best rival
+            this.aiStatEl.style.display = 'flex';
+            this.timerEl.style.display = 'flex';
+            const me = this.currentUser ? this.currentUser.username : null;
+            const standings = this.matchStandings();
+            const mine = standings.find(p => p.name === me);
+            const rivals = standings.filter(p => p !== mine);
+            this.scoreEl.previousElementSibling.textContent = mine ? mine.name : 'Score';
+            this.scoreEl.textContent = mine ? mine.score : currentScore;
+            this.aiStatEl.querySelector('.stat-label').textContent = rivals.length > 0 ? `Best: ${rivals[0].name}` : 'Best Rival';
+            this.aiScoreEl.textContent = rivals.length > 0 ? rivals[0].score : 0;
+            this.scoreEl.parentElement.classList.add('current-player');
+            this.aiScoreEl.parentElement.classList.remove('current-player');
         } else if (this.gameState.mode === 'pvp') {
             this.aiStatEl.style.display = 'flex';
             this.timerEl.style.display = 'flex';
@@ -520,9 +612,10 @@ export class SnakeGameClient {
         } else {
             this.aiStatEl.style.display = 'flex';
             this.timerEl.style.display = 'flex';
-            this.aiStatEl.querySelector('.stat-label').textContent = 'AI Score';
+            const extra = this.gameState.opponents || [];
+            this.aiStatEl.querySelector('.stat-label').textContent = extra.length > 0 ? 'Best AI' : 'AI Score';
             this.scoreEl.previousElementSibling.textContent = 'Score';
-            this.aiScoreEl.textContent = this.gameState.aiScore || 0;
+            this.aiScoreEl.textContent = Math.max(this.gameState.aiScore || 0, ...extra.map(o => o.score || 0));
 
             // In non-PVP, Player 1 is always the human
             this.scoreEl.parentElement.classList.add('current-player');
@@ -535,10 +628,15 @@ export class SnakeGameClient {
 
         // Update Difficulty buttons state
         const currentDiff = this.gameState.difficulty || 'mid';
-        ['low', 'mid', 'high'].forEach(d => {
+        ['low', 'mid', 'high', 'expert', 'adaptive'].forEach(d => {
             const btn = document.getElementById(`diff-${d}`);
             if (btn) btn.classList.toggle('active', currentDiff === d);
         });
+        const adaptiveBtn = document.getElementById('diff-adaptive');
+        if (adaptiveBtn) {
+            const level = this.gameState.adaptiveLevel || 0;
+            adaptiveBtn.textContent = level > 0 ? `Adaptive Lv${level}` : 'Adaptive';
+        }
 
         // Update Mode buttons state
         const currentMode = this.gameState.mode || 'battle';
@@ -558,6 +656,18 @@ export class SnakeGameClient {
             berserkerToggle.classList.toggle('active', !!this.gameState.berserker);
         }
 
+        // Update opponent count picker
+        const opponentsSelect = document.getElementById('ai-opponents');
+        if (opponentsSelect && this.gameState.mode === 'battle' && document.activeElement !== opponentsSelect) {
+            opponentsSelect.value = String(this.getOpponentCount());
+        }
+
+        // Update opponent personality picker
+        const personalitySelect = document.getElementById('ai-personality');
+        if (personalitySelect && this.gameState.aiPersonality && document.activeElement !== personalitySelect) {
+            personalitySelect.value = this.gameState.aiPersonality;
+        }
+
         if (autoBtn) {
             autoBtn.classList.toggle('active', !!this.gameState.autoPlay);
         }
@@ -568,9 +678,9 @@ export class SnakeGameClient {
     updateEffectsUI() {
         if (!this.effectsBar) return;
 
-        // Use P1 effects in solo, or my effects in PVP
-        const isP2 = this.currentUser && this.gameState.p2Name === this.currentUser.username;
-        const myEffects = isP2 ? (this.gameState.p2Effects || []) : (this.gameState.p1Effects || []);
+        // Use P1 effects in solo, or my effects in PVP (only the first two seats carry them)
+        const seat = this.gameState.isPVP ? this.mySeat() : 0;
+        const myEffects = seat === 1 ? (this.gameState.p2Effects || []) : (seat === 0 ? (this.gameState.p1Effects || []) : []);
 
         if (myEffects.length === 0) {
             this.effectsBar.innerHTML = '';
@@ -654,6 +764,8 @@ export class SnakeGameClient {
 
         if (this.winRateEl) this.winRateEl.textContent = `${rate}%`;
         if (this.gamesWonEl) this.gamesWonEl.textContent = `${wins}/${total}`;
+        const ratingEl = document.getElementById('userRating');
+        if (ratingEl) ratingEl.textContent = `⭐ ${this.currentUser.rating || 0}`;
 
         // Update User Info Bar
         if (this.userInfoBar && this.displayUsername && this.currentUser) {
@@ -673,6 +785,7 @@ export class SnakeGameClient {
         // 1. Reset special elements
         this.cancelMatchBtn.classList.add('hidden');
         this.reconnectBtn.classList.add('hidden');
+        this.renderRematch(false);
         this.overlayTitle.classList.remove('searching-pulse', 'important-message');
 
         const isDisconnected = !this.ws || this.ws.readyState === WebSocket.CLOSED || this.ws.readyState === WebSocket.CLOSING;
@@ -684,6 +797,13 @@ export class SnakeGameClient {
             this.overlayTitle.style.color = '#f6e05e';
             this.overlayMessage.textContent = this.kickReason;
             this.reconnectBtn.classList.remove('hidden');
+        } else if ((isDisconnected || isConnecting) && this.resumeUntil) {
+            this.gameOverlay.style.display = 'flex';
+            this.overlayTitle.textContent = 'RECONNECTING...';
+            this.overlayTitle.style.color = '#fbbf24';
+            const left = Math.max(0, Math.ceil((this.resumeUntil - Date.now()) / 1000));
+            this.overlayMessage.textContent = `Your game is kept for another ${left}s`;
+            this.reconnectBtn.classList.remove('hidden');
         } else if (isDisconnected) {
             this.gameOverlay.style.display = 'flex';
             this.overlayTitle.textContent = 'CONNECTION LOST';
@@ -700,7 +820,10 @@ export class SnakeGameClient {
             this.overlayTitle.textContent = 'SEARCHING...';
             this.overlayTitle.classList.add('searching-pulse');
             this.overlayTitle.style.color = '#f6e05e';
-            this.overlayMessage.textContent = 'Waiting for an opponent to join';
+            const q = this.queueStatus;
+            this.overlayMessage.textContent = q
+                ? `⏱️ ${q.waited || 0}s · ~${q.estimate || 0}s to go · 👥 ${q.size || 0} searching · ⭐ ${q.rating || 0} ±${q.gap || 0}`
+                : 'Waiting for an opponent to join';
             this.cancelMatchBtn.classList.remove('hidden');
         } else if (isImportant && this.currentMessage) {
             // Synchronized Countdown / Match Found state
@@ -711,14 +834,24 @@ export class SnakeGameClient {
                 this.overlayTitle.textContent = this.currentMessage;
                 this.overlayTitle.classList.add('important-message');
                 this.overlayTitle.style.color = '#fff';
-                this.overlayMessage.textContent = this.gameState.mode === 'pvp' ? 'GET READY!' : '';
+                this.overlayMessage.textContent = this.gameState.isPVP ? 'GET READY!' : '';
             } else {
                 this.gameOverlay.style.display = 'none';
             }
         } else if (isGameOver) {
             this.gameOverlay.style.display = 'flex';
             if (this.gameState.winner) {
-                if (this.gameState.mode === 'pvp') {
+                if (this.gameState.isPVP && this.gameState.mode === 'battle') {
+                    // Room battle: my placement among every snake
+                    const me = this.currentUser ? this.currentUser.username : null;
+                    const standings = this.matchStandings();
+                    const mine = standings.find(p => p.name === me);
+                    const place = mine ? 1 + standings.filter(p => p.score > mine.score).length : 0;
+                    const medals = ['🥇', '🥈', '🥉'];
+                    const suffix = ['st', 'nd', 'rd'][place - 1] || 'th';
+                    this.overlayTitle.textContent = place ? `${medals[place - 1] || '🏁'} ${place}${suffix} OF ${standings.length}` : `🏆 ${standings[0].name.toUpperCase()} WINS!`;
+                    this.overlayTitle.style.color = place === 1 ? '#f6e05e' : '#9f7aea';
+                } else if (this.gameState.mode === 'pvp') {
                     // PVP specific winner display
                     if (this.gameState.winner === 'player') {
                         const winnerName = this.gameState.p1Name || 'PLAYER 1';
@@ -732,6 +865,13 @@ export class SnakeGameClient {
                         this.overlayTitle.textContent = '🤝 DRAW!';
                         this.overlayTitle.style.color = '#4299e1';
                     }
+                } else if (this.getOpponentCount() > 1 && this.gameState.placement) {
+                    // Free-for-all: show the final placement
+                    const place = this.gameState.placement;
+                    const medals = ['🥇', '🥈', '🥉'];
+                    const suffix = ['st', 'nd', 'rd'][place - 1] || 'th';
+                    this.overlayTitle.textContent = `${medals[place - 1] || '🏁'} ${place}${suffix} OF ${this.getOpponentCount() + 1}`;
+                    this.overlayTitle.style.color = place === 1 ? '#f6e05e' : '#9f7aea';
                 } else {
                     // Standard vs AI display
                     if (this.gameState.winner === 'player') {
@@ -746,6 +886,10 @@ export class SnakeGameClient {
                     }
                 }
                 this.overlayMessage.innerHTML = `<span class="tap-hint">Tap or Press 'R' to Restart</span>`;
+                if (this.gameState.series) {
+                    this.overlayMessage.innerHTML = this.seriesSummary() + (this.seriesRunning() ? '' : `<br>${this.overlayMessage.innerHTML}`);
+                }
+                this.renderRematch(this.canRematch());
             } else {
                 this.overlayTitle.textContent = 'GAME OVER';
                 this.overlayTitle.style.color = '#f56565';
@@ -767,6 +911,31 @@ export class SnakeGameClient {
         this.gameOverlay.style.flexDirection = 'column';
     }
 
+    // Every snake in the game with its score, best first
+    matchStandings() {
+        const st = this.gameState;
+        const all = [{ name: st.p1Name || 'Player 1', score: st.score || 0 }];
+        if (st.aiSnake?.length || st.p2Name) all.push({ name: st.p2Name || 'Player 2', score: st.aiScore || 0 });
+        (st.opponents || []).forEach(o => all.push({ name: o.name, score: o.score || 0 }));
+        return all.sort((a, b) => b.score - a.score);
+    }
+
+    // Seat of the logged-in player in the current match, -1 when watching
+    mySeat() {
+        const me = this.currentUser ? this.currentUser.username : null;
+        if (!me) return -1;
+        if (this.gameState.p1Name === me) return 0;
+        if (this.gameState.p2Name === me) return 1;
+        const idx = (this.gameState.opponents || []).findIndex(o => o.name === me);
+        return idx >= 0 ? idx + 2 : -1;
+    }
+
+    // Number of AI snakes in the current solo game (free-for-all when above 1)
+    getOpponentCount() {
+        if (!this.gameState?.aiSnake?.length) return 1;
+        return 1 + (this.gameState.opponents || []).length;
+    }
+
     renderLeaderboard() {
         if (!this.leaderboardList) return;
         const type = this.currentLeaderboardTab;
@@ -783,7 +952,7 @@ export class SnakeGameClient {
                         <span class="rank">#${index + 1}</span>
                         <span class="player-name">${this.escapeHTML(entry.name)}</span>
                         <span class="player-score">${entry.score}</span>
-                        <span class="player-meta">${entry.mode}<br>${dateStr} ${timeStr}</span>
+                        <span class="player-meta">${entry.opponents > 1 ? `FFA ×${entry.opponents}` : entry.mode}<br>${dateStr} ${timeStr}</span>
                     </div>
                 `;
             }).join('') || '<p class="loading-text">No scores yet. Be the first!</p>';
@@ -931,10 +1100,16 @@ export class SnakeGameClient {
             const actionMap = {
                 'arrowup': 'up', 'w': 'up', 'arrowdown': 'down', 's': 'down',
                 'arrowleft': 'left', 'arrowright': 'right', 'd': 'right',
-                ' ': 'pause', 'r': 'restart', 'q': 'quit', 'p': 'auto'
+                ' ': 'pause', 'r': 'restart', 'q': 'quit', 'p': 'auto', 'i': 'ai_debug'
             };
             const action = actionMap[key];
+            if (this.watching) return; // Spectators only watch
             if (key === 'f' || key === 'enter') { this.fire(); return; }
+            if (action === 'restart') {
+                e.preventDefault();
+                this.restart();
+                return;
+            }
             if (action) {
                 e.preventDefault();
                 let extra = {};
@@ -978,8 +1153,8 @@ export class SnakeGameClient {
 
         const handleStartRestart = () => {
             if (!this.ws || this.ws.readyState !== WebSocket.OPEN) return;
-            if (this.isMatching) return; // Prevent clicking while matching
-            if (this.gameState?.gameOver) this.sendMessage('restart');
+            if (this.isMatching || this.watching) return; // Prevent clicking while matching or watching
+            if (this.gameState?.gameOver) this.restart();
             else if (this.gameState && !this.gameState.started) this.sendMessage('start');
         };
 
@@ -988,7 +1163,7 @@ export class SnakeGameClient {
     }
 
     setupDifficulty() {
-        ['low', 'mid', 'high'].forEach(d => {
+        ['low', 'mid', 'high', 'expert', 'adaptive'].forEach(d => {
             document.getElementById(`diff-${d}`)?.addEventListener('click', () => {
                 if (!this.ws || this.ws.readyState !== WebSocket.OPEN) return;
                 if (!this.gameState?.started || this.gameState?.gameOver) {
@@ -1006,6 +1181,52 @@ export class SnakeGameClient {
             this.sendMessage('toggleBerserker');
         });
 
+        const opponentsSelect = document.getElementById('ai-opponents');
+        opponentsSelect?.addEventListener('change', () => {
+            if (!this.gameState?.started || this.gameState?.gameOver) {
+                this.sendMessage('opponents', { mode: opponentsSelect.value });
+            } else {
+                opponentsSelect.value = String(this.getOpponentCount());
+                this.showTempMessage("Can't change opponents during game!");
+            }
+        });
+
+        // WASM bots registered by an admin can play the AI snakes
+        const brainSelect = document.getElementById('ai-brain');
+        if (brainSelect) {
+            let brain = '';
+            fetch('/api/wasm-bots')
+                .then(res => res.json())
+                .then(bots => {
+                    for (const bot of bots || []) {
+                        const option = document.createElement('option');
+                        option.value = bot;
+                        option.textContent = '🧩 ' + bot.slice('wasm:'.length);
+                        brainSelect.appendChild(option);
+                    }
+                })
+                .catch(() => {});
+            brainSelect.addEventListener('change', () => {
+                if (!this.gameState?.started || this.gameState?.gameOver) {
+                    brain = brainSelect.value;
+                    this.sendMessage('opponent_brain', { mode: brain });
+                } else {
+                    brainSelect.value = brain;
+                    this.showTempMessage("Can't change opponent during game!");
+                }
+            });
+        }
+
+        const personalitySelect = document.getElementById('ai-personality');
+        personalitySelect?.addEventListener('change', () => {
+            if (!this.gameState?.started || this.gameState?.gameOver) {
+                this.sendMessage('personality', { mode: personalitySelect.value });
+            } else {
+                personalitySelect.value = this.gameState.aiPersonality || 'balanced';
+                this.showTempMessage("Can't change opponent during game!");
+            }
+        });
+
     }
 
     setupAutoPlay() {
@@ -1036,9 +1257,14 @@ export class SnakeGameClient {
                 return;
             }
             if (this.isMatching) return;
+            if (this.room?.code) {
+                this.showTempMessage("Leave your room to search for a match");
+                return;
+            }
 
             if (this.ws && this.ws.readyState === WebSocket.OPEN) {
                 this.isMatching = true;
+                this.queueStatus = null;
                 this.sendMessage('find_match');
                 this.showTempMessage("Searching for opponent...");
                 this.updateOverlay(); // Update UI immediately
@@ -1046,6 +1272,197 @@ export class SnakeGameClient {
         };
     }
 
+    setupRoom() {
+        const byId = (id) => document.getElementById(id);
+        const requireLogin = () => {
+            if (this.currentUser) return true;
+            this.showTempMessage("Please login to play in rooms!");
+            this.authOverlay.classList.remove('hidden');
+            return false;
+        };
+        const settings = () => ({
+            size: byId('room-size').value,
+            mode: byId('room-mode').value,
+            seats: byId('room-mode').value === 'pvp' ? 2 : parseInt(byId('room-seats').value),
+            duration: parseInt(byId('room-duration').value),
+            props: byId('room-props').checked,
+            aiFill: byId('room-aifill').checked
+        });
+
+        byId('room-create')?.addEventListener('click', () => {
+            if (requireLogin()) this.sendMessage('room_create', { roomSettings: settings() });
+        });
+        byId('room-join')?.addEventListener('click', () => {
+            const code = byId('room-code').value.trim().toUpperCase();
+            if (code && requireLogin()) this.sendMessage('room_join', { code });
+        });
+        byId('room-code')?.addEventListener('keydown', (e) => {
+            if (e.key === 'Enter') byId('room-join').click();
+        });
+        byId('room-ready')?.addEventListener('click', () => this.sendMessage('room_ready'));
+        byId('room-start')?.addEventListener('click', () => this.sendMessage('room_start'));
+        byId('room-leave')?.addEventListener('click', () => this.sendMessage('room_leave'));
+        byId('roomCodeLabel')?.addEventListener('click', () => {
+            navigator.clipboard?.writeText(this.room?.code || '');
+            this.showTempMessage("Room code copied!");
+        });
+        byId('roomMembers')?.addEventListener('click', (e) => {
+            const target = e.target.dataset?.kick;
+            if (target) this.sendMessage('room_kick', { target: decodeURIComponent(target) });
+        });
+    }
+
+    handleRoom(room, error) {
+        this.room = room && room.code ? room : null;
+        const inRoom = !!this.room;
+        document.getElementById('roomLobby').classList.toggle('hidden', inRoom);
+        document.getElementById('roomInfo').classList.toggle('hidden', !inRoom);
+        document.getElementById('roomError').textContent = error || '';
+        if (!inRoom) return;
+
+        const me = this.currentUser ? this.currentUser.username : null;
+        const members = room.members || [];
+        const isHost = members.some(m => m.host && m.name === me);
+        const s = room.settings || {};
+        document.getElementById('roomCodeLabel').textContent = room.code;
+        document.getElementById('roomRules').textContent =
+            `${s.mode === 'pvp' ? '💀 PVP' : '⚔️ Battle'} · ${s.size} · ${s.duration}s · ${members.length}/${s.seats}` +
+            `${s.aiFill ? ' + 🤖' : ''}${s.props ? ' · 🎁' : ''}${room.playing ? ' · 🎮 playing' : ''}`;
+        document.getElementById('roomMembers').innerHTML = members.map(m => `
+            <span class="room-member ${m.ready ? 'ready' : ''}">
+                ${m.host ? '👑' : (m.ready ? '✅' : '⏳')} ${this.escapeHTML(m.name)}
+                ${isHost && !m.host && !room.playing ? `<button class="kick-btn" data-kick="${encodeURIComponent(m.name)}" title="Kick">✖</button>` : ''}
+            </span>
+        `).join('');
+        document.getElementById('room-ready').classList.toggle('hidden', isHost || room.playing);
+        document.getElementById('room-ready').classList.toggle('active', members.some(m => m.name === me && m.ready));
+        document.getElementById('room-start').classList.toggle('hidden', !isHost || room.playing);
+    }
+
+    setupSpectate() {
+        document.getElementById('live-refresh')?.addEventListener('click', () => this.sendMessage('spectate_list'));
+        document.getElementById('liveMatches')?.addEventListener('click', (e) => {
+            const id = e.target.dataset?.watch;
+            if (id) this.sendMessage('spectate', { code: id });
+        });
+        document.getElementById('watch-back')?.addEventListener('click', () => this.sendMessage('spectate_stop'));
+    }
+
+    renderLiveMatches(matches) {
+        const list = document.getElementById('liveMatches');
+        if (!list) return;
+        if (matches.length === 0) {
+            list.innerHTML = '<span class="live-empty">No live matches right now</span>';
+            return;
+        }
+        list.innerHTML = matches.map(m => `
+            <span class="live-match">
+                ${m.room ? '🏠' : '⚔️'} ${(m.players || []).map(n => this.escapeHTML(n)).join(' vs ')}
+                ${m.seats > (m.players || []).length ? ` + ${m.seats - (m.players || []).length} 🤖` : ''}
+                · ⏱️ ${m.timeLeft}s · 👀 ${m.spectators || 0}
+                <button class="mode-btn" data-watch="${this.escapeHTML(m.id)}">📺 Watch</button>
+            </span>
+        `).join('');
+    }
+
+    handleSpectate(msg) {
+        document.getElementById('liveError').textContent = msg.error || '';
+        if (msg.error) return;
+        if (msg.watching) {
+            this.watching = msg.watching;
+        } else if (msg.success && this.watching) {
+            // The match is over: keep the final board until we go back
+            this.watching.ended = true;
+        } else {
+            this.watching = null;
+        }
+
+        document.getElementById('watchInfo').classList.toggle('hidden', !this.watching);
+        if (!this.watching) return;
+        const w = this.watching;
+        document.getElementById('watchLabel').textContent = w.ended
+            ? `🏁 ${msg.success}`
+            : `👀 Watching ${w.room ? `room ${w.room}` : `match ${w.id}`}: ${(w.players || []).join(' vs ')}`;
+    }
+
+    setupRematch() {
+        document.querySelectorAll('.rematch-btn[data-best-of]').forEach(btn => {
+            btn.addEventListener('click', (e) => {
+                e.stopPropagation(); // The overlay click would restart
+                this.sendMessage('rematch', { mode: btn.dataset.bestOf });
+            });
+        });
+        document.getElementById('rematchDecline')?.addEventListener('click', (e) => {
+            e.stopPropagation();
+            this.sendMessage('rematch_decline');
+            this.rematch = { status: 'withdrawn' };
+            this.updateOverlay();
+        });
+    }
+
+    // A rematch is possible after a matchmaking game between two people,
+    // once any series they played is over
+    canRematch() {
+        const st = this.gameState;
+        const me = this.currentUser?.username;
+        return !!(st?.gameOver && st.mode === 'pvp' && !this.room && !this.watching && me &&
+            [st.p1Name, st.p2Name].includes(me) && st.p2Name !== '🤖 Bot' && !this.seriesRunning());
+    }
+
+    // Between two games of a series, which starts the next one by itself
+    seriesRunning() {
+        const series = this.gameState?.series;
+        return !!(series && !series.over && this.rematch?.status !== 'gone');
+    }
+
+    seriesSummary() {
+        const st = this.gameState;
+        const s = st.series;
+        const score = `${s.p1Wins || 0}-${s.p2Wins || 0}`;
+        if (this.rematch?.status === 'gone' && !s.over) {
+            return `🏳️ ${this.escapeHTML(this.rematch.from)} left the series (${score})`;
+        }
+        if (!s.over) return `🏅 Series ${score} · best of ${s.bestOf} · next game starting soon…`;
+        return s.winner
+            ? `🏅 ${this.escapeHTML(s.winner)} wins the series ${score}`
+            : `🤝 Series drawn ${score}`;
+    }
+
+    renderRematch(show) {
+        const panel = document.getElementById('rematchPanel');
+        if (!panel) return;
+        panel.classList.toggle('hidden', !show);
+        if (!show) return;
+
+        const r = this.rematch || {};
+        const from = this.escapeHTML(r.from || '');
+        const kind = r.bestOf > 1 ? `best of ${r.bestOf}` : 'rematch';
+        const texts = {
+            offer: `🔁 ${from} wants a ${kind}`,
+            sent: `⏳ Waiting for ${from} to accept the ${kind}…`,
+            declined: `✖ ${from} declined`,
+            gone: `👋 ${from} is gone`,
+            unavailable: 'Rematch no longer possible',
+            withdrawn: 'Rematch declined',
+        };
+        document.getElementById('rematchStatus').innerHTML = texts[r.status] || 'Play the same opponent again?';
+
+        const closed = ['declined', 'gone', 'unavailable', 'withdrawn'].includes(r.status);
+        panel.querySelectorAll('.rematch-btn[data-best-of]').forEach(btn => {
+            btn.classList.toggle('hidden', closed);
+            btn.classList.toggle('offered', r.status === 'offer' && Number(btn.dataset.bestOf) === r.bestOf);
+        });
+        document.getElementById('rematchDecline').classList.toggle('hidden', !['offer', 'sent'].includes(r.status));
+    }
+
+    // Leaves a finished game for a new solo one and declines any rematch.
+    // Not between the games of a series, which goes on by itself.
+    restart() {
+        if (this.seriesRunning()) return;
+        if (this.canRematch()) this.sendMessage('rematch_decline');
+        this.sendMessage('restart');
+    }
+
     showTempMessage(msg) {
         this.currentMessage = msg;
         this.messageStartTime = Date.now();
@@ -1062,10 +1479,10 @@ export class SnakeGameClient {
         el.classList.toggle('connected', status === 'connected');
     }
 
-    updatePlayerCount(count) {
+    updatePlayerCount(count, searching = 0) {
         const el = document.getElementById('totalPlayers');
         if (el) {
-            el.textContent = `👥 ${count} Player${count !== 1 ? 's' : ''}`;
+            el.textContent = `👥 ${count} Player${count !== 1 ? 's' : ''}` + (searching > 0 ? ` · 🔍 ${searching} searching` : '');
         }
     }
 
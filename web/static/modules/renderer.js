@@ -89,11 +89,36 @@ export class GameRenderer {
             });
         }
 
+        // Draw extra snakes (free-for-all AI, room players); colours follow the server's seat colours
+        if (gameState.opponents) {
+            const palette = [['#ed8936', '#f6ad55'], ['#4299e1', '#90cdf4'], ['#ed64a6', '#fbb6ce'], ['#38b2ac', '#81e6d9'], ['#ecc94b', '#faf089'], ['#e53e3e', '#feb2b2']];
+            gameState.opponents.forEach((opp, n) => {
+                const [headColor, bodyColor] = palette[n % palette.length];
+                const isLocal = clientUsername && opp.name === clientUsername;
+                (opp.snake || []).forEach((segment, index) => {
+                    if (index === 0) {
+                        this.ctx.fillStyle = opp.stunned ? '#718096' : headColor;
+                        this.drawCell(segment.x, segment.y);
+                        this.drawEyes(segment.x, segment.y, true, opp.stunned);
+                        if (isLocal) this.drawYouIndicator(segment.x, segment.y, headColor);
+                    } else {
+                        this.ctx.fillStyle = opp.stunned ? '#a0aec0' : bodyColor;
+                        this.drawCell(segment.x, segment.y);
+                    }
+                });
+            });
+        }
+
         // Draw fireballs
         if (gameState.fireballs) {
             gameState.fireballs.forEach(fb => this.drawFireball(fb));
         }
 
+        // AI debug overlay (toggled with I)
+        if (gameState.aiDebug && gameState.aiDebug.length > 0) {
+            this.drawAIDebug(gameState);
+        }
+
         // Draw effects
         explosions.forEach(exp => this.drawExplosion(exp));
         this.drawConfetti(confetti);
@@ -114,6 +139,71 @@ export class GameRenderer {
         this.drawCanvasMessage(currentMessage, messageStartTime, messageType);
     }
 
+    // drawAIDebug shows each AI's target, the flood-fill space and score of
+    // every direction it weighed, and its intent above its head
+    drawAIDebug(gameState) {
+        const intentColors = { HUNT: '#f6e05e', SURVIVE: '#fc8181', HARASS: '#f687b3', ATTACK: '#f56565', IDLE: '#a0aec0' };
+        const half = this.cellSize / 2;
+        const bodyOf = (idx) => {
+            if (idx === 0) return gameState.snake;
+            if (idx === 1) return gameState.aiSnake;
+            return (gameState.opponents || [])[idx - 2]?.snake;
+        };
+
+        this.ctx.save();
+        this.ctx.textAlign = 'center';
+        this.ctx.textBaseline = 'middle';
+        gameState.aiDebug.forEach(d => {
+            const body = bodyOf(d.player || 0);
+            if (!body || body.length === 0) return;
+            const head = body[0];
+            const color = intentColors[d.intent] || '#fff';
+            const hx = head.x * this.cellSize + half;
+            const hy = head.y * this.cellSize + half;
+
+            // Target: dashed line from the head and a ring on the tile
+            if (d.target) {
+                const tx = d.target.x * this.cellSize + half;
+                const ty = d.target.y * this.cellSize + half;
+                this.ctx.strokeStyle = color;
+                this.ctx.lineWidth = 2;
+                this.ctx.setLineDash([4, 4]);
+                this.ctx.beginPath();
+                this.ctx.moveTo(hx, hy);
+                this.ctx.lineTo(tx, ty);
+                this.ctx.stroke();
+                this.ctx.setLineDash([]);
+                this.ctx.beginPath();
+                this.ctx.arc(tx, ty, half, 0, Math.PI * 2);
+                this.ctx.stroke();
+            }
+
+            // Candidates: flood-fill size on each neighbour, best one highlighted
+            const candidates = d.candidates || [];
+            let best = null;
+            candidates.forEach(c => {
+                if (c.safe && (!best || c.score > best.score)) best = c;
+            });
+            this.ctx.font = `bold ${Math.floor(this.cellSize * 0.45)}px monospace`;
+            candidates.forEach(c => {
+                const x = (head.x + (c.dir?.x || 0)) * this.cellSize;
+                const y = (head.y + (c.dir?.y || 0)) * this.cellSize;
+                this.ctx.fillStyle = c === best ? 'rgba(72, 187, 120, 0.45)' : c.safe ? 'rgba(0, 0, 0, 0.45)' : 'rgba(245, 101, 101, 0.45)';
+                this.ctx.fillRect(x, y, this.cellSize, this.cellSize);
+                this.ctx.fillStyle = '#fff';
+                this.ctx.fillText(c.safe ? String(c.space || 0) : '✕', x + half, y + half);
+            });
+
+            // Intent and urgency above the head
+            let label = d.intent || '';
+            if (d.urgency > 0) label += ` ${Math.round(d.urgency * 100)}%`;
+            this.ctx.font = `bold ${Math.floor(this.cellSize * 0.5)}px Inter, sans-serif`;
+            this.ctx.fillStyle = color;
+            this.ctx.fillText(label, hx, hy - this.cellSize * 1.2);
+        });
+        this.ctx.restore();
+    }
+
     drawYouIndicator(x, y, color) {
         const bounce = Math.sin(Date.now() * 0.01) * 3;
         const centerX = x * this.cellSize + this.cellSize / 2;